@@ -0,0 +1,94 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Accessor is a typed getter of an instance. The instance is retrieved from the container on the first call to Get,
+// and later calls return the cached value without reflection or map lookups. It is safe for concurrent use.
+//
+// Accessors are usually created once for the most frequently accessed instances:
+//
+//	var userService = alice.NewAccessor[*UserService](container, "UserService")
+//
+//	func handle() {
+//		userService.Get().Serve()
+//	}
+//
+// The cached value is dropped when the container created by CreateContainer resets any instance, so Get retrieves it
+// again. An accessor is bound to its container, so it is not updated by Reload; code using a Handle should create
+// accessors of the current container instead.
+type Accessor[T any] struct {
+	c       Container
+	resolve func() interface{}
+	// resets is the number of resets of the container, or nil if it can't be reset.
+	resets *atomic.Uint64
+
+	mu     sync.Mutex
+	cached atomic.Pointer[accessedValue[T]]
+}
+
+// accessedValue is a value cached by an Accessor, with the number of resets of the container when it is retrieved.
+type accessedValue[T any] struct {
+	value  T
+	resets uint64
+}
+
+// NewAccessor creates an accessor of the instance with the specified name.
+func NewAccessor[T any](c Container, name string) *Accessor[T] {
+	return &Accessor[T]{
+		c: c,
+		resolve: func() interface{} {
+			return c.InstanceByName(name)
+		},
+		resets: resetsOf(c),
+	}
+}
+
+// NewTypedAccessor creates an accessor of the instance with type T.
+func NewTypedAccessor[T any](c Container) *Accessor[T] {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return &Accessor[T]{
+		c: c,
+		resolve: func() interface{} {
+			return c.Instance(t)
+		},
+		resets: resetsOf(c),
+	}
+}
+
+// resetsOf returns the reset counter of a container, or nil if it is not created by CreateContainer.
+func resetsOf(c Container) *atomic.Uint64 {
+	if ic, ok := c.(*container); ok {
+		return &ic.resets
+	}
+	return nil
+}
+
+// Get returns the instance. It panics if the instance is not found or it is not of type T.
+func (a *Accessor[T]) Get() T {
+	var resets uint64
+	if a.resets != nil {
+		resets = a.resets.Load()
+	}
+	if cached := a.cached.Load(); cached != nil && cached.resets == resets {
+		return cached.value
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if cached := a.cached.Load(); cached != nil && cached.resets == resets {
+		return cached.value
+	}
+	instance := a.resolve()
+	value, ok := instance.(T)
+	if !ok {
+		panic(formatContainerError(a.c, fmt.Errorf("instance of type %T is not a %s", instance,
+			reflect.TypeOf((*T)(nil)).Elem())))
+	}
+	a.cached.Store(&accessedValue[T]{value: value, resets: resets})
+	return value
+}
@@ -0,0 +1,57 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAccessor(t *testing.T) {
+	c := CreateContainer(&M1{}, &M2{}, &M3{}, &M4{}, &M5{})
+
+	d1 := NewAccessor[D1](c, "D1")
+	expectedD1 := &D1Impl{}
+	if !reflect.DeepEqual(d1.Get(), expectedD1) {
+		t.Errorf("bad instance from Get(): got %v, expected %v", d1.Get(), expectedD1)
+	}
+
+	d5 := NewTypedAccessor[*D5Impl](c)
+	expectedD5 := &D5Impl{}
+	if !reflect.DeepEqual(d5.Get(), expectedD5) {
+		t.Errorf("bad instance from Get(): got %v, expected %v", d5.Get(), expectedD5)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		d1.Get()
+	})
+	if allocs != 0 {
+		t.Errorf("bad allocations of Get(): got %v, expected 0", allocs)
+	}
+}
+
+func TestAccessor_Reset(t *testing.T) {
+	c := CreateContainer(&freshModule{}, &ResetConsumerModule{})
+	d1 := NewAccessor[D1](c, "D1")
+	consumer := NewTypedAccessor[*D1Consumer](c)
+	old := d1.Get()
+	consumer.Get()
+	c.(Rebuilder).Reset("D1")
+	if d1.Get() == old || d1.Get() != c.InstanceByName("D1") {
+		t.Errorf("bad instance from Get() after Reset(): got %v, expected %v", d1.Get(), c.InstanceByName("D1"))
+	}
+	if consumer.Get().D1 != d1.Get() {
+		t.Errorf("bad dependant from Get() after Reset(): got %v, expected %v", consumer.Get().D1, d1.Get())
+	}
+}
+
+func TestAccessor_PanicOnTypeMismatch(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected panic for Get() on type mismatch")
+		} else {
+			t.Log(r)
+		}
+	}()
+
+	c := CreateContainer(&M1{})
+	NewAccessor[D2](c, "D1").Get()
+}
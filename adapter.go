@@ -0,0 +1,137 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithAdapters returns an option which registers adapters converting instances to the types they are not provided
+// as, e.g. func(*zap.Logger) *slog.Logger. An adapter is a function with 1 parameter and 1 return value. It is
+// consulted when a dependency associated by type, as a field or parameter, is not provided, but an instance of the
+// parameter type is. Adapters are not chained, and at most one is registered per return type.
+//
+// The adapter is called once per container, when the adapted dependency is first injected, and the result is shared
+// by all consumers, like an instance. Retrieving the type from the container doesn't adapt it. Container.Explain
+// reports the adaptation.
+func WithAdapters(adapters ...interface{}) Option {
+	return func(o *options) {
+		o.adapters = append(o.adapters, adapters...)
+	}
+}
+
+// adapter is a validated adapter.
+type adapter struct {
+	from reflect.Type
+	fn   reflect.Value
+}
+
+// newAdapters validates the adapters and indexes them by the types they convert to.
+func newAdapters(adapters []interface{}) (map[reflect.Type]*adapter, error) {
+	indexed := make(map[reflect.Type]*adapter)
+	var errs []error
+	for _, a := range adapters {
+		v := reflect.ValueOf(a)
+		if v.Kind() != reflect.Func || v.Type().NumIn() != 1 || v.Type().NumOut() != 1 {
+			errs = append(errs, fmt.Errorf("adapter %T is not a function with 1 parameter and 1 return value", a))
+			continue
+		}
+		to := v.Type().Out(0)
+		if _, ok := indexed[to]; ok {
+			errs = append(errs, fmt.Errorf("multiple adapters to type %s", typeName(to)))
+			continue
+		}
+		indexed[to] = &adapter{from: v.Type().In(0), fn: v}
+	}
+	return indexed, joinErrors(errs)
+}
+
+// createAdaptedDependency creates the dependency of a module on the provider of the type an adapter converts from.
+func (g *graph) createAdaptedDependency(rm *reflectedModule, to reflect.Type, a *adapter,
+	typeToProvidersMap map[reflect.Type][]*reflectedModule) error {
+	if !providesType(typeToProvidersMap, a.from, g.options.strict) {
+		return fmt.Errorf("dependency type %s.%s is not found, nor is type %s it is adapted from", rm.name,
+			to.Name(), typeName(a.from))
+	}
+	g.adapted[to] = a
+	return g.createDependencyByType(rm, a.from, typeToProvidersMap)
+}
+
+// providesType reports whether an instance of type t, or assignable to it unless in strict mode, is provided.
+func providesType(typeToProvidersMap map[reflect.Type][]*reflectedModule, t reflect.Type, strict bool) bool {
+	if _, ok := typeToProvidersMap[t]; ok || strict {
+		return ok
+	}
+	for provided := range typeToProvidersMap {
+		if provided.AssignableTo(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// adaptation is a dependency adapted by an adapter.
+type adaptation struct {
+	// done is closed when the adapter returns.
+	done chan struct{}
+	// instance is the adapted instance.
+	instance interface{}
+	// recovered is the value recovered if the adapter or the resolution of its parameter panics.
+	recovered interface{}
+}
+
+// adaptedInstance returns the instance of type t adapted from the instance of the adapter parameter type, calling the
+// adapter on first use. Concurrent resolutions wait for the first one, so the adapter is called once.
+func (c *container) adaptedInstance(t reflect.Type, a *adapter) interface{} {
+	key := "adapted " + typeName(t)
+	c.mu.Lock()
+	ad, ok := c.adaptedByType[t]
+	if !ok {
+		ad = &adaptation{done: make(chan struct{})}
+		c.adaptedByType[t] = ad
+	}
+	c.mu.Unlock()
+
+	if ok {
+		c.await(key, ad.done)
+	} else {
+		func() {
+			defer close(ad.done)
+			defer func() {
+				ad.recovered = recover()
+			}()
+			c.constructions.construct(key, func() {
+				from := c.findInstanceByType(a.from)
+				ad.instance = a.fn.Call([]reflect.Value{instanceValue(from, a.from)})[0].Interface()
+			})
+		}()
+	}
+	if ad.recovered != nil {
+		panic(ad.recovered)
+	}
+	return ad.instance
+}
+
+// resetAdaptations removes the adapted dependencies whose adapters take any of the instances being reset, so they are
+// adapted again from the new instances. The caller must hold the lock.
+func (c *container) resetAdaptations(resetNames map[string]bool) {
+	for t, a := range c.graph.adapted {
+		for name := range resetNames {
+			if im := findInstanceMethodInModules(c.reflected, name); im != nil && im.tp.AssignableTo(a.from) {
+				delete(c.adaptedByType, t)
+				break
+			}
+		}
+	}
+}
+
+// explainAdaptation writes how a dependency is adapted, with the indented explanation of the type it is adapted from.
+func (c *container) explainAdaptation(b *strings.Builder, a *adapter) {
+	fmt.Fprintf(b, "  not provided, adapted from type %s by the registered adapter\n", typeName(a.from))
+	for _, line := range strings.SplitAfter(c.Explain(a.from), "\n") {
+		if line != "" {
+			fmt.Fprintf(b, "  %s", line)
+		}
+	}
+	fmt.Fprintf(b, "result: adapted from type %s\n", typeName(a.from))
+}
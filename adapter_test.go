@@ -0,0 +1,136 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type legacyClient struct {
+	addr string
+}
+
+type modernClient struct {
+	legacy *legacyClient
+}
+
+type clientConsumerModule struct {
+	BaseModule
+	Client *modernClient `alice:""`
+}
+
+func (m *clientConsumerModule) Consumer() *D5Impl {
+	return &D5Impl{}
+}
+
+func TestWithAdapters(t *testing.T) {
+	calls := 0
+	adapt := func(legacy *legacyClient) *modernClient {
+		calls++
+		return &modernClient{legacy: legacy}
+	}
+	legacy := &legacyClient{addr: "localhost"}
+	var adapted *modernClient
+	consumer := &clientConsumerModule{}
+	modules := []Module{
+		NewModule("legacy").Provide("Legacy", func() *legacyClient { return legacy }).Build(),
+		consumer,
+		NewModule("params").Provide("Param", func(c *modernClient) *D3Impl {
+			adapted = c
+			return &D3Impl{}
+		}).Build(),
+	}
+	c := CreateContainerWithOptions(modules, WithAdapters(adapt))
+
+	if consumer.Client == nil || consumer.Client.legacy != legacy {
+		t.Errorf("bad field after CreateContainer(): got %v, expected adapted from %v", consumer.Client, legacy)
+	}
+	if adapted != consumer.Client || calls != 1 {
+		t.Errorf("bad adapter calls after CreateContainer(): got %d, expected %d", calls, 1)
+	}
+	explanation := c.(Introspector).Explain(reflect.TypeOf(&modernClient{}))
+	if !strings.Contains(explanation, "alice.legacyClient by the registered adapter") ||
+		!strings.Contains(explanation, "result: adapted from type") {
+		t.Errorf("bad explanation after Explain(): got %v, expected the adaptation", explanation)
+	}
+}
+
+func TestWithAdapters_Reset(t *testing.T) {
+	n := 0
+	consumer := &clientConsumerModule{}
+	modules := []Module{
+		NewModule("legacy").Provide("Legacy", func() *legacyClient {
+			n++
+			return &legacyClient{addr: fmt.Sprint(n)}
+		}).Build(),
+		consumer,
+	}
+	adapt := func(legacy *legacyClient) *modernClient {
+		return &modernClient{legacy: legacy}
+	}
+	c := CreateContainerWithOptions(modules, WithAdapters(adapt))
+	c.(Rebuilder).Reset("Legacy")
+	if addr := consumer.Client.legacy.addr; addr != "2" {
+		t.Errorf("bad adapted dependency after Reset(): got adapted from %s, expected from %s", addr, "2")
+	}
+}
+
+func TestWithAdapters_Concurrent(t *testing.T) {
+	var calls int32
+	adapt := func(legacy *legacyClient) *modernClient {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(time.Millisecond)
+		return &modernClient{legacy: legacy}
+	}
+	var modules []Module
+	modules = append(modules,
+		NewModule("legacy").Provide("Legacy", func() *legacyClient { return &legacyClient{} }).Build())
+	for i := 0; i < 4; i++ {
+		modules = append(modules, NewModule(fmt.Sprint("consumer", i)).
+			Provide(fmt.Sprint("Consumer", i), func(c *modernClient) string { return c.legacy.addr }).
+			Build())
+	}
+	c := CreateContainerWithOptions(modules, WithLazy(), WithAdapters(adapt))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.InstanceByName(fmt.Sprint("Consumer", i))
+		}(i)
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("bad adapter calls after concurrent InstanceByName(): got %d, expected %d", calls, 1)
+	}
+}
+
+func TestWithAdapters_Error(t *testing.T) {
+	cases := []struct {
+		adapters []interface{}
+		expected string
+	}{
+		{[]interface{}{"adapter"}, "adapter string is not a function with 1 parameter and 1 return value"},
+		{
+			[]interface{}{
+				func(*legacyClient) *modernClient { return nil },
+				func(*D5Impl) *modernClient { return nil },
+			},
+			"multiple adapters to type",
+		},
+		{
+			[]interface{}{func(*D3Impl) *modernClient { return nil }},
+			"alice.D3Impl it is adapted from",
+		},
+	}
+	for _, tc := range cases {
+		err := ValidateWithOptions([]Module{&clientConsumerModule{}}, WithAdapters(tc.adapters...))
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("bad error after ValidateWithOptions(): got %v, expected %v", err, tc.expected)
+		}
+	}
+}
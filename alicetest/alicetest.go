@@ -0,0 +1,199 @@
+// Package alicetest provides helpers to use alice containers in tests.
+package alicetest
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/magic003/alice"
+)
+
+const _Tag = "alice"
+
+// Env builds a container once and injects its instances into test-local structs. It is usually defined as a package
+// level variable in a test file, so the container is shared by all tests of the package:
+//
+//	var env = alicetest.New(&ConfigModule{}, &PersistModule{})
+//
+//	func TestDao(t *testing.T) {
+//		var deps struct {
+//			Dao persist.WebPageDao `alice:""`
+//		}
+//		env.Inject(t, &deps)
+//		...
+//	}
+type Env struct {
+	modules []alice.Module
+	opts    []alice.Option
+	mocks   []MockBinding
+
+	once      sync.Once
+	c         alice.Container
+	recovered interface{}
+}
+
+// New creates an Env with the specified modules. The container is built on first use. Modules implementing
+// alice.TestVariantModule are replaced by their test variants, like a fake clock.
+func New(modules ...alice.Module) *Env {
+	return NewWithOptions(modules)
+}
+
+// NewWithOptions creates an Env with the specified modules and container options. The container is built on first
+// use.
+func NewWithOptions(modules []alice.Module, opts ...alice.Option) *Env {
+	return &Env{
+		modules: modules,
+		opts:    opts,
+	}
+}
+
+// testVariants replaces the modules implementing alice.TestVariantModule by their test variants.
+func testVariants(modules []alice.Module) []alice.Module {
+	variants := make([]alice.Module, len(modules))
+	for i, m := range modules {
+		if tm, ok := m.(alice.TestVariantModule); ok {
+			m = tm.TestVariant()
+		}
+		variants[i] = m
+	}
+	return variants
+}
+
+// Container returns the container, building it if necessary. It fails the test if the container could not be built.
+func (e *Env) Container(t testing.TB) alice.Container {
+	t.Helper()
+	e.once.Do(func() {
+		defer func() {
+			e.recovered = recover()
+		}()
+		modules, opts := testVariants(e.modules), e.opts
+		if len(e.mocks) > 0 {
+			mocks, policy := mocksModule(e.mocks)
+			modules = append(modules, mocks)
+			opts = append(append([]alice.Option{}, opts...), policy)
+			if err := checkShadowedProviders(modules, opts, e.mocks); err != nil {
+				panic(err)
+			}
+		}
+		e.c = alice.CreateContainerWithOptions(modules, opts...)
+	})
+	if e.recovered != nil {
+		t.Fatalf("failed to create container: %v", e.recovered)
+	}
+	return e.c
+}
+
+// Inject populates the fields of deps from the container. See the package level Inject function for details.
+func (e *Env) Inject(t testing.TB, deps interface{}, overrides ...Override) {
+	t.Helper()
+	Inject(t, e.Container(t), deps, overrides...)
+}
+
+// Override replaces an instance injected into a test struct. It only affects the struct, not the instances in the
+// container which depend on the replaced one.
+type Override struct {
+	name     string
+	tp       reflect.Type
+	instance interface{}
+}
+
+// OverrideName overrides the fields associated with the specified name.
+func OverrideName(name string, instance interface{}) Override {
+	return Override{name: name, instance: instance}
+}
+
+// OverrideType overrides the fields associated with the specified type.
+func OverrideType(t reflect.Type, instance interface{}) Override {
+	return Override{tp: t, instance: instance}
+}
+
+// Inject populates the fields of deps, which must be a pointer of struct, from the container. Fields are tagged the
+// same way as module fields: `alice:""` associates the field by type and `alice:"Name"` by name. Untagged fields are
+// left untouched, except embedded structs whose fields are populated as well. The fields are reset to zero values
+// when the test finishes, so instances don't leak across tests.
+func Inject(t testing.TB, c alice.Container, deps interface{}, overrides ...Override) {
+	t.Helper()
+	v := reflect.ValueOf(deps)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		t.Fatalf("deps %T is not a pointer of struct", deps)
+	}
+
+	injected := injectFields(t, c, v.Elem(), overrides)
+	t.Cleanup(func() {
+		for _, fv := range injected {
+			fv.Set(reflect.Zero(fv.Type()))
+		}
+	})
+}
+
+// injectFields populates the tagged fields of a struct value and the structs embedded in it, allocating the nil
+// pointers of embedded structs. It returns the populated fields.
+func injectFields(t testing.TB, c alice.Container, v reflect.Value, overrides []Override) []reflect.Value {
+	t.Helper()
+	var injected []reflect.Value
+	st := v.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		name, exists := field.Tag.Lookup(_Tag)
+		if !exists {
+			if !field.Anonymous {
+				continue
+			}
+			embedded := settable(v.Field(i))
+			if embedded.Kind() == reflect.Ptr && embedded.Type().Elem().Kind() == reflect.Struct {
+				if embedded.IsNil() {
+					embedded.Set(reflect.New(embedded.Type().Elem()))
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				injected = append(injected, injectFields(t, c, embedded, overrides)...)
+			}
+			continue
+		}
+
+		instance, err := resolve(c, name, field.Type, overrides)
+		if err != nil {
+			t.Fatalf("failed to inject field %s.%s: %s", st.Name(), field.Name, err.Error())
+		}
+		fv := settable(v.Field(i))
+		iv := reflect.ValueOf(instance)
+		if !iv.Type().AssignableTo(field.Type) {
+			t.Fatalf("failed to inject field %s.%s: type %s is not assignable to %s",
+				st.Name(), field.Name, iv.Type(), field.Type)
+		}
+		fv.Set(iv)
+		injected = append(injected, fv)
+	}
+	return injected
+}
+
+// resolve finds the instance for a field, preferring the overrides.
+func resolve(c alice.Container, name string, t reflect.Type, overrides []Override) (instance interface{}, err error) {
+	for _, o := range overrides {
+		if (name != "" && o.name == name) || (name == "" && o.tp == t) {
+			return o.instance, nil
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	if name != "" {
+		return c.MustInstanceByName(name), nil
+	}
+	return c.MustInstance(t), nil
+}
+
+// settable returns a settable value of an addressable field, allowing unexported fields.
+func settable(field reflect.Value) reflect.Value {
+	if field.CanSet() {
+		return field
+	}
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+}
@@ -0,0 +1,126 @@
+package alicetest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/magic003/alice"
+	"github.com/magic003/alice/clock"
+	"github.com/magic003/alice/random"
+)
+
+type Greeter interface {
+	Greet() string
+}
+
+type greeter struct {
+	name string
+}
+
+func (g *greeter) Greet() string {
+	return "hello " + g.name
+}
+
+type GreeterModule struct {
+	alice.BaseModule
+}
+
+func (m *GreeterModule) Name() string {
+	return "alice"
+}
+
+func (m *GreeterModule) Greeter() Greeter {
+	return &greeter{name: "alice"}
+}
+
+var env = New(&GreeterModule{})
+
+func TestInject(t *testing.T) {
+	var deps struct {
+		Greeter Greeter `alice:""`
+		name    string  `alice:"Name"`
+		other   string
+	}
+
+	t.Run("inject", func(t *testing.T) {
+		env.Inject(t, &deps)
+		if deps.Greeter.Greet() != "hello alice" {
+			t.Errorf("bad Greeter after Inject(): got %s, expected %s", deps.Greeter.Greet(), "hello alice")
+		}
+		if deps.name != "alice" {
+			t.Errorf("bad name after Inject(): got %s, expected %s", deps.name, "alice")
+		}
+	})
+
+	if deps.Greeter != nil || deps.name != "" {
+		t.Errorf("fields are expected to be reset after test, got %v", deps)
+	}
+}
+
+type greeterDeps struct {
+	Greeter Greeter `alice:""`
+}
+
+func TestInject_Embedded(t *testing.T) {
+	var deps struct {
+		greeterDeps
+		*namedDeps
+	}
+
+	t.Run("inject", func(t *testing.T) {
+		env.Inject(t, &deps)
+		if deps.Greeter == nil || deps.name != "alice" {
+			t.Errorf("bad promoted fields after Inject(): got %v and %q", deps.Greeter, deps.name)
+		}
+	})
+
+	if deps.Greeter != nil || deps.name != "" {
+		t.Errorf("promoted fields are expected to be reset after test, got %v and %q", deps.Greeter, deps.name)
+	}
+}
+
+type namedDeps struct {
+	name string `alice:"Name"`
+}
+
+func TestInject_Override(t *testing.T) {
+	var deps struct {
+		Greeter Greeter `alice:""`
+		Name    string  `alice:"Name"`
+	}
+	fake := &greeter{name: "fake"}
+
+	env.Inject(t, &deps,
+		OverrideType(reflect.TypeOf((*Greeter)(nil)).Elem(), fake),
+		OverrideName("Name", "bob"))
+	if deps.Greeter != fake {
+		t.Errorf("bad Greeter after Inject(): got %v, expected %v", deps.Greeter, fake)
+	}
+	if deps.Name != "bob" {
+		t.Errorf("bad Name after Inject(): got %s, expected %s", deps.Name, "bob")
+	}
+
+	// the container is shared
+	if env.Container(t) != env.Container(t) {
+		t.Error("container is expected to be built once")
+	}
+}
+
+func TestEnv_TestVariants(t *testing.T) {
+	env := New(clock.NewModule(), random.NewModule())
+	var deps struct {
+		Clock clock.Clock `alice:""`
+	}
+	env.Inject(t, &deps)
+	fake, ok := deps.Clock.(*clock.Fake)
+	if !ok {
+		t.Fatalf("bad clock after Inject(): got %T, expected *clock.Fake", deps.Clock)
+	}
+	if !fake.Now().Equal(clock.Epoch) {
+		t.Errorf("bad time after Inject(): got %s, expected %s", fake.Now(), clock.Epoch)
+	}
+	source := env.Container(t).InstanceByName("Source")
+	if source.(interface{ Uint64() uint64 }).Uint64() != random.Seeded(random.Seed).Uint64() {
+		t.Error("bad source after Container(): got a source not seeded by random.Seed")
+	}
+}
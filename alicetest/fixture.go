@@ -0,0 +1,43 @@
+package alicetest
+
+import (
+	"reflect"
+
+	"github.com/magic003/alice"
+)
+
+// FixtureBuilder builds a container backed by a map of instances, for unit tests of code taking a container without
+// real modules:
+//
+//	c := alicetest.Fixture().
+//		With("DB", fakeDB).
+//		WithType(new(Clock), fixedClock).
+//		Build()
+type FixtureBuilder struct {
+	overrides []alice.Override
+}
+
+// Fixture creates a builder of a fixture container.
+func Fixture() *FixtureBuilder {
+	return &FixtureBuilder{}
+}
+
+// With adds an instance retrieved by the specified name.
+func (b *FixtureBuilder) With(name string, instance interface{}) *FixtureBuilder {
+	b.overrides = append(b.overrides, alice.OverrideName(name, instance))
+	return b
+}
+
+// WithType adds an instance retrieved by type. ptr is a pointer of the type, usually created by new, so interface
+// types could be specified.
+func (b *FixtureBuilder) WithType(ptr interface{}, instance interface{}) *FixtureBuilder {
+	b.overrides = append(b.overrides, alice.OverrideType(reflect.TypeOf(ptr).Elem(), instance))
+	return b
+}
+
+// Build returns the container. Retrieving an instance not added panics with *alice.LookupError, like a real
+// container. The container has no modules, so the other methods behave as on an empty container.
+func (b *FixtureBuilder) Build() alice.Container {
+	empty := alice.CreateContainer()
+	return empty.(alice.Scoper).Fork(b.overrides...)
+}
@@ -0,0 +1,28 @@
+package alicetest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/magic003/alice"
+)
+
+func TestFixture(t *testing.T) {
+	g := &greeter{name: "fixture"}
+	c := Fixture().
+		With("Name", "fixture").
+		WithType(new(Greeter), g).
+		Build()
+
+	if name := c.InstanceByName("Name"); name != "fixture" {
+		t.Errorf("bad instance by name from fixture: got %v, expected %v", name, "fixture")
+	}
+	greeterType := reflect.TypeOf((*Greeter)(nil)).Elem()
+	if instance := c.Instance(greeterType); instance != g {
+		t.Errorf("bad instance by type from fixture: got %v, expected %v", instance, g)
+	}
+	if _, err := c.ResolveByName("Other"); !errors.Is(err, alice.ErrNotFound) {
+		t.Errorf("bad error from fixture on name not added: got %v, expected %v", err, alice.ErrNotFound)
+	}
+}
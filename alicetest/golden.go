@@ -0,0 +1,39 @@
+package alicetest
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/magic003/alice"
+)
+
+// UpdateGoldenEnv is the environment variable which makes AssertFingerprint update the golden files instead of
+// comparing with them, e.g. "ALICE_UPDATE_GOLDEN=1 go test ./...".
+const UpdateGoldenEnv = "ALICE_UPDATE_GOLDEN"
+
+// AssertFingerprint fails the test if the fingerprint of the container differs from the one stored in the golden
+// file, so wiring changes must be approved by updating the file. The container must implement alice.Introspector.
+func AssertFingerprint(t testing.TB, c alice.Container, golden string) {
+	t.Helper()
+	in, ok := c.(alice.Introspector)
+	if !ok {
+		t.Fatalf("container %T doesn't implement alice.Introspector", c)
+	}
+	fingerprint := in.Fingerprint()
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.WriteFile(golden, []byte(fingerprint+"\n"), 0644); err != nil {
+			t.Fatalf("failed to update golden file %s: %s", golden, err.Error())
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s: %s", golden, err.Error())
+	}
+	if fingerprint != strings.TrimSpace(string(expected)) {
+		t.Errorf("wiring changed: fingerprint %s doesn't match golden file %s. Run with %s=1 to approve the change.",
+			fingerprint, golden, UpdateGoldenEnv)
+	}
+}
@@ -0,0 +1,24 @@
+package alicetest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/magic003/alice"
+)
+
+func TestAssertFingerprint(t *testing.T) {
+	golden := filepath.Join(t.TempDir(), "wiring.golden")
+	c := env.Container(t)
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	AssertFingerprint(t, c, golden)
+	content, err := os.ReadFile(golden)
+	if err != nil || string(content) != c.(alice.Introspector).Fingerprint()+"\n" {
+		t.Errorf("bad golden file after update: got %q, err %v", content, err)
+	}
+
+	t.Setenv(UpdateGoldenEnv, "")
+	AssertFingerprint(t, c, golden)
+}
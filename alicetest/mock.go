@@ -0,0 +1,116 @@
+package alicetest
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/magic003/alice"
+)
+
+// _MocksModule is the name of the module providing the mocks.
+const _MocksModule = "mocks"
+
+// MockBinding binds a mock, usually generated by tools like gomock or mockery, to an interface in a test container.
+type MockBinding struct {
+	tp        reflect.Type
+	mock      interface{}
+	shadowing bool
+}
+
+// Mock binds the mock to the interface I. The mock is provided as an instance named after the interface, so modules
+// could depend on it by type or by name:
+//
+//	ctrl := gomock.NewController(t)
+//	env := alicetest.NewWithMocks([]alice.Module{&OrderModule{}}, alicetest.Mock[Store](NewMockStore(ctrl)))
+//
+// It panics if I is not an interface.
+func Mock[I any](mock I) MockBinding {
+	tp := reflect.TypeOf((*I)(nil)).Elem()
+	if tp.Kind() != reflect.Interface {
+		panic(fmt.Errorf("mocked type %s is not an interface", tp))
+	}
+	return MockBinding{tp: tp, mock: mock}
+}
+
+// Shadowing returns a copy of the binding which replaces a real provider of the same name, or of a type assignable to
+// the interface. Without it, a mock shadowing a real provider fails the test, as it is usually a mistake.
+func (b MockBinding) Shadowing() MockBinding {
+	b.shadowing = true
+	return b
+}
+
+// NewWithMocks creates an Env with the specified modules and mocks. As mocks are usually created per test, so is the
+// Env. Modules implementing alice.TestVariantModule are replaced by their test variants as in New.
+func NewWithMocks(modules []alice.Module, mocks ...MockBinding) *Env {
+	e := New(modules...)
+	e.mocks = mocks
+	return e
+}
+
+// mocksModule returns the module providing the mocks, and the option resolving the conflicts between the mocks and
+// the real providers of the same names or types. Other conflicts are left to the conflict policies of the test.
+func mocksModule(mocks []MockBinding) (alice.Module, alice.Option) {
+	b := alice.NewModule(_MocksModule)
+	byName := make(map[string]MockBinding)
+	for _, m := range mocks {
+		mock := reflect.New(m.tp).Elem()
+		mock.Set(reflect.ValueOf(m.mock))
+		constructor := reflect.MakeFunc(
+			reflect.FuncOf(nil, []reflect.Type{m.tp}, false),
+			func([]reflect.Value) []reflect.Value {
+				return []reflect.Value{mock}
+			})
+		b.Provide(m.tp.Name(), constructor.Interface())
+		byName[m.tp.Name()] = m
+	}
+
+	policy := func(conflict alice.Conflict) (int, error) {
+		for i, candidate := range conflict.Candidates {
+			if candidate.Module != _MocksModule {
+				continue
+			}
+			if byName[candidate.Name].shadowing {
+				return i, nil
+			}
+			var shadowed []string
+			for _, other := range conflict.Candidates {
+				if other.Module != _MocksModule {
+					shadowed = append(shadowed, other.Module+"."+other.Name)
+				}
+			}
+			return -1, shadowingError(candidate.Name, shadowed)
+		}
+		return -1, nil
+	}
+	return b.Build(), alice.WithConflictPolicy(policy)
+}
+
+// checkShadowedProviders returns error if a mock without MockBinding.Shadowing() shadows real providers whose types
+// are assignable to its interface, which are not conflicts as the mock declares the interface exactly. Invalid
+// modules are reported when the container is created.
+func checkShadowedProviders(modules []alice.Module, opts []alice.Option, mocks []MockBinding) error {
+	planned, err := alice.Plan(modules, opts...)
+	if err != nil {
+		return nil
+	}
+	for _, m := range mocks {
+		if m.shadowing {
+			continue
+		}
+		var shadowed []string
+		for _, p := range planned {
+			if p.Module != _MocksModule && p.Type.AssignableTo(m.tp) {
+				shadowed = append(shadowed, p.Module+"."+p.Name)
+			}
+		}
+		if len(shadowed) > 0 {
+			return shadowingError(m.tp.Name(), shadowed)
+		}
+	}
+	return nil
+}
+
+// shadowingError returns the error of a mock shadowing real providers unintentionally.
+func shadowingError(mock string, shadowed []string) error {
+	return fmt.Errorf("mock %s shadows %v, which needs MockBinding.Shadowing()", mock, shadowed)
+}
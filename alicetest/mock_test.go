@@ -0,0 +1,143 @@
+package alicetest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/magic003/alice"
+)
+
+type mockGreeter struct {
+	calls int
+}
+
+func (g *mockGreeter) Greet() string {
+	g.calls++
+	return "hello mock"
+}
+
+type WelcomeModule struct {
+	alice.BaseModule
+	Greeter Greeter `alice:""`
+}
+
+func (m *WelcomeModule) Welcome() func() string {
+	return m.Greeter.Greet
+}
+
+// fakeTB records the fatal failure of a test, and panics to stop the caller instead of exiting the goroutine.
+type fakeTB struct {
+	testing.TB
+	failure string
+}
+
+func (tb *fakeTB) Fatalf(format string, args ...interface{}) {
+	tb.failure = fmt.Sprintf(format, args...)
+	panic(tb.failure)
+}
+
+func TestNewWithMocks(t *testing.T) {
+	mock := &mockGreeter{}
+	env := NewWithMocks([]alice.Module{&WelcomeModule{}}, Mock[Greeter](mock))
+
+	var deps struct {
+		Welcome func() string `alice:"Welcome"`
+		Greeter Greeter       `alice:"Greeter"`
+	}
+	env.Inject(t, &deps)
+	if welcome := deps.Welcome(); welcome != "hello mock" || mock.calls != 1 {
+		t.Errorf("bad welcome after Inject(): got %v, expected %v", welcome, "hello mock")
+	}
+	if deps.Greeter != mock {
+		t.Errorf("bad greeter after Inject(): got %v, expected %v", deps.Greeter, mock)
+	}
+}
+
+func TestNewWithMocks_Shadowing(t *testing.T) {
+	mock := &mockGreeter{}
+	env := NewWithMocks([]alice.Module{&GreeterModule{}, &WelcomeModule{}}, Mock[Greeter](mock).Shadowing())
+
+	var deps struct {
+		Welcome func() string `alice:"Welcome"`
+	}
+	env.Inject(t, &deps)
+	if welcome := deps.Welcome(); welcome != "hello mock" {
+		t.Errorf("bad welcome after Inject(): got %v, expected %v", welcome, "hello mock")
+	}
+}
+
+func TestNewWithMocks_Unintended(t *testing.T) {
+	env := NewWithMocks([]alice.Module{&GreeterModule{}, &WelcomeModule{}}, Mock[Greeter](&mockGreeter{}))
+
+	ft := &fakeTB{TB: t}
+	func() {
+		defer func() {
+			recover()
+		}()
+		env.Container(ft)
+	}()
+	if !strings.Contains(ft.failure, "mock Greeter shadows [GreeterModule.Greeter]") {
+		t.Errorf("bad failure after Container(): got %v, expected shadowing of GreeterModule.Greeter", ft.failure)
+	}
+}
+
+type concreteGreeterModule struct {
+	alice.BaseModule
+}
+
+func (m *concreteGreeterModule) EnglishGreeter() *mockGreeter {
+	return &mockGreeter{}
+}
+
+func TestNewWithMocks_Assignable(t *testing.T) {
+	modules := []alice.Module{&concreteGreeterModule{}, &WelcomeModule{}}
+	env := NewWithMocks(modules, Mock[Greeter](&mockGreeter{}))
+	ft := &fakeTB{TB: t}
+	func() {
+		defer func() {
+			recover()
+		}()
+		env.Container(ft)
+	}()
+	if !strings.Contains(ft.failure, "mock Greeter shadows [concreteGreeterModule.EnglishGreeter]") {
+		t.Errorf("bad failure after Container(): got %v, expected shadowing of concreteGreeterModule.EnglishGreeter",
+			ft.failure)
+	}
+
+	mock := &mockGreeter{}
+	env = NewWithMocks(modules, Mock[Greeter](mock).Shadowing())
+	var deps struct {
+		Greeter Greeter `alice:""`
+	}
+	env.Inject(t, &deps)
+	if deps.Greeter != mock {
+		t.Errorf("bad Greeter after Inject(): got %v, expected the mock", deps.Greeter)
+	}
+}
+
+func TestNewWithMocks_ConflictPolicy(t *testing.T) {
+	modules := []alice.Module{
+		alice.NewModule("first").Provide("Salutation", func() string { return "hello" }).Build(),
+		alice.NewModule("second").Provide("Salutation", func() string { return "hi" }).Build(),
+		&WelcomeModule{},
+	}
+	env := NewWithMocks(modules, Mock[Greeter](&mockGreeter{}))
+	env.opts = []alice.Option{alice.WithConflictPolicy(alice.FirstWins)}
+	var deps struct {
+		Salutation string `alice:"Salutation"`
+	}
+	env.Inject(t, &deps)
+	if deps.Salutation != "hello" {
+		t.Errorf("bad Salutation after Inject(): got %v, expected %v", deps.Salutation, "hello")
+	}
+}
+
+func TestMock_NotInterface(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for a type which is not an interface")
+		}
+	}()
+	Mock[*mockGreeter](&mockGreeter{})
+}
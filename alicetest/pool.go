@@ -0,0 +1,92 @@
+package alicetest
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/magic003/alice"
+)
+
+// Pool pre-builds identical containers and leases them to parallel tests, so the graph isn't rebuilt per test. The
+// modules are created by a function for each container, as modules are injected and can't be shared:
+//
+//	var pool = alicetest.NewPool(4, func() []alice.Module {
+//		return []alice.Module{&ConfigModule{}, &PersistModule{}}
+//	}).ResetBetweenLeases("Cache")
+//
+//	func TestDao(t *testing.T) {
+//		t.Parallel()
+//		c := pool.Lease(t)
+//		...
+//	}
+type Pool struct {
+	size     int
+	modules  func() []alice.Module
+	opts     []alice.Option
+	stateful []string
+
+	once      sync.Once
+	idle      chan alice.Container
+	recovered interface{}
+}
+
+// NewPool creates a pool of size containers with the modules returned by the function and the container options.
+// The containers are built concurrently on first use. Modules are replaced by their test variants as in New.
+func NewPool(size int, modules func() []alice.Module, opts ...alice.Option) *Pool {
+	if size < 1 {
+		size = 1
+	}
+	return &Pool{
+		size:    size,
+		modules: modules,
+		opts:    opts,
+	}
+}
+
+// ResetBetweenLeases sets the names of the stateful instances, which are reset by alice.Rebuilder when a leased
+// container is returned to the pool.
+func (p *Pool) ResetBetweenLeases(names ...string) *Pool {
+	p.stateful = names
+	return p
+}
+
+// Lease returns a container for exclusive use by the test, blocking until one is idle. The container is returned to
+// the pool when the test finishes. It fails the test if the containers could not be built.
+func (p *Pool) Lease(t testing.TB) alice.Container {
+	t.Helper()
+	p.once.Do(p.build)
+	if p.recovered != nil {
+		t.Fatalf("failed to create container: %v", p.recovered)
+	}
+
+	c := <-p.idle
+	t.Cleanup(func() {
+		if len(p.stateful) > 0 {
+			c.(alice.Rebuilder).Reset(p.stateful...)
+		}
+		p.idle <- c
+	})
+	return c
+}
+
+// build creates all containers concurrently.
+func (p *Pool) build() {
+	p.idle = make(chan alice.Container, p.size)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for i := 0; i < p.size; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					mu.Lock()
+					p.recovered = r
+					mu.Unlock()
+				}
+			}()
+			p.idle <- alice.CreateContainerWithOptions(testVariants(p.modules()), p.opts...)
+		}()
+	}
+	wg.Wait()
+}
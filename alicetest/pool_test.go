@@ -0,0 +1,42 @@
+package alicetest
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/magic003/alice"
+)
+
+type counter struct {
+	n int
+}
+
+var built atomic.Int32
+
+var pool = NewPool(2, func() []alice.Module {
+	built.Add(1)
+	return []alice.Module{
+		alice.NewModule("counter").
+			Provide("Counter", func() *counter { return &counter{} }).
+			Build(),
+	}
+}).ResetBetweenLeases("Counter")
+
+func TestPool(t *testing.T) {
+	for i := 0; i < 4; i++ {
+		t.Run("lease", func(t *testing.T) {
+			t.Parallel()
+			c := pool.Lease(t)
+			cnt := c.InstanceByName("Counter").(*counter)
+			if cnt.n != 0 {
+				t.Errorf("bad counter after Lease(): got %d, expected %d", cnt.n, 0)
+			}
+			cnt.n++
+		})
+	}
+	t.Cleanup(func() {
+		if n := built.Load(); n != 2 {
+			t.Errorf("bad number of built containers: got %d, expected %d", n, 2)
+		}
+	})
+}
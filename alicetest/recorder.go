@@ -0,0 +1,127 @@
+package alicetest
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/magic003/alice"
+)
+
+// Call is a call recorded by a Recorder.
+type Call struct {
+	// Method is the method name, or empty if the instance is a function.
+	Method string
+	Args   []interface{}
+}
+
+// Recorder records the calls made to instances, so tests could assert interactions without hand-written mocks.
+//
+// Instances are wrapped in recording proxies created by a ProxyFactory. Function-typed instances are proxied by
+// reflection. Go reflection could not create types implementing interfaces, so interface-typed instances are proxied
+// by the constructors registered to an alice.GeneratedProxyFactory, usually generated ahead of time, which is passed
+// to NewRecorderWithProxyFactory.
+type Recorder struct {
+	mu      sync.Mutex
+	calls   map[string][]Call
+	factory alice.ProxyFactory
+}
+
+// NewRecorder creates an empty recorder, which proxies function-typed instances by alice.ReflectProxyFactory.
+func NewRecorder() *Recorder {
+	return NewRecorderWithProxyFactory(alice.ReflectProxyFactory{})
+}
+
+// NewRecorderWithProxyFactory creates an empty recorder, which proxies instances by the factory.
+func NewRecorderWithProxyFactory(factory alice.ProxyFactory) *Recorder {
+	return &Recorder{calls: make(map[string][]Call), factory: factory}
+}
+
+// Record records a call to the method of the instance with the specified name.
+func (r *Recorder) Record(name string, method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[name] = append(r.calls[name], Call{Method: method, Args: args})
+}
+
+// Calls returns the calls recorded for the instance with the specified name, in order.
+func (r *Recorder) Calls(name string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call{}, r.calls[name]...)
+}
+
+// Count returns the number of calls recorded for the method of the instance with the specified name.
+func (r *Recorder) Count(name string, method string) int {
+	count := 0
+	for _, call := range r.Calls(name) {
+		if call.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// Func returns a function of the same type as fn, which records each call under the specified name and calls fn.
+func (r *Recorder) Func(name string, fn interface{}) interface{} {
+	target := func() interface{} { return fn }
+	proxy, _ := alice.ReflectProxyFactory{}.Proxy(reflect.TypeOf(fn), target, r.interceptor(name))
+	return proxy
+}
+
+// Proxy returns a proxy of type t, which records each call under the specified name and calls the instance. It
+// returns false if the proxy factory of the recorder can't proxy the type.
+func (r *Recorder) Proxy(name string, t reflect.Type, instance interface{}) (interface{}, bool) {
+	return r.factory.Proxy(t, func() interface{} { return instance }, r.interceptor(name))
+}
+
+// interceptor returns the interceptor recording the calls under the specified name.
+func (r *Recorder) interceptor(name string) alice.Interceptor {
+	return func(inv *alice.Invocation) []interface{} {
+		r.Record(name, inv.Method, inv.Args...)
+		return inv.Proceed(inv.Args)
+	}
+}
+
+// Overrides returns the overrides replacing the instances with the specified names by recording proxies, to be passed
+// to Inject. It fails the test if any instance is of another type than function or interface, or its type can't be
+// proxied by the proxy factory of the recorder, e.g. an interface without a registered proxy constructor. Without
+// names, all the instances of function and interface types the factory could proxy are replaced, and the others are
+// logged, so NewRecorder records the function-typed ones.
+func (r *Recorder) Overrides(t testing.TB, c alice.Container, names ...string) []Override {
+	t.Helper()
+	types := make(map[string]reflect.Type)
+	all := len(names) == 0
+	if introspector, ok := c.(alice.Introspector); ok {
+		for _, info := range introspector.Instances() {
+			types[info.Name] = info.Type
+			if all && (info.Type.Kind() == reflect.Func || info.Type.Kind() == reflect.Interface) {
+				names = append(names, info.Name)
+			}
+		}
+	}
+	var overrides []Override
+	for _, name := range names {
+		instance, err := resolve(c, name, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to record instance %s: %s", name, err.Error())
+		}
+		tp, ok := types[name]
+		if !ok {
+			tp = reflect.TypeOf(instance)
+		}
+		if tp == nil || tp.Kind() != reflect.Func && tp.Kind() != reflect.Interface {
+			t.Fatalf("failed to record instance %s: type %s is not a function or an interface", name, tp)
+		}
+		proxy, ok := r.Proxy(name, tp, instance)
+		if !ok && all {
+			t.Logf("instance %s is not recorded: type %s can't be proxied by the proxy factory", name, tp)
+			continue
+		}
+		if !ok {
+			t.Fatalf("failed to record instance %s: type %s can't be proxied by the proxy factory", name, tp)
+		}
+		overrides = append(overrides, OverrideName(name, proxy))
+	}
+	return overrides
+}
@@ -0,0 +1,110 @@
+package alicetest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/magic003/alice"
+)
+
+func TestRecorder(t *testing.T) {
+	c := alice.CreateContainer(alice.NewModule("funcs").
+		Provide("Greet", func() func(string) string {
+			return func(name string) string { return "hello " + name }
+		}).
+		Build())
+
+	r := NewRecorder()
+	var deps struct {
+		Greet func(string) string `alice:"Greet"`
+	}
+	Inject(t, c, &deps, r.Overrides(t, c, "Greet")...)
+
+	if greeting := deps.Greet("alice"); greeting != "hello alice" {
+		t.Errorf("bad result of recorded function: got %s, expected %s", greeting, "hello alice")
+	}
+	deps.Greet("bob")
+	expected := []Call{{Args: []interface{}{"alice"}}, {Args: []interface{}{"bob"}}}
+	if calls := r.Calls("Greet"); !reflect.DeepEqual(calls, expected) {
+		t.Errorf("bad calls after Calls(): got %v, expected %v", calls, expected)
+	}
+
+	r.Record("Dao", "Save", 1)
+	if count := r.Count("Dao", "Save"); count != 1 {
+		t.Errorf("bad count after Count(): got %d, expected %d", count, 1)
+	}
+}
+
+// greeterProxy is what a code generator would write for Greeter.
+type greeterProxy struct {
+	target      func() interface{}
+	interceptor alice.Interceptor
+}
+
+func (p greeterProxy) Greet() string {
+	target := p.target().(Greeter)
+	results := p.interceptor(&alice.Invocation{
+		Method: "Greet",
+		Proceed: func([]interface{}) []interface{} {
+			return []interface{}{target.Greet()}
+		},
+	})
+	return results[0].(string)
+}
+
+func TestRecorder_Interface(t *testing.T) {
+	c := alice.CreateContainer(&GreeterModule{}, &WelcomeModule{})
+	factory := alice.NewGeneratedProxyFactory()
+	factory.Register(reflect.TypeOf((*Greeter)(nil)).Elem(), func(target func() interface{},
+		interceptor alice.Interceptor) interface{} {
+		return greeterProxy{target: target, interceptor: interceptor}
+	})
+
+	r := NewRecorderWithProxyFactory(factory)
+	var deps struct {
+		Greeter Greeter       `alice:"Greeter"`
+		Welcome func() string `alice:"Welcome"`
+	}
+	Inject(t, c, &deps, r.Overrides(t, c)...)
+	if greeting := deps.Greeter.Greet(); greeting != "hello alice" {
+		t.Errorf("bad result of recorded interface: got %s, expected %s", greeting, "hello alice")
+	}
+	t.Log(deps.Welcome(), r.calls)
+	if count := r.Count("Greeter", "Greet"); count != 1 {
+		t.Errorf("bad count after Count(): got %d, expected %d", count, 1)
+	}
+	if calls := r.Calls("Welcome"); len(calls) != 1 {
+		t.Errorf("bad calls after Calls(): got %v, expected 1 call", calls)
+	}
+
+	ft := &fakeTB{TB: t}
+	func() {
+		defer func() {
+			recover()
+		}()
+		NewRecorder().Overrides(ft, c, "Greeter")
+	}()
+	if !strings.Contains(ft.failure, "can't be proxied") {
+		t.Errorf("bad failure after Overrides() without proxy constructor: got %v", ft.failure)
+	}
+}
+
+func TestRecorder_DefaultFactory(t *testing.T) {
+	c := alice.CreateContainer(&GreeterModule{}, &WelcomeModule{})
+	r := NewRecorder()
+	var deps struct {
+		Greeter Greeter       `alice:"Greeter"`
+		Welcome func() string `alice:"Welcome"`
+	}
+	Inject(t, c, &deps, r.Overrides(t, c)...)
+	if greeting := deps.Welcome(); greeting != "hello alice" {
+		t.Errorf("bad result of recorded function: got %s, expected %s", greeting, "hello alice")
+	}
+	if deps.Greeter.Greet() != "hello alice" || len(r.Calls("Greeter")) != 0 {
+		t.Errorf("bad calls of interface the default factory can't proxy: got %v, expected none", r.Calls("Greeter"))
+	}
+	if calls := r.Calls("Welcome"); len(calls) != 1 {
+		t.Errorf("bad calls after Calls(): got %v, expected 1 call", calls)
+	}
+}
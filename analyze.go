@@ -0,0 +1,93 @@
+package alice
+
+// GraphMetrics are the metrics of a dependency graph, e.g. to enforce architectural budgets in CI:
+//
+//	metrics, err := alice.Analyze(modules...)
+//	for _, m := range metrics.Modules {
+//		if m.FanOut > 12 {
+//			t.Errorf("module %s depends on %d modules", m.Module, m.FanOut)
+//		}
+//	}
+type GraphMetrics struct {
+	// Modules are the metrics of the modules in instantiation order.
+	Modules []ModuleMetrics
+	// Instances is the number of instances.
+	Instances int
+	// Edges is the number of dependencies between modules.
+	Edges int
+	// MaxDepth is the length of the longest chain of module dependencies.
+	MaxDepth int
+	// Fallbacks is the number of fallback instances in use, which are not shadowed by other providers.
+	Fallbacks int
+}
+
+// ModuleMetrics are the metrics of a module in a dependency graph.
+type ModuleMetrics struct {
+	// Module is the name of the module.
+	Module string
+	// Instances is the number of instances provided by the module.
+	Instances int
+	// FanIn is the number of modules depending on the module.
+	FanIn int
+	// FanOut is the number of modules the module depends on.
+	FanOut int
+	// Depth is the length of the longest chain of module dependencies below the module, or 0 if it depends on no
+	// module.
+	Depth int
+}
+
+// Analyze computes the metrics of the graph of the modules, without constructing any instance. It returns the same
+// errors as Validate if the modules are invalid.
+func Analyze(modules ...Module) (GraphMetrics, error) {
+	return AnalyzeWithOptions(modules)
+}
+
+// AnalyzeWithOptions computes the metrics like Analyze, with the options the container would be created with.
+func AnalyzeWithOptions(modules []Module, opts ...Option) (GraphMetrics, error) {
+	c := &container{
+		modules: modules,
+		options: newOptions(opts...),
+	}
+	rms, err := c.plan()
+	if err != nil {
+		return GraphMetrics{}, err
+	}
+
+	var metrics GraphMetrics
+	depths := make(map[*reflectedModule]int)
+	// modules are in instantiation order, so the depths of the dependencies are computed first
+	for _, rm := range rms {
+		mm := ModuleMetrics{
+			Module:    rm.name,
+			Instances: len(rm.instances),
+		}
+		for _, other := range rms {
+			if other == rm {
+				continue
+			}
+			if c.graph.g[rm][other] {
+				mm.FanIn++
+			}
+			if c.graph.g[other][rm] {
+				mm.FanOut++
+				if depth := depths[other] + 1; depth > mm.Depth {
+					mm.Depth = depth
+				}
+			}
+		}
+		depths[rm] = mm.Depth
+		for _, instance := range rm.instances {
+			if instance.fallback {
+				metrics.Fallbacks++
+			}
+		}
+
+		metrics.Modules = append(metrics.Modules, mm)
+		metrics.Instances += mm.Instances
+		metrics.Edges += mm.FanOut
+		if mm.Depth > metrics.MaxDepth {
+			metrics.MaxDepth = mm.Depth
+		}
+	}
+	return metrics, nil
+}
@@ -0,0 +1,54 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	metrics, err := Analyze(&M1{}, &M2{}, &M3{}, &M4{})
+	if err != nil {
+		t.Fatalf("bad error after Analyze(): got %v, expected nil", err)
+	}
+
+	if metrics.Instances != 6 || metrics.Edges != 4 || metrics.MaxDepth != 3 || metrics.Fallbacks != 0 {
+		t.Errorf("bad graph metrics after Analyze(): got %+v", metrics)
+	}
+	expected := map[string]ModuleMetrics{
+		"M1": {Module: "M1", Instances: 2, FanIn: 2, FanOut: 0, Depth: 0},
+		"M2": {Module: "M2", Instances: 1, FanIn: 1, FanOut: 2, Depth: 2},
+		"M3": {Module: "M3", Instances: 1, FanIn: 0, FanOut: 1, Depth: 3},
+		"M4": {Module: "M4", Instances: 2, FanIn: 1, FanOut: 1, Depth: 1},
+	}
+	if len(metrics.Modules) != len(expected) {
+		t.Fatalf("bad module metrics after Analyze(): got %+v, expected %+v", metrics.Modules, expected)
+	}
+	for _, m := range metrics.Modules {
+		if !reflect.DeepEqual(m, expected[m.Module]) {
+			t.Errorf("bad metrics of module %s after Analyze(): got %+v, expected %+v", m.Module, m,
+				expected[m.Module])
+		}
+	}
+}
+
+func TestAnalyze_Fallbacks(t *testing.T) {
+	metrics, err := Analyze(&FallbackModule1{})
+	if err != nil {
+		t.Fatalf("bad error after Analyze(): got %v, expected nil", err)
+	}
+	if metrics.Fallbacks != 2 {
+		t.Errorf("bad fallbacks after Analyze(): got %d, expected %d", metrics.Fallbacks, 2)
+	}
+
+	// both fallbacks are shadowed by M1
+	metrics, _ = Analyze(&M1{}, &FallbackModule1{})
+	if metrics.Fallbacks != 0 {
+		t.Errorf("bad fallbacks after Analyze() with shadowing module: got %d, expected %d", metrics.Fallbacks, 0)
+	}
+}
+
+func TestAnalyze_Invalid(t *testing.T) {
+	if _, err := Analyze(&M2{}); err == nil {
+		t.Error("expected error for missing dependencies")
+	}
+}
@@ -0,0 +1,78 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func BenchmarkCreateContainer(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		CreateContainer(&M1{}, &M2{}, &M3{}, &M4{}, &M5{})
+	}
+}
+
+func BenchmarkCreateContainer_Lazy(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		CreateContainerWithOptions([]Module{&M1{}, &M2{}, &M3{}, &M4{}, &M5{}}, WithLazy())
+	}
+}
+
+func BenchmarkInstanceByName(b *testing.B) {
+	c := CreateContainer(&M1{}, &M2{}, &M3{}, &M4{}, &M5{})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.InstanceByName("D1")
+	}
+}
+
+func BenchmarkInstance(b *testing.B) {
+	c := CreateContainer(&M1{}, &M2{}, &M3{}, &M4{}, &M5{})
+	t := reflect.TypeOf((*D2)(nil)).Elem()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Instance(t)
+	}
+}
+
+func BenchmarkInstance_Assignable(b *testing.B) {
+	c := CreateContainer(&M1{}, &M2{}, &M3{}, &M4{}, &M5{})
+	t := reflect.TypeOf((*D5)(nil)).Elem()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Instance(t)
+	}
+}
+
+func BenchmarkInstanceByName_Lazy(b *testing.B) {
+	c := CreateContainerWithOptions([]Module{&M1{}, &M2{}, &M3{}, &M4{}, &M5{}}, WithLazy())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.InstanceByName("D1")
+	}
+}
+
+func BenchmarkInstanceByName_Instrumented(b *testing.B) {
+	c := CreateContainerWithOptions([]Module{&M1{}, &M2{}, &M3{}, &M4{}, &M5{}},
+		WithInstrumentation(NewResolutionStats()))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.InstanceByName("D1")
+	}
+}
+
+func BenchmarkReflectModule(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := reflectModule(&reflectTestModule{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAccessor(b *testing.B) {
+	c := CreateContainer(&M1{}, &M2{}, &M3{}, &M4{}, &M5{})
+	d1 := NewAccessor[D1](c, "D1")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d1.Get()
+	}
+}
@@ -0,0 +1,67 @@
+package alice
+
+import (
+	"fmt"
+	"time"
+)
+
+// StartupBudget is the expected duration of creating a container, so regressions in boot time are caught
+// automatically.
+type StartupBudget struct {
+	// Total is the budget of creating the container, excluding background instances. Zero means no total budget.
+	Total time.Duration
+	// Expected are the expected durations of the instance methods keyed by instance names. In lazy mode, the
+	// duration of an instance includes constructing its dependencies on first use.
+	Expected map[string]time.Duration
+	// Fail makes the creation fail if the budget is exceeded, after all instances are constructed. In lazy mode, an
+	// instance exceeding its expected duration fails to be constructed instead. Otherwise, only warnings are logged.
+	Fail bool
+}
+
+// WithStartupBudget returns an option which checks the durations of creating the container and constructing
+// instances against the budget. Each overrun is logged as a warning by the logger of the container, with the
+// instance, the elapsed and the expected durations.
+func WithStartupBudget(budget StartupBudget) Option {
+	return func(o *options) {
+		o.budget = &budget
+	}
+}
+
+// checkInstanceBudget checks the duration of constructing an instance.
+func (c *container) checkInstanceBudget(name string, elapsed time.Duration) {
+	budget := c.options.budget
+	expected, ok := budget.Expected[name]
+	if !ok || elapsed <= expected {
+		return
+	}
+	c.options.logger.Warn("alice: instance exceeded its startup budget", "instance", name, "elapsed", elapsed,
+		"expected", expected)
+	err := fmt.Errorf("instance %s took %s, expected %s", name, elapsed, expected)
+	if budget.Fail && c.lazyByName != nil {
+		panic(err)
+	}
+	c.mu.Lock()
+	c.budgetErrors = append(c.budgetErrors, err)
+	c.mu.Unlock()
+}
+
+// checkTotalBudget checks the duration of creating the container. It panics if the budget is exceeded and it should
+// fail.
+func (c *container) checkTotalBudget(elapsed time.Duration) {
+	budget := c.options.budget
+	if budget.Total > 0 && elapsed > budget.Total {
+		c.options.logger.Warn("alice: container exceeded its startup budget", "elapsed", elapsed,
+			"expected", budget.Total)
+		c.mu.Lock()
+		c.budgetErrors = append(c.budgetErrors, fmt.Errorf("container creation took %s, expected %s", elapsed,
+			budget.Total))
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	err := joinErrors(c.budgetErrors)
+	c.mu.Unlock()
+	if budget.Fail && err != nil {
+		panic(fmt.Errorf("startup budget exceeded: %w", err))
+	}
+}
@@ -0,0 +1,74 @@
+package alice
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type slowModule struct {
+	BaseModule
+}
+
+func (m *slowModule) Slow() string {
+	time.Sleep(5 * time.Millisecond)
+	return "slow"
+}
+
+func (m *slowModule) Fast() int {
+	return 1
+}
+
+func TestWithStartupBudget(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	budget := StartupBudget{
+		Total:    time.Millisecond,
+		Expected: map[string]time.Duration{"Slow": time.Millisecond, "Fast": time.Second},
+	}
+	CreateContainerWithOptions([]Module{&slowModule{}}, WithStartupBudget(budget), WithLogger(logger))
+
+	output := buf.String()
+	for _, expected := range []string{
+		`msg="alice: instance exceeded its startup budget" instance=Slow`,
+		`msg="alice: container exceeded its startup budget"`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("bad warnings after CreateContainerWithOptions(): got %s, expected to contain %s", output,
+				expected)
+		}
+	}
+	if strings.Contains(output, "instance=Fast") {
+		t.Errorf("bad warnings after CreateContainerWithOptions(): got %s, expected no warning for Fast", output)
+	}
+}
+
+func TestWithStartupBudget_Fail(t *testing.T) {
+	budget := StartupBudget{
+		Expected: map[string]time.Duration{"Slow": time.Millisecond},
+		Fail:     true,
+	}
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+	func() {
+		defer func() {
+			err, ok := recover().(error)
+			if !ok || !strings.HasPrefix(err.Error(), "startup budget exceeded: instance Slow took") {
+				t.Errorf("bad panic from CreateContainerWithOptions(): got %v, expected startup budget exceeded", err)
+			}
+		}()
+		CreateContainerWithOptions([]Module{&slowModule{}}, WithStartupBudget(budget), WithLogger(logger))
+	}()
+
+	c := CreateContainerWithOptions([]Module{&slowModule{}}, WithStartupBudget(budget), WithLogger(logger),
+		WithLazy())
+	if _, err := c.ResolveByName("Fast"); err != nil {
+		t.Errorf("bad result from ResolveByName() in lazy mode: got %v, expected nil", err)
+	}
+	_, err := c.ResolveByName("Slow")
+	if err == nil || !strings.Contains(err.Error(), "instance Slow took") {
+		t.Errorf("bad result from ResolveByName() in lazy mode: got %v, expected instance Slow took", err)
+	}
+}
@@ -0,0 +1,466 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ModuleBuilder builds a module without defining a struct type. It is handy for scripts, examples and tests.
+//
+// Dependencies are declared by pointers, which are set before any constructor of the module is called:
+//
+//	var cfg Config
+//	m := alice.NewModule("storage").
+//		Require(&cfg).
+//		Provide("DB", func() *sql.DB {
+//			return openDB(cfg.DSN)
+//		}).
+//		Build()
+type ModuleBuilder struct {
+	m   *builtModule
+	err error
+}
+
+// builtModule is a module created by ModuleBuilder.
+type builtModule struct {
+	BaseModule
+	name string
+
+	providers    []*builtProvider
+	namedDepends []*namedField
+	typedDepends []*typedField
+	listDepends  []*listField
+	description  string
+	deprecations map[string]string
+	sandbox      *Sandbox
+	// imported indicates the instances are owned by another container, so they are not started or stopped.
+	imported bool
+	err      error
+}
+
+// builtProvider is an instance provided by a built module.
+type builtProvider struct {
+	name          string
+	constructor   reflect.Value
+	description   string
+	contributions []Contribution
+	fallback      bool
+	synchronized  bool
+	nilable       bool
+	isolation     *Isolation
+	condition     *Condition
+	degradation   *degradation
+	gate          *instanceGate
+	view          reflect.Type
+	params        []reflect.Type
+	paramsStruct  *paramsStruct
+}
+
+// NewModule creates a builder of a module with the specified name.
+func NewModule(name string) *ModuleBuilder {
+	return &ModuleBuilder{
+		m: &builtModule{name: name},
+	}
+}
+
+// Provide defines an instance with the specified name. constructor must be a function with 1 return value. The return
+// type is used as the instance type. The parameters, if any, are dependencies associated by type.
+func (b *ModuleBuilder) Provide(name string, constructor interface{}) *ModuleBuilder {
+	v := reflect.ValueOf(constructor)
+	if v.Kind() != reflect.Func || v.Type().NumOut() != 1 {
+		b.setError(fmt.Errorf("constructor %s.%s is not a function with 1 return value", b.m.name, name))
+		return b
+	}
+	params, ps, err := reflectParams(v.Type(), 0)
+	if err != nil {
+		b.setError(fmt.Errorf("constructor %s.%s: %s", b.m.name, name, err.Error()))
+		return b
+	}
+	b.m.providers = append(b.m.providers, &builtProvider{
+		name:         name,
+		constructor:  v,
+		params:       params,
+		paramsStruct: ps,
+	})
+	return b
+}
+
+// ProvideIf defines an instance like Provide, which is removed when the container is created if the condition doesn't
+// hold. Several instances with the same name could be provided under exclusive conditions:
+//
+//	m := alice.NewModule("watch").
+//		ProvideIfOS("linux", "Watcher", newEpollWatcher).
+//		ProvideIf(alice.Condition{OS: []string{"darwin", "freebsd"}}, "Watcher", newKqueueWatcher).
+//		Build()
+//
+// The methods marking an instance by name, like Describe, mark all the ones provided with the name, so they apply to
+// whichever holds. It fails the container creation if more than one of them holds.
+func (b *ModuleBuilder) ProvideIf(condition Condition, name string, constructor interface{}) *ModuleBuilder {
+	n := len(b.m.providers)
+	b.Provide(name, constructor)
+	if len(b.m.providers) > n {
+		b.m.providers[n].condition = &condition
+	}
+	return b
+}
+
+// ProvideIfOS defines an instance like Provide, which is only provided if runtime.GOOS is goos.
+func (b *ModuleBuilder) ProvideIfOS(goos string, name string, constructor interface{}) *ModuleBuilder {
+	return b.ProvideIf(Condition{OS: []string{goos}}, name, constructor)
+}
+
+// ProvideIfCapability defines an instance like Provide, which is only provided if the process has the capability,
+// like "cgo". See WithCapabilities.
+func (b *ModuleBuilder) ProvideIfCapability(capability string, name string, constructor interface{}) *ModuleBuilder {
+	return b.ProvideIf(Condition{Capabilities: []string{capability}}, name, constructor)
+}
+
+// Describe attaches a human-readable description to the instance with the specified name, which must be provided
+// before. The empty name describes the module itself.
+func (b *ModuleBuilder) Describe(name string, description string) *ModuleBuilder {
+	if name == "" {
+		b.m.description = description
+		return b
+	}
+	for _, p := range b.providersNamed(name, "described") {
+		p.description = description
+	}
+	return b
+}
+
+// Fallback marks the instance with the specified name, which must be provided before, as a fallback. It is removed if
+// any other instance, which is not a fallback, has the same name, or a type assignable to the type of the fallback.
+func (b *ModuleBuilder) Fallback(name string) *ModuleBuilder {
+	for _, p := range b.providersNamed(name, "fallback") {
+		p.fallback = true
+	}
+	return b
+}
+
+// Synchronize marks the instance with the specified name, which must be provided before, synchronized. It is wrapped
+// in a proxy guarded by a mutex. See SynchronizedModule.
+func (b *ModuleBuilder) Synchronize(name string) *ModuleBuilder {
+	for _, p := range b.providersNamed(name, "synchronized") {
+		if err := checkSynchronizable(b.m.name, name, p.constructor.Type().Out(0)); err != nil {
+			b.setError(err)
+			return b
+		}
+		p.synchronized = true
+	}
+	return b
+}
+
+// NonCritical marks the instance with the specified name, which must be provided before, non-critical. substitute
+// is used if it fails to be constructed. See DegradableModule.
+func (b *ModuleBuilder) NonCritical(name string, substitute interface{}) *ModuleBuilder {
+	for _, p := range b.providersNamed(name, "non-critical") {
+		d, err := newDegradation(p.constructor.Type().Out(0), substitute)
+		if err != nil {
+			b.setError(fmt.Errorf("non-critical instance %s.%s: %s", b.m.name, name, err.Error()))
+			return b
+		}
+		p.degradation = d
+	}
+	return b
+}
+
+// AllowNil marks the instance with the specified name, which must be provided before, nilable. See NilableModule.
+func (b *ModuleBuilder) AllowNil(name string) *ModuleBuilder {
+	for _, p := range b.providersNamed(name, "nilable") {
+		p.nilable = true
+	}
+	return b
+}
+
+// Isolate constructs the instance with the specified name, which must be provided before, on a dedicated goroutine.
+// See IsolatedModule.
+func (b *ModuleBuilder) Isolate(name string, isolation Isolation) *ModuleBuilder {
+	for _, p := range b.providersNamed(name, "isolated") {
+		p.isolation = &isolation
+	}
+	return b
+}
+
+// Sandbox sets the sandbox applied while the constructors of the module are called. See SandboxedModule.
+func (b *ModuleBuilder) Sandbox(sandbox Sandbox) *ModuleBuilder {
+	b.m.sandbox = &sandbox
+	return b
+}
+
+// Gate binds the instance with the specified name, which must be provided before, to a feature flag. alternative must
+// be a function without parameters, returning a value assignable to the instance type. It is called instead of the
+// constructor if the flag is disabled.
+func (b *ModuleBuilder) Gate(name string, flag string, alternative interface{}) *ModuleBuilder {
+	for _, p := range b.providersNamed(name, "gated") {
+		gate, err := newInstanceGate(p.constructor.Type().Out(0), Gate{Flag: flag, Alternative: alternative})
+		if err != nil {
+			b.setError(fmt.Errorf("gated instance %s.%s: %s", b.m.name, name, err.Error()))
+			return b
+		}
+		p.gate = gate
+	}
+	return b
+}
+
+// As registers the instance with the specified name, which must be provided before, by the view type instead of the
+// constructor return type. See ViewedModule for details.
+func (b *ModuleBuilder) As(name string, view reflect.Type) *ModuleBuilder {
+	for _, p := range b.providersNamed(name, "viewed") {
+		if view == nil || !p.constructor.Type().Out(0).AssignableTo(view) {
+			b.setError(fmt.Errorf("instance %s.%s of type %s is not assignable to view %v", b.m.name, name,
+				p.constructor.Type().Out(0), view))
+			return b
+		}
+		p.view = view
+	}
+	return b
+}
+
+// Contribute adds the instance with the specified name, which must be provided before, to a group with the priority.
+func (b *ModuleBuilder) Contribute(name string, group string, priority int) *ModuleBuilder {
+	for _, p := range b.providersNamed(name, "contributed") {
+		p.contributions = append(p.contributions, Contribution{Group: group, Priority: priority})
+	}
+	return b
+}
+
+// RequireGroup declares a dependency on the instances contributed to a group. target must be a non-nil pointer of
+// slice. The pointed slice is set to the instances ordered by priority.
+func (b *ModuleBuilder) RequireGroup(group string, target interface{}) *ModuleBuilder {
+	field, ok := b.targetField(target)
+	if !ok {
+		return b
+	}
+	if field.Kind() != reflect.Slice {
+		b.setError(fmt.Errorf("dependency target %v of module %s is not a pointer of slice", target, b.m.name))
+		return b
+	}
+	b.m.listDepends = append(b.m.listDepends, &listField{
+		group: group,
+		field: field,
+	})
+	return b
+}
+
+// RequireChain declares a dependency on the function instances contributed to a group. target must be a non-nil
+// pointer of function. The pointed function is set to a function calling the instances in order of priority. See
+// chainFuncs for how the results are combined.
+func (b *ModuleBuilder) RequireChain(group string, target interface{}) *ModuleBuilder {
+	field, ok := b.targetField(target)
+	if !ok {
+		return b
+	}
+	if field.Kind() != reflect.Func {
+		b.setError(fmt.Errorf("dependency target %v of module %s is not a pointer of function", target, b.m.name))
+		return b
+	}
+	b.m.listDepends = append(b.m.listDepends, &listField{
+		group: group,
+		chain: true,
+		field: field,
+	})
+	return b
+}
+
+// Deprecate marks the instance with the specified name deprecated, or the whole module if the name is empty.
+// replacement is the hint of what to use instead. A named instance must be provided before.
+func (b *ModuleBuilder) Deprecate(name string, replacement string) *ModuleBuilder {
+	if name != "" && !b.provided(name) {
+		b.setError(fmt.Errorf("deprecated instance %s.%s is not defined", b.m.name, name))
+		return b
+	}
+	if b.m.deprecations == nil {
+		b.m.deprecations = make(map[string]string)
+	}
+	b.m.deprecations[name] = replacement
+	return b
+}
+
+// Require declares a dependency associated by type. target must be a non-nil pointer. The pointed value is set to
+// the instance of the same or assignable type defined in other modules.
+func (b *ModuleBuilder) Require(target interface{}) *ModuleBuilder {
+	field, ok := b.targetField(target)
+	if ok {
+		b.m.typedDepends = append(b.m.typedDepends, &typedField{
+			tp:    field.Type(),
+			field: field,
+		})
+	}
+	return b
+}
+
+// RequireNamed declares a dependency associated by name. target must be a non-nil pointer. The pointed value is set
+// to the instance with the specified name defined in other modules.
+func (b *ModuleBuilder) RequireNamed(name string, target interface{}) *ModuleBuilder {
+	field, ok := b.targetField(target)
+	if ok {
+		b.m.namedDepends = append(b.m.namedDepends, &namedField{
+			name:  name,
+			field: field,
+		})
+	}
+	return b
+}
+
+// RequireNames declares a dependency on multiple instances associated by names. target must be a non-nil pointer of
+// slice. The pointed slice is set to the instances with the specified names, in the same order.
+func (b *ModuleBuilder) RequireNames(names []string, target interface{}) *ModuleBuilder {
+	field, ok := b.targetField(target)
+	if !ok {
+		return b
+	}
+	if field.Kind() != reflect.Slice {
+		b.setError(fmt.Errorf("dependency target %v of module %s is not a pointer of slice", target, b.m.name))
+		return b
+	}
+	b.m.listDepends = append(b.m.listDepends, &listField{
+		names: append([]string{}, names...),
+		field: field,
+	})
+	return b
+}
+
+// RequireMap declares a dependency on all instances of other modules assignable to the value type of a map. target
+// must be a non-nil pointer of map keyed by string. The pointed map is set to the instances keyed by their names.
+func (b *ModuleBuilder) RequireMap(target interface{}) *ModuleBuilder {
+	field, ok := b.targetField(target)
+	if !ok {
+		return b
+	}
+	if field.Kind() != reflect.Map || field.Type().Key().Kind() != reflect.String {
+		b.setError(fmt.Errorf("dependency target %v of module %s is not a pointer of map keyed by string", target,
+			b.m.name))
+		return b
+	}
+	b.m.listDepends = append(b.m.listDepends, &listField{
+		keyed: true,
+		field: field,
+	})
+	return b
+}
+
+// RequirePrefix declares a dependency on the instances of other modules whose names have the prefix and whose types are
+// assignable to the value type of a map, like RequireMap. The pointed map is set to the instances keyed by their names
+// without the prefix.
+func (b *ModuleBuilder) RequirePrefix(prefix string, target interface{}) *ModuleBuilder {
+	if prefix == "" {
+		b.setError(fmt.Errorf("dependency target %v of module %s has an empty prefix", target, b.m.name))
+		return b
+	}
+	n := len(b.m.listDepends)
+	b.RequireMap(target)
+	if len(b.m.listDepends) > n {
+		b.m.listDepends[n].prefix = prefix
+	}
+	return b
+}
+
+// Build returns the module. If the builder is misused, the error is reported when the module is used to create a
+// container.
+func (b *ModuleBuilder) Build() Module {
+	b.m.err = b.err
+	return b.m
+}
+
+func (b *ModuleBuilder) provided(name string) bool {
+	for _, p := range b.m.providers {
+		if p.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// providersNamed returns the providers with the specified name, which are several if they are provided under
+// conditions. It sets the error if there is none, describing the marked instance by the adjective.
+func (b *ModuleBuilder) providersNamed(name string, adjective string) []*builtProvider {
+	var providers []*builtProvider
+	for _, p := range b.m.providers {
+		if p.name == name {
+			providers = append(providers, p)
+		}
+	}
+	if len(providers) == 0 {
+		b.setError(fmt.Errorf("%s instance %s.%s is not defined", adjective, b.m.name, name))
+	}
+	return providers
+}
+
+func (b *ModuleBuilder) targetField(target interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		b.setError(fmt.Errorf("dependency target %v of module %s is not a non-nil pointer", target, b.m.name))
+		return reflect.Value{}, false
+	}
+	return v.Elem(), true
+}
+
+func (b *ModuleBuilder) setError(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// reflectBuiltModule creates a reflectedModule from a built module.
+func reflectBuiltModule(m *builtModule) (*reflectedModule, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	var instances []*instanceMethod
+	for _, p := range m.providers {
+		tp := p.constructor.Type().Out(0)
+		if p.view != nil {
+			tp = p.view
+		}
+		instances = append(instances, &instanceMethod{
+			name:          p.name,
+			tp:            tp,
+			method:        p.constructor,
+			params:        p.params,
+			paramsStruct:  p.paramsStruct.copy(),
+			description:   p.description,
+			contributions: p.contributions,
+			fallback:      p.fallback,
+			synchronized:  p.synchronized,
+			nilable:       p.nilable,
+			isolation:     p.isolation,
+			condition:     p.condition,
+			degradation:   p.degradation,
+			sandbox:       m.sandbox,
+			gate:          p.gate,
+		})
+	}
+
+	// the dependencies are copied, as their names could be qualified per container
+	var namedDepends []*namedField
+	for _, dep := range m.namedDepends {
+		copied := *dep
+		namedDepends = append(namedDepends, &copied)
+	}
+	var typedDepends []*typedField
+	for _, dep := range m.typedDepends {
+		copied := *dep
+		typedDepends = append(typedDepends, &copied)
+	}
+	var listDepends []*listField
+	for _, dep := range m.listDepends {
+		copied := *dep
+		copied.names = append([]string{}, dep.names...)
+		listDepends = append(listDepends, &copied)
+	}
+
+	rm := &reflectedModule{
+		m:            m,
+		name:         m.name,
+		instances:    instances,
+		namedDepends: namedDepends,
+		typedDepends: typedDepends,
+		listDepends:  listDepends,
+		description:  m.description,
+	}
+	if err := deprecate(rm, m.deprecations); err != nil {
+		return nil, err
+	}
+	return rm, nil
+}
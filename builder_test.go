@@ -0,0 +1,69 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModuleBuilder(t *testing.T) {
+	var (
+		d1 D1
+		d3 D3
+	)
+	m := NewModule("built").
+		RequireNamed("D1", &d1).
+		Require(&d3).
+		Provide("D5", func() *D5Impl {
+			if d1 == nil || d3 == nil {
+				t.Error("dependencies are expected to be set before constructor is called")
+			}
+			return &D5Impl{}
+		}).
+		Build()
+
+	c := CreateContainer(&M1{}, &M4{}, m, &M3{})
+
+	if !reflect.DeepEqual(d1, &D1Impl{}) {
+		t.Errorf("bad d1 after CreateContainer(): got %v, expected %v", d1, &D1Impl{})
+	}
+	if !reflect.DeepEqual(d3, &D3Impl{}) {
+		t.Errorf("bad d3 after CreateContainer(): got %v, expected %v", d3, &D3Impl{})
+	}
+	d5 := c.InstanceByName("D5")
+	if !reflect.DeepEqual(d5, &D5Impl{}) {
+		t.Errorf("bad instance from InstanceByName(): got %v, expected %v", d5, &D5Impl{})
+	}
+}
+
+func TestReflectModule_BuiltModule(t *testing.T) {
+	m := NewModule("built").Provide("D1", func() D1 { return &D1Impl{} }).Build()
+
+	rm, err := reflectModule(m)
+	if err != nil {
+		t.Errorf("unexpected error after reflectModule(): %s", err.Error())
+	}
+	if rm.name != "built" {
+		t.Errorf("bad name in reflectedModule: got %s, expected %s", rm.name, "built")
+	}
+	if len(rm.instances) != 1 || rm.instances[0].name != "D1" ||
+		rm.instances[0].tp != reflect.TypeOf((*D1)(nil)).Elem() {
+		t.Errorf("bad instances in reflectedModule: got %v", rm.instances)
+	}
+}
+
+func TestReflectModule_InvalidBuiltModule(t *testing.T) {
+	m := NewModule("invalid").Provide("D1", func() (D1, error) { return &D1Impl{}, nil }).Build()
+	_, err := reflectModule(m)
+	if err == nil {
+		t.Error("expect error after reflectModule() on built module with 2 return values constructor")
+	}
+	t.Log(err.Error())
+
+	var d1 D1
+	m = NewModule("invalid").Require(d1).Build()
+	_, err = reflectModule(m)
+	if err == nil {
+		t.Error("expect error after reflectModule() on built module with non-pointer target")
+	}
+	t.Log(err.Error())
+}
@@ -0,0 +1,6 @@
+//go:build cgo
+
+package alice
+
+// cgoEnabled indicates the binary is built with cgo.
+const cgoEnabled = true
@@ -0,0 +1,34 @@
+package alice
+
+import (
+	"reflect"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// chainFuncs returns a function of type t calling the funcs in order with the same arguments. If the last result of
+// t is an error, the chain stops at the first function returning a non-nil error, and returns its results. Otherwise
+// it returns the results of the last function. An empty chain returns zero values.
+func chainFuncs(t reflect.Type, funcs []reflect.Value) reflect.Value {
+	failable := t.NumOut() > 0 && t.Out(t.NumOut()-1) == errorType
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		results := make([]reflect.Value, t.NumOut())
+		for i := range results {
+			results[i] = reflect.Zero(t.Out(i))
+		}
+		for _, f := range funcs {
+			if f.IsNil() {
+				continue
+			}
+			if t.IsVariadic() {
+				results = f.CallSlice(args)
+			} else {
+				results = f.Call(args)
+			}
+			if failable && !results[len(results)-1].IsNil() {
+				break
+			}
+		}
+		return results
+	})
+}
@@ -0,0 +1,113 @@
+package alice
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Validator func(s string) error
+
+type ValidatorModule struct {
+	BaseModule
+}
+
+func (m *ValidatorModule) NotEmpty() Validator {
+	return func(s string) error {
+		if s == "" {
+			return errors.New("empty")
+		}
+		return nil
+	}
+}
+
+func (m *ValidatorModule) Lower() Validator {
+	return func(s string) error {
+		if strings.ToLower(s) != s {
+			return errors.New("not lower")
+		}
+		return nil
+	}
+}
+
+func (m *ValidatorModule) Groups() map[string]Contribution {
+	return map[string]Contribution{
+		"NotEmpty": {Group: "validators", Priority: 0},
+		"Lower":    {Group: "validators", Priority: 10},
+	}
+}
+
+type ChainModule struct {
+	BaseModule
+	Validate Validator    `alice:"chain=validators"`
+	Empty    func() error `alice:"chain=empty"`
+}
+
+type invalidChainModule struct {
+	BaseModule
+	Validate []Validator `alice:"chain=validators"`
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	trace := NewModule("trace").
+		Provide("Trace", func() Validator {
+			return func(s string) error {
+				calls = append(calls, s)
+				return nil
+			}
+		}).
+		Contribute("Trace", "validators", -10).
+		Build()
+	chain := &ChainModule{}
+	CreateContainer(&ValidatorModule{}, chain, trace)
+
+	if err := chain.Validate("ok"); err != nil {
+		t.Errorf("bad error after calling the chain: got %v, expected nil", err)
+	}
+	if err := chain.Validate(""); err == nil || err.Error() != "empty" {
+		t.Errorf("bad error after calling the chain: got %v, expected empty", err)
+	}
+	if err := chain.Validate("UP"); err == nil || err.Error() != "not lower" {
+		t.Errorf("bad error after calling the chain: got %v, expected not lower", err)
+	}
+	if expected := []string{"ok", "", "UP"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("bad calls after calling the chain: got %v, expected %v", calls, expected)
+	}
+	if err := chain.Empty(); err != nil {
+		t.Errorf("bad error after calling the empty chain: got %v, expected nil", err)
+	}
+}
+
+func TestChain_RequireChain(t *testing.T) {
+	var sum func(n int) int
+	var total int
+	adder := func(k int) func(int) int {
+		return func(n int) int {
+			total += n * k
+			return total
+		}
+	}
+	m := NewModule("adders").
+		Provide("One", func() func(int) int { return adder(1) }).
+		Provide("Ten", func() func(int) int { return adder(10) }).
+		Contribute("One", "adders", 0).
+		Contribute("Ten", "adders", 0).
+		Build()
+	consumer := NewModule("consumer").RequireChain("adders", &sum).Build()
+	CreateContainer(m, consumer)
+	if result := sum(2); result != 22 {
+		t.Errorf("bad result after calling the chain: got %d, expected %d", result, 22)
+	}
+}
+
+func TestChain_Invalid(t *testing.T) {
+	if err := Validate(&invalidChainModule{}); err == nil {
+		t.Error("expected error for chain field not of function type")
+	}
+	var notFunc []Validator
+	if err := Validate(NewModule("built").RequireChain("validators", &notFunc).Build()); err == nil {
+		t.Error("expected error for chain target not of function type")
+	}
+}
@@ -0,0 +1,115 @@
+//go:build cobra
+
+// Package cli wires cobra commands through alice containers. Modules contribute their *cobra.Command instances to
+// the "commands" group, and the module provided by the package assembles them under the root command. The commands
+// run their dependencies resolved from the container by Invoke, so multi-command CLIs don't need global singletons.
+//
+// The package depends on github.com/spf13/cobra, so it is only built with the "cobra" build tag:
+//
+//	go build -tags cobra ./...
+package cli
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/magic003/alice"
+	"github.com/spf13/cobra"
+)
+
+// Group is the group where modules contribute their commands, e.g. by implementing alice.GroupedModule:
+//
+//	func (m *ServerModule) ServeCommand() *cobra.Command {
+//		return &cobra.Command{Use: "serve", RunE: cli.Invoke(func(ctx context.Context, s *Server) error {
+//			return s.ListenAndServe(ctx)
+//		})}
+//	}
+//
+//	func (m *ServerModule) Groups() map[string]alice.Contribution {
+//		return map[string]alice.Contribution{"ServeCommand": {Group: cli.Group}}
+//	}
+const Group = "commands"
+
+var (
+	_CommandType = reflect.TypeOf((*cobra.Command)(nil))
+	_ArgsType    = reflect.TypeOf([]string(nil))
+	_ContextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	_ErrorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Module provides the "RootCommand" instance, which is the root command with all commands contributed to Group as
+// subcommands, in the order of their priorities.
+type Module struct {
+	alice.BaseModule
+	Commands []*cobra.Command `alice:"group=commands"`
+
+	root *cobra.Command
+}
+
+// NewModule creates the module assembling commands under the root command.
+func NewModule(root *cobra.Command) *Module {
+	return &Module{root: root}
+}
+
+// RootCommand returns the root command.
+func (m *Module) RootCommand() *cobra.Command {
+	m.root.AddCommand(m.Commands...)
+	return m.root
+}
+
+// Execute executes the root command of the container with the arguments of the process. The container is carried
+// by the context of the command, so the functions passed to Invoke could resolve their parameters from it.
+func Execute(ctx context.Context, c alice.Container) error {
+	root := c.InstanceByName("RootCommand").(*cobra.Command)
+	return root.ExecuteContext(alice.NewContext(ctx, c))
+}
+
+// Invoke returns a RunE function of commands calling fn, whose parameters are resolved from the container carried by
+// the context of the command. Parameters of type *cobra.Command, []string and context.Context are the command, its
+// arguments and its context, and any other parameter is an instance retrieved by type. fn optionally returns an
+// error. It panics if fn is not such a function.
+func Invoke(fn interface{}) func(cmd *cobra.Command, args []string) error {
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	if t.Kind() != reflect.Func {
+		panic(fmt.Errorf("cli: Invoke expects a function, got %s", t))
+	}
+	if t.NumOut() > 1 || (t.NumOut() == 1 && t.Out(0) != _ErrorType) {
+		panic(fmt.Errorf("cli: Invoke expects a function returning nothing or an error, got %s", t))
+	}
+
+	return func(cmd *cobra.Command, args []string) error {
+		c, ok := alice.ContainerFromContext(cmd.Context())
+		if !ok {
+			return alice.ErrNoContainer
+		}
+		in := make([]reflect.Value, t.NumIn())
+		for i := range in {
+			switch pt := t.In(i); pt {
+			case _CommandType:
+				in[i] = reflect.ValueOf(cmd)
+			case _ArgsType:
+				in[i] = reflect.ValueOf(args)
+			case _ContextType:
+				in[i] = reflect.ValueOf(cmd.Context())
+			default:
+				instance, err := c.Resolve(pt)
+				if err != nil {
+					return fmt.Errorf("failed to resolve parameter %d of command %s: %w", i, cmd.Name(), err)
+				}
+				if instance == nil {
+					// a nil instance, e.g. of a nilable interface, has no value to pass
+					in[i] = reflect.Zero(pt)
+				} else {
+					in[i] = reflect.ValueOf(instance)
+				}
+			}
+		}
+		out := v.Call(in)
+		if len(out) == 1 && !out[0].IsNil() {
+			return out[0].Interface().(error)
+		}
+		return nil
+	}
+}
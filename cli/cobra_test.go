@@ -0,0 +1,114 @@
+//go:build cobra
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/magic003/alice"
+	"github.com/spf13/cobra"
+)
+
+type greeter struct {
+	greeting string
+}
+
+type greetModule struct {
+	alice.BaseModule
+	greeted []string
+}
+
+func (m *greetModule) Greeter() *greeter {
+	return &greeter{greeting: "hello"}
+}
+
+func (m *greetModule) GreetCommand() *cobra.Command {
+	return &cobra.Command{Use: "greet", RunE: Invoke(func(ctx context.Context, g *greeter, args []string) {
+		m.greeted = append(m.greeted, g.greeting+" "+strings.Join(args, " "))
+	})}
+}
+
+func (m *greetModule) FailCommand() *cobra.Command {
+	return &cobra.Command{Use: "fail", RunE: Invoke(func(cmd *cobra.Command) error {
+		return errors.New("failed " + cmd.Name())
+	})}
+}
+
+func (m *greetModule) Groups() map[string]alice.Contribution {
+	return map[string]alice.Contribution{
+		"GreetCommand": {Group: Group},
+		"FailCommand":  {Group: Group, Priority: 1},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	m := &greetModule{}
+	c := alice.CreateContainer(NewModule(&cobra.Command{Use: "app"}), m)
+	root := c.InstanceByName("RootCommand").(*cobra.Command)
+	if len(root.Commands()) != 2 {
+		t.Fatalf("bad subcommands of RootCommand: got %d, expected 2", len(root.Commands()))
+	}
+
+	root.SetArgs([]string{"greet", "alice"})
+	if err := Execute(context.Background(), c); err != nil {
+		t.Errorf("bad result from Execute(): got %v, expected nil", err)
+	}
+	if len(m.greeted) != 1 || m.greeted[0] != "hello alice" {
+		t.Errorf("bad greetings after Execute(): got %v, expected [hello alice]", m.greeted)
+	}
+
+	root.SetArgs([]string{"fail"})
+	if err := Execute(context.Background(), c); err == nil || err.Error() != "failed fail" {
+		t.Errorf("bad result from Execute(): got %v, expected failed fail", err)
+	}
+}
+
+func TestInvoke_Unresolved(t *testing.T) {
+	c := alice.CreateContainer(NewModule(&cobra.Command{Use: "app"}))
+	runE := Invoke(func(g *greeter) {})
+	cmd := &cobra.Command{Use: "greet"}
+	cmd.SetContext(alice.NewContext(context.Background(), c))
+	err := runE(cmd, nil)
+	if err == nil || !errors.Is(err, alice.ErrNotFound) {
+		t.Errorf("bad result from RunE: got %v, expected %v", err, alice.ErrNotFound)
+	}
+
+	cmd.SetContext(context.Background())
+	if err := runE(cmd, nil); err != alice.ErrNoContainer {
+		t.Errorf("bad result from RunE without container: got %v, expected %v", err, alice.ErrNoContainer)
+	}
+}
+
+func TestInvoke_NilInstance(t *testing.T) {
+	m := alice.NewModule("optional").
+		Provide("Stringer", func() fmt.Stringer { return nil }).
+		AllowNil("Stringer").
+		Build()
+	c := alice.CreateContainer(m)
+	called := false
+	runE := Invoke(func(s fmt.Stringer) {
+		called = s == nil
+	})
+	cmd := &cobra.Command{Use: "print"}
+	cmd.SetContext(alice.NewContext(context.Background(), c))
+	if err := runE(cmd, nil); err != nil || !called {
+		t.Errorf("bad result from RunE with nil instance: got %v, called with nil %v", err, called)
+	}
+}
+
+func TestInvoke_BadFunction(t *testing.T) {
+	for _, fn := range []interface{}{"fn", func() int { return 0 }} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("bad result from Invoke(%T): got no panic, expected panic", fn)
+				}
+			}()
+			Invoke(fn)
+		}()
+	}
+}
@@ -0,0 +1,110 @@
+// Package clock provides the Clock instance, so code reading the time could be tested deterministically. The module
+// provides the system clock, and its test variant, used by alicetest containers automatically, provides a fake clock
+// which only moves when it is told to.
+//
+//	c := alice.CreateContainer(clock.NewModule(), &SessionModule{})
+package clock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/magic003/alice"
+)
+
+// Clock tells the time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+}
+
+// System is the clock of the system.
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// Epoch is the initial time of the fake clock of the test module.
+var Epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Fake is a clock which only moves by Set or Advance. It is safe for concurrent use.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake creates a fake clock at the time.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the time of the clock.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since returns the time elapsed since t by the clock.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// Set sets the time of the clock.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Advance moves the clock forward by d.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// Module provides the Clock instance of the system clock.
+type Module struct {
+	alice.BaseModule
+}
+
+// NewModule creates the module.
+func NewModule() *Module {
+	return &Module{}
+}
+
+// Clock returns the system clock.
+func (m *Module) Clock() Clock {
+	return System
+}
+
+// TestVariant returns a test module with a fake clock at Epoch.
+func (m *Module) TestVariant() alice.Module {
+	return NewTestModule(Epoch)
+}
+
+// TestModule provides the Clock instance of a fake clock, which could be type asserted to *Fake in tests.
+type TestModule struct {
+	alice.BaseModule
+	now time.Time
+}
+
+// NewTestModule creates the test module with a fake clock at the time.
+func NewTestModule(now time.Time) *TestModule {
+	return &TestModule{now: now}
+}
+
+// Clock returns a fake clock.
+func (m *TestModule) Clock() Clock {
+	return NewFake(m.now)
+}
@@ -0,0 +1,38 @@
+package clock
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/magic003/alice"
+)
+
+var clockType = reflect.TypeOf((*Clock)(nil)).Elem()
+
+func TestSystem(t *testing.T) {
+	c := alice.CreateContainer(NewModule())
+	if clock := c.Instance(clockType).(Clock); clock != System {
+		t.Errorf("bad clock after CreateContainer(): got %v, expected the system clock", clock)
+	}
+	if d := System.Since(System.Now()); d < 0 || d > time.Second {
+		t.Errorf("bad duration after Since(): got %s", d)
+	}
+}
+
+func TestFake(t *testing.T) {
+	c := alice.CreateContainer(NewModule().TestVariant())
+	fake := c.Instance(clockType).(*Fake)
+	if !fake.Now().Equal(Epoch) {
+		t.Errorf("bad time after CreateContainer(): got %s, expected %s", fake.Now(), Epoch)
+	}
+	fake.Advance(time.Hour)
+	if d := fake.Since(Epoch); d != time.Hour {
+		t.Errorf("bad duration after Advance(): got %s, expected %s", d, time.Hour)
+	}
+	now := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+	fake.Set(now)
+	if !fake.Now().Equal(now) {
+		t.Errorf("bad time after Set(): got %s, expected %s", fake.Now(), now)
+	}
+}
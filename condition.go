@@ -0,0 +1,100 @@
+package alice
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Condition restricts an instance to the platforms and capabilities it is implemented for. It is evaluated when the
+// container is created, and an instance whose condition doesn't hold is removed, as if it were not defined. So
+// platform-specific implementations of the same instance, like an epoll and a kqueue watcher, could coexist in one
+// module set without splitting them into files by build tags.
+type Condition struct {
+	// OS contains the values of runtime.GOOS the instance is provided on. Empty means any.
+	OS []string
+	// Arch contains the values of runtime.GOARCH the instance is provided on. Empty means any.
+	Arch []string
+	// Capabilities contains the capabilities the process must all have, like "cgo". See WithCapabilities.
+	Capabilities []string
+}
+
+// holds reports whether the condition holds for the current platform and the capabilities.
+func (cond Condition) holds(capabilities map[string]bool) bool {
+	if len(cond.OS) > 0 && !containsString(cond.OS, runtime.GOOS) {
+		return false
+	}
+	if len(cond.Arch) > 0 && !containsString(cond.Arch, runtime.GOARCH) {
+		return false
+	}
+	for _, capability := range cond.Capabilities {
+		if !capabilities[capability] {
+			return false
+		}
+	}
+	return true
+}
+
+// containsString reports whether s is in values.
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// WithCapabilities returns an option which adds capabilities detected by the application, like "avx2" or "io_uring",
+// to the ones evaluated by conditional instances. The built-in capability is "cgo", if cgo is enabled.
+func WithCapabilities(capabilities ...string) Option {
+	return func(o *options) {
+		o.capabilities = append(o.capabilities, capabilities...)
+	}
+}
+
+// capabilitySet returns the built-in capabilities and the ones added by WithCapabilities.
+func (o *options) capabilitySet() map[string]bool {
+	set := map[string]bool{"cgo": cgoEnabled}
+	for _, capability := range o.capabilities {
+		set[capability] = true
+	}
+	return set
+}
+
+// markConditionalInstances sets the conditions of the instances with the specified names.
+func markConditionalInstances(moduleName string, instances []*instanceMethod, conditions map[string]Condition) error {
+	for name, condition := range conditions {
+		instance := findInstanceMethod(instances, name)
+		if instance == nil {
+			return fmt.Errorf("conditional instance %s.%s is not defined", moduleName, name)
+		}
+		condition := condition
+		instance.condition = &condition
+	}
+	return nil
+}
+
+// removeUnsatisfiedInstances removes the instances of the module whose conditions don't hold. It returns error if
+// more than one conditional instance with the same name holds, as the conditions are meant to be exclusive.
+func removeUnsatisfiedInstances(rm *reflectedModule, capabilities map[string]bool) error {
+	var instances []*instanceMethod
+	held := make(map[string]bool)
+	var errs []error
+	for _, instance := range rm.instances {
+		if instance.condition == nil {
+			instances = append(instances, instance)
+			continue
+		}
+		if !instance.condition.holds(capabilities) {
+			continue
+		}
+		if held[instance.name] {
+			errs = append(errs, fmt.Errorf("conditions of multiple instances %s.%s hold", rm.name, instance.name))
+			continue
+		}
+		held[instance.name] = true
+		instances = append(instances, instance)
+	}
+	rm.instances = instances
+	return joinErrors(errs)
+}
@@ -0,0 +1,116 @@
+package alice
+
+import (
+	"runtime"
+	"testing"
+)
+
+type watcher interface {
+	Kind() string
+}
+
+type kindWatcher string
+
+func (w kindWatcher) Kind() string {
+	return string(w)
+}
+
+type conditionalModule struct {
+	BaseModule
+}
+
+func (m *conditionalModule) Conditions() map[string]Condition {
+	return map[string]Condition{
+		"Current": {OS: []string{runtime.GOOS}, Arch: []string{runtime.GOARCH}},
+		"Other":   {OS: []string{"plan9-" + runtime.GOOS}},
+	}
+}
+
+func (m *conditionalModule) Current() D1 {
+	return &D1Impl{}
+}
+
+func (m *conditionalModule) Other() D2 {
+	return &D2Impl{}
+}
+
+func TestConditionalModule(t *testing.T) {
+	c := CreateContainer(&conditionalModule{}).(Introspector)
+	if names := c.Constructed(); len(names) != 1 || names[0] != "Current" {
+		t.Errorf("bad instances after CreateContainer(): got %v, expected [Current]", names)
+	}
+
+	m := NewModule("watch").
+		ProvideIfOS("plan9-"+runtime.GOOS, "Watcher", func() watcher { return kindWatcher("other") }).
+		ProvideIfOS(runtime.GOOS, "Watcher", func() watcher { return kindWatcher("current") }).
+		ProvideIfCapability("io_uring", "Ring", func() *D5Impl { return &D5Impl{} }).
+		Build()
+	wc := CreateContainer(m)
+	if kind := wc.InstanceByName("Watcher").(watcher).Kind(); kind != "current" {
+		t.Errorf("bad instance after InstanceByName(): got %v, expected %v", kind, "current")
+	}
+	if _, err := wc.(Introspector).InstanceByTypeName("*alice.D5Impl"); err == nil {
+		t.Error("expected error for instance without capability")
+	}
+	rc := CreateContainerWithOptions([]Module{m}, WithCapabilities("io_uring"))
+	if rc.InstanceByName("Ring") == nil {
+		t.Error("expected instance with capability")
+	}
+}
+
+func TestConditionalModule_Marks(t *testing.T) {
+	m := NewModule("watch").
+		ProvideIfOS("plan9-"+runtime.GOOS, "Watcher", func() watcher { return kindWatcher("other") }).
+		ProvideIfOS(runtime.GOOS, "Watcher", func() watcher { return kindWatcher("current") }).
+		Describe("Watcher", "watches the files").
+		Build()
+	infos := CreateContainer(m).(Introspector).Instances()
+	if len(infos) != 1 || infos[0].Description != "watches the files" {
+		t.Errorf("bad instances after CreateContainer(): got %v, expected the described Watcher", infos)
+	}
+
+	both := NewModule("watch").
+		ProvideIfOS(runtime.GOOS, "Watcher", func() watcher { return kindWatcher("first") }).
+		ProvideIf(Condition{Arch: []string{runtime.GOARCH}}, "Watcher",
+			func() watcher { return kindWatcher("second") }).
+		Build()
+	if err := Validate(both); err == nil {
+		t.Error("expected error for multiple conditional instances holding")
+	}
+}
+
+func TestConditionalModule_Error(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for undefined conditional instance")
+		}
+	}()
+	CreateContainer(&undefinedConditionModule{})
+}
+
+type undefinedConditionModule struct {
+	BaseModule
+}
+
+func (m *undefinedConditionModule) Conditions() map[string]Condition {
+	return map[string]Condition{"Missing": {}}
+}
+
+func TestCondition_Holds(t *testing.T) {
+	cases := []struct {
+		condition Condition
+		expected  bool
+	}{
+		{Condition{}, true},
+		{Condition{OS: []string{"plan9-" + runtime.GOOS, runtime.GOOS}}, true},
+		{Condition{Arch: []string{"none"}}, false},
+		{Condition{Capabilities: []string{"cgo"}}, cgoEnabled},
+		{Condition{Capabilities: []string{"avx2", "io_uring"}}, false},
+	}
+	capabilities := map[string]bool{"cgo": cgoEnabled, "avx2": true}
+	for _, tc := range cases {
+		if holds := tc.condition.holds(capabilities); holds != tc.expected {
+			t.Errorf("bad result after holds() of %+v: got %v, expected %v", tc.condition, holds, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package alice
+
+import (
+	"context"
+)
+
+// ConfigChangeListener is an optional interface an instance could implement to be notified when a Dynamic instance
+// it depends on, directly or transitively, is refreshed. It lets an instance apply the new configuration, e.g. by
+// rebuilding a client, without rebuilding the container. As dependencies are injected into module fields, all
+// instances of a depending module are notified.
+type ConfigChangeListener interface {
+	// OnConfigChange is called with the name of the refreshed instance, after the instances it depends on have been
+	// notified.
+	OnConfigChange(ctx context.Context, changed string) error
+}
+
+// changeNotifier is implemented by instances whose values change, like Dynamic.
+type changeNotifier interface {
+	notifyChange(callback func()) (cancel func())
+}
+
+// subscribeChanges propagates the changes of an instance to its listeners between Container.Start and
+// Container.Stop.
+func (c *container) subscribeChanges(name string, notifier changeNotifier) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.changeSubscriptions[name]; ok {
+		return
+	}
+	if c.changeSubscriptions == nil {
+		c.changeSubscriptions = make(map[string]func())
+	}
+	c.changeSubscriptions[name] = notifier.notifyChange(func() {
+		c.propagateChange(context.Background(), name)
+	})
+}
+
+// unsubscribeChanges cancels the subscriptions of subscribeChanges.
+func (c *container) unsubscribeChanges() {
+	c.mu.Lock()
+	subscriptions := c.changeSubscriptions
+	c.changeSubscriptions = nil
+	c.mu.Unlock()
+	for _, cancel := range subscriptions {
+		cancel()
+	}
+}
+
+// propagateChange notifies the constructed listeners depending on the changed instance, in instantiation order. A
+// failing listener is logged, and doesn't stop the others from being notified.
+func (c *container) propagateChange(ctx context.Context, changed string) {
+	dependents, _ := c.dependentsOf([]string{changed})
+	for _, name := range c.instanceNames() {
+		if name == changed || !dependents[name] {
+			continue
+		}
+		listener, ok := c.constructedInstance(name).(ConfigChangeListener)
+		if !ok {
+			continue
+		}
+		if err := listener.OnConfigChange(ctx, changed); err != nil {
+			c.options.logger.Warn("alice: failed to apply config change", "instance", name, "changed", changed,
+				"error", err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package alice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type configListener struct {
+	name string
+	log  *lifecycleLog
+	fail bool
+}
+
+func (l *configListener) OnConfigChange(ctx context.Context, changed string) error {
+	l.log.add(l.name + " " + changed)
+	if l.fail {
+		return errors.New("apply failed")
+	}
+	return nil
+}
+
+func TestConfigChange(t *testing.T) {
+	value := "v1"
+	config := MustDynamic[string](DynamicProviderFunc[string](func(ctx context.Context) (string, error) {
+		return value, nil
+	}), 0)
+	log := &lifecycleLog{}
+	configModule := NewModule("config").
+		Provide("Config", func() *Dynamic[string] { return config }).
+		Build()
+	var dynamicConfig *Dynamic[string]
+	clientModule := NewModule("client").
+		RequireNamed("Config", &dynamicConfig).
+		Provide("Client", func() *configListener { return &configListener{name: "client", log: log, fail: true} }).
+		Build()
+	var client *configListener
+	serverModule := NewModule("server").
+		RequireNamed("Client", &client).
+		Provide("Server", func() *configListener { return &configListener{name: "server", log: log} }).
+		Build()
+	unrelated := NewModule("unrelated").
+		Provide("Unrelated", func() *configListener { return &configListener{name: "unrelated", log: log} }).
+		Build()
+	var buf bytes.Buffer
+	c := CreateContainerWithOptions([]Module{serverModule, unrelated, clientModule, configModule},
+		WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+
+	ctx := context.Background()
+	config.Refresh(ctx)
+	if events := log.get(); len(events) != 0 {
+		t.Errorf("bad events before Start(): got %v, expected empty", events)
+	}
+
+	if err := c.(Lifecycle).Start(ctx); err != nil {
+		t.Fatalf("bad error after Start(): got %v, expected nil", err)
+	}
+	value = "v2"
+	config.Refresh(ctx)
+	expected := []string{"client Config", "server Config"}
+	if events := log.get(); !reflect.DeepEqual(events, expected) {
+		t.Errorf("bad events after Refresh(): got %v, expected %v", events, expected)
+	}
+	if !strings.Contains(buf.String(), `failed to apply config change" instance=Client changed=Config`) {
+		t.Errorf("bad log after Refresh(): got %s", buf.String())
+	}
+
+	c.(Lifecycle).Stop(ctx)
+	config.Refresh(ctx)
+	if events := log.get(); len(events) != 2 {
+		t.Errorf("bad events after Stop(): got %v, expected %v", events, expected)
+	}
+}
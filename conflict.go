@@ -0,0 +1,196 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Conflict describes the instances conflicting on a name, or on a type when they are associated by type. A type
+// conflict is either on a type declared by multiple instances, or on an interface type no instance declares, which
+// multiple instances are assignable to.
+type Conflict struct {
+	// Name is the conflicting name, or empty for a type conflict.
+	Name string
+	// Type is the conflicting type for a type conflict, or nil otherwise.
+	Type reflect.Type
+	// Candidates are the conflicting instances in the order of the modules and their instance methods.
+	Candidates []InstanceInfo
+}
+
+// ConflictPolicy resolves a conflict by returning the index of the winning candidate. It could return -1 to keep the
+// default behavior, which reports duplicated names as errors and ambiguous types when they are associated by type, or
+// an error to fail the container creation.
+//
+// For a name conflict, the other candidates are removed from the container. For a type conflict, all candidates are
+// kept and could be retrieved by name, while the winner is used when the type is associated or retrieved by type.
+type ConflictPolicy func(conflict Conflict) (int, error)
+
+// FirstWins is a conflict policy picking the candidate defined first.
+func FirstWins(conflict Conflict) (int, error) {
+	return 0, nil
+}
+
+// LastWins is a conflict policy picking the candidate defined last, so later modules supersede earlier ones.
+func LastWins(conflict Conflict) (int, error) {
+	return len(conflict.Candidates) - 1, nil
+}
+
+// WithConflictPolicy returns an option which resolves conflicting names and types by the policy. Without the option,
+// conflicts fail the container creation. If the option is specified multiple times, the policies are consulted from
+// the last one, until one of them picks a winner or returns an error, so policies added for a purpose, such as test
+// mocks, don't replace the ones of the application.
+func WithConflictPolicy(policy ConflictPolicy) Option {
+	return func(o *options) {
+		previous := o.conflictPolicy
+		if previous == nil {
+			o.conflictPolicy = policy
+			return
+		}
+		o.conflictPolicy = func(conflict Conflict) (int, error) {
+			winner, err := policy(conflict)
+			if err != nil || winner >= 0 {
+				return winner, err
+			}
+			return previous(conflict)
+		}
+	}
+}
+
+// conflictCandidate is an instance conflicting with others.
+type conflictCandidate struct {
+	rm       *reflectedModule
+	instance *instanceMethod
+}
+
+// resolveNameConflicts removes the instances losing name conflicts from their modules. Names which stay duplicated
+// are reported by computeProviders.
+func (g *graph) resolveNameConflicts() error {
+	byName := make(map[string][]conflictCandidate)
+	var names []string
+	for _, rm := range g.modules {
+		for _, instance := range rm.instances {
+			if _, ok := byName[instance.name]; !ok {
+				names = append(names, instance.name)
+			}
+			byName[instance.name] = append(byName[instance.name], conflictCandidate{rm: rm, instance: instance})
+		}
+	}
+
+	var errs []error
+	for _, name := range names {
+		candidates := byName[name]
+		if len(candidates) < 2 {
+			continue
+		}
+		winner, err := g.resolveConflict(Conflict{Name: name}, candidates)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if winner < 0 {
+			continue
+		}
+		for i, c := range candidates {
+			if i != winner {
+				c.rm.instances = removeInstanceMethod(c.rm.instances, c.instance)
+			}
+		}
+	}
+	return joinErrors(errs)
+}
+
+// resolveTypeConflicts figures out the winning instance names of the types declared by multiple instances.
+func (g *graph) resolveTypeConflicts() error {
+	byType := make(map[reflect.Type][]conflictCandidate)
+	var types []reflect.Type
+	for _, rm := range g.modules {
+		for _, instance := range rm.instances {
+			if _, ok := byType[instance.tp]; !ok {
+				types = append(types, instance.tp)
+			}
+			byType[instance.tp] = append(byType[instance.tp], conflictCandidate{rm: rm, instance: instance})
+		}
+	}
+
+	var errs []error
+	for _, t := range types {
+		candidates := byType[t]
+		if len(candidates) < 2 {
+			continue
+		}
+		winner, err := g.resolveConflict(Conflict{Type: t}, candidates)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if winner >= 0 {
+			g.typeWinners[t] = candidates[winner].instance.name
+		}
+	}
+	return joinErrors(errs)
+}
+
+// resolveAssignableConflict calls the conflict policy when no instance declares a dependency type and multiple
+// instances are assignable to it. The winner is recorded like the ones of the declared types. It returns false if
+// there is no such conflict, or the policy keeps the default behavior.
+func (g *graph) resolveAssignableConflict(depType reflect.Type) (bool, error) {
+	if g.options.conflictPolicy == nil || depType.Kind() != reflect.Interface {
+		return false, nil
+	}
+	var candidates []conflictCandidate
+	for _, rm := range g.modules {
+		for _, instance := range rm.instances {
+			if instance.tp.AssignableTo(depType) {
+				candidates = append(candidates, conflictCandidate{rm: rm, instance: instance})
+			}
+		}
+	}
+	if len(candidates) < 2 {
+		return false, nil
+	}
+	winner, err := g.resolveConflict(Conflict{Type: depType}, candidates)
+	if err != nil || winner < 0 {
+		return false, err
+	}
+	g.typeWinners[depType] = candidates[winner].instance.name
+	return true, nil
+}
+
+// resolveConflict calls the conflict policy, validating the result.
+func (g *graph) resolveConflict(conflict Conflict, candidates []conflictCandidate) (int, error) {
+	if g.options.conflictPolicy == nil {
+		return -1, nil
+	}
+	for _, c := range candidates {
+		conflict.Candidates = append(conflict.Candidates, InstanceInfo{
+			Name:        c.instance.name,
+			Type:        c.instance.tp,
+			Module:      c.rm.name,
+			Description: c.instance.description,
+		})
+	}
+
+	subject := conflict.Name
+	if subject == "" {
+		subject = conflict.Type.String()
+	}
+	winner, err := g.options.conflictPolicy(conflict)
+	if err != nil {
+		return -1, fmt.Errorf("conflict on %s: %s", subject, err.Error())
+	}
+	if winner >= len(candidates) {
+		return -1, fmt.Errorf("conflict on %s: winner %d is out of %d candidates", subject, winner, len(candidates))
+	}
+	return winner, nil
+}
+
+// removeInstanceMethod returns the instance methods without the specified one.
+func removeInstanceMethod(instances []*instanceMethod, removed *instanceMethod) []*instanceMethod {
+	var kept []*instanceMethod
+	for _, instance := range instances {
+		if instance != removed {
+			kept = append(kept, instance)
+		}
+	}
+	return kept
+}
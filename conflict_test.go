@@ -0,0 +1,135 @@
+package alice
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func conflictModules() []Module {
+	first := NewModule("first").
+		Provide("D1", func() D1 { return &countedD1{n: 1} }).
+		Build()
+	second := NewModule("second").
+		Provide("D1", func() D1 { return &countedD1{n: 2} }).
+		Provide("OtherD1", func() D1 { return &countedD1{n: 3} }).
+		Build()
+	return []Module{first, second}
+}
+
+func TestConflictPolicy(t *testing.T) {
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	tests := []struct {
+		policy       ConflictPolicy
+		byName       int
+		byType       int
+		numInstances int
+	}{
+		{FirstWins, 1, 1, 2},
+		{LastWins, 2, 3, 2},
+	}
+	for _, test := range tests {
+		for _, lazy := range []bool{false, true} {
+			opts := []Option{WithConflictPolicy(test.policy)}
+			if lazy {
+				opts = append(opts, WithLazy())
+			}
+			c := CreateContainerWithOptions(conflictModules(), opts...)
+			if n := c.InstanceByName("D1").(*countedD1).n; n != test.byName {
+				t.Errorf("bad instance after InstanceByName(): got %d, expected %d", n, test.byName)
+			}
+			if n := c.Instance(d1Type).(*countedD1).n; n != test.byType {
+				t.Errorf("bad instance after Instance(): got %d, expected %d", n, test.byType)
+			}
+			if n := len(c.(Introspector).Instances()); n != test.numInstances {
+				t.Errorf("bad number of instances after CreateContainer(): got %d, expected %d", n,
+					test.numInstances)
+			}
+		}
+	}
+}
+
+func TestConflictPolicy_Custom(t *testing.T) {
+	var d1 D1
+	consumer := NewModule("consumer").Require(&d1).Build()
+	policy := func(conflict Conflict) (int, error) {
+		if conflict.Name != "" {
+			return -1, nil
+		}
+		for i, candidate := range conflict.Candidates {
+			if candidate.Name == "OtherD1" {
+				return i, nil
+			}
+		}
+		return -1, errors.New("no OtherD1")
+	}
+	second := conflictModules()[1]
+	CreateContainerWithOptions([]Module{second, consumer}, WithConflictPolicy(policy))
+	if n := d1.(*countedD1).n; n != 3 {
+		t.Errorf("bad dependency after CreateContainer(): got %d, expected %d", n, 3)
+	}
+
+	if err := ValidateWithOptions(conflictModules(), WithConflictPolicy(policy)); err == nil {
+		t.Error("expected error for duplicated name kept by the policy")
+	}
+	failing := func(conflict Conflict) (int, error) {
+		return 0, errors.New("conflicts are not allowed")
+	}
+	if err := ValidateWithOptions(conflictModules(), WithConflictPolicy(failing)); err == nil {
+		t.Error("expected error from the policy")
+	}
+}
+
+func TestConflictPolicy_Assignable(t *testing.T) {
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	assignableModules := func(d1 *D1) []Module {
+		return []Module{
+			NewModule("impl").Provide("Impl", func() *D1Impl { return &D1Impl{} }).Build(),
+			NewModule("counted").Provide("Counted", func() *countedD1 { return &countedD1{n: 1} }).Build(),
+			NewModule("consumer").Require(d1).Build(),
+		}
+	}
+
+	var conflicts []Conflict
+	policy := func(conflict Conflict) (int, error) {
+		conflicts = append(conflicts, conflict)
+		return LastWins(conflict)
+	}
+	var d1 D1
+	c := CreateContainerWithOptions(assignableModules(&d1), WithConflictPolicy(policy))
+	if _, ok := d1.(*countedD1); !ok {
+		t.Errorf("bad dependency after CreateContainer(): got %T, expected *countedD1", d1)
+	}
+	if _, ok := c.Instance(d1Type).(*countedD1); !ok {
+		t.Errorf("bad instance after Instance(): got %T, expected *countedD1", c.Instance(d1Type))
+	}
+	if len(conflicts) != 1 || conflicts[0].Type != d1Type || len(conflicts[0].Candidates) != 2 {
+		t.Errorf("bad conflicts after CreateContainer(): got %+v, expected 1 conflict on D1 with 2 candidates",
+			conflicts)
+	}
+
+	if err := Validate(assignableModules(&d1)...); err == nil {
+		t.Error("expected error for ambiguous assignable types without conflict policy")
+	}
+}
+
+func TestConflictPolicy_Multiple(t *testing.T) {
+	var names []string
+	named := func(conflict Conflict) (int, error) {
+		if conflict.Name == "" {
+			return -1, nil
+		}
+		names = append(names, conflict.Name)
+		return LastWins(conflict)
+	}
+	c := CreateContainerWithOptions(conflictModules(), WithConflictPolicy(FirstWins), WithConflictPolicy(named))
+	if n := c.InstanceByName("D1").(*countedD1).n; n != 2 {
+		t.Errorf("bad instance after InstanceByName(): got %d, expected %d", n, 2)
+	}
+	if n := c.Instance(reflect.TypeOf((*D1)(nil)).Elem()).(*countedD1).n; n != 2 {
+		t.Errorf("bad instance after Instance(): got %d, expected %d", n, 2)
+	}
+	if len(names) != 1 || names[0] != "D1" {
+		t.Errorf("bad conflicts of the last policy: got %v, expected [D1]", names)
+	}
+}
@@ -2,109 +2,670 @@
 package alice
 
 import (
-	"fmt"
+	"context"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // CreateContainer creates a new instance of container with specified modules. It panics if any of the module is
 // invalid. This is the only way to create a container. Most applications call it only once during bootstrap.
 func CreateContainer(modules ...Module) Container {
+	return CreateContainerWithOptions(modules)
+}
+
+// CreateContainerWithOptions creates a new instance of container like CreateContainer, with options customizing
+// its behavior.
+func CreateContainerWithOptions(modules []Module, opts ...Option) Container {
 	c := &container{
 		modules: modules,
+		options: newOptions(opts...),
 	}
 	c.populate()
 	return c
 }
 
 // Container defines the interface of an instance container. It initializes instances based on dependencies,
-// and provides APIs to retrieve instances by type or name.
+// and provides APIs to retrieve instances by type or name. It is kept minimal, so alternative implementations, such as
+// test fixtures, are easy to write. Other features are provided by extension interfaces, like Lifecycle,
+// Introspector, Rebuilder and Scoper, discovered by type assertions.
 type Container interface {
 	// Instance returns an instance by type. It panics when no instance is found,
-	// or multiple instances are found for the same type.
+	// or multiple instances are found for the same type. It is kept for compatibility, and is the same as
+	// MustInstance unless the failure policy is changed by WithFailurePolicy.
 	Instance(t reflect.Type) interface{}
-	// InstanceByName returns an instance by name. It panics when no instance is found.
+	// InstanceByName returns an instance by name. It panics when no instance is found. It is kept for compatibility,
+	// and is the same as MustInstanceByName unless the failure policy is changed by WithFailurePolicy.
 	InstanceByName(name string) interface{}
+	// Resolve returns an instance by type. It returns *LookupError when no instance is found, or multiple instances
+	// are found for the same type, and *ConstructionError when the instance fails to be constructed.
+	Resolve(t reflect.Type) (interface{}, error)
+	// ResolveByName returns an instance by name. It returns errors like Resolve.
+	ResolveByName(name string) (interface{}, error)
+	// MustInstance is like Resolve but panics with the error.
+	MustInstance(t reflect.Type) interface{}
+	// MustInstanceByName is like ResolveByName but panics with the error.
+	MustInstanceByName(name string) interface{}
 }
 
-// container is an implementation of Container interface. It is not thread-safe.
+// Lifecycle is an extension interface of Container to construct, start and stop instances. The containers created by
+// CreateContainer implement it:
+//
+//	if lc, ok := c.(alice.Lifecycle); ok {
+//		err = lc.Start(ctx)
+//	}
+type Lifecycle interface {
+	// Warm constructs the instances with the specified names ahead of time, or all instances if no name is
+	// specified. It is mostly useful in lazy mode, and waits for background instances otherwise. It returns error
+	// if any instance fails to be constructed or the context is done.
+	Warm(ctx context.Context, names ...string) error
+	// Start starts the instances implementing Starter in instantiation order, constructing them if needed. It
+	// returns the first error as *StartError, including the failure to construct an instance, doesn't start the
+	// remaining instances, and stops the started ones in reverse order.
+	Start(ctx context.Context) error
+	// Stop stops the constructed instances implementing Stopper in reverse instantiation order, so an instance is
+	// stopped before its dependencies. It stops all of them even if some fail, and returns the errors, including an
+	// error if any scope created from the container is not closed. WithStopTimeouts bounds each instance.
+	Stop(ctx context.Context) error
+}
+
+// Introspector is an extension interface of Container to inspect the wiring. The containers created by
+// CreateContainer implement it.
+type Introspector interface {
+	// Plan returns the instances in instantiation order, with the modules providing them and their dependencies.
+	Plan() []PlannedInstance
+	// Order returns the references of instances in instantiation order.
+	Order() []InstanceRef
+	// Instances returns the information of all instances in instantiation order.
+	Instances() []InstanceInfo
+	// Modules returns the information of all modules in instantiation order.
+	Modules() []ModuleInfo
+	// Constructed returns the names of the constructed instances in instantiation order.
+	Constructed() []string
+	// Pending returns the names of the instances not constructed yet in instantiation order, e.g. in lazy mode.
+	Pending() []string
+	// Export encodes the constructed value instances, such as configurations, as JSON keyed by instance names.
+	// Instances referring to live resources, like pointers and interfaces, are excluded. The result could be used to
+	// seed another container by WithSeed.
+	Export() ([]byte, error)
+	// Fingerprint returns a stable hash of the wiring, including the modules, the provided instance names and types,
+	// and the dependencies. It changes only if the wiring changes, so it could be logged and compared across
+	// deployments, or asserted in tests.
+	Fingerprint() string
+	// Unused returns the names of instances that no module depends on and that have never been retrieved from the
+	// container, sorted by name. They are candidates for pruning.
+	Unused() []string
+	// DependentsOf returns the names of the instances depending on the instance with the specified name directly or
+	// transitively, in instantiation order, e.g. to find what is affected by changing it. As dependencies are
+	// injected into module fields, all instances of a depending module are dependents. It returns error if the
+	// instance is not defined.
+	DependentsOf(name string) ([]string, error)
+	// DependenciesOf returns the names of the instances the instance with the specified name depends on directly or
+	// transitively, in instantiation order. As for DependentsOf, the dependencies of its module are included. It
+	// returns error if the instance is not defined.
+	DependenciesOf(name string) ([]string, error)
+	// Degradations returns the failures of non-critical instances, which are replaced by their substitutes with
+	// WithDegradedStartup, in the order they happened.
+	Degradations() []Degradation
+	// Explain returns a human-readable trace of how an instance of the type would be resolved: the exact type
+	// matches, the assignable candidates considered and why each is accepted or rejected, and the providing modules.
+	// It doesn't construct any instance.
+	Explain(t reflect.Type) string
+	// ExplainName is like Explain, but for an instance resolved by name.
+	ExplainName(name string) string
+	// InstanceByTypeName returns an instance by the canonical name of its type, which includes the full package path,
+	// e.g. "github.com/magic003/alice/example.Store" or "*net/http.Client", for tooling like debug consoles which
+	// only has string identifiers. The type must be declared by an instance method or depended on by type. It returns
+	// errors like Resolve.
+	InstanceByTypeName(name string) (interface{}, error)
+}
+
+// Rebuilder is an extension interface of Container to rebuild instances or containers from the same modules. The
+// containers created by CreateContainer implement it.
+type Rebuilder interface {
+	// Reset discards the instances with the specified names and all instances depending on them, so they are
+	// constructed again. In lazy mode, they are constructed on next use; otherwise, they are constructed right away.
+	// Accessors of the container retrieve them again. It is intended for tests and must not be called concurrently
+	// with retrievals. It panics if any name is not defined.
+	Reset(names ...string)
+	// Without creates a new container with the same options from the modules of this container, excluding the
+	// modules of the specified types, e.g. to run a trimmed-down variant without metrics or background jobs locally.
+	// The other modules are copied, so the new container doesn't affect this one. It returns error if any type is not
+	// a module of this container, a remaining built module declares dependency targets by pointers, which can't be
+	// copied, or the remaining modules are invalid or fail to be constructed. The plan of the new container is not
+	// verified against WithPinnedPlan, nor printed by WithPrintPlanAndExit.
+	Without(moduleTypes ...reflect.Type) (Container, error)
+}
+
+// Scoper is an extension interface of Container to create views and child scopes. The containers created by
+// CreateContainer, and the forks and scopes of them, implement it.
+type Scoper interface {
+	// Fork creates a cheap view of the container resolving the overridden names and types to alternate instances,
+	// e.g. to swap implementations per request for A/B testing. All other instances are shared with this container,
+	// and instances depending on the overridden ones are not rebuilt. The other methods are delegated to this
+	// container.
+	Fork(overrides ...Override) Container
+	// NewScope creates a child scope resolving the overridden instances like Fork. The scope must be closed when it
+	// is no longer used, e.g. when a request finishes.
+	NewScope(overrides ...Override) Scope
+	// ScopeStats returns the usage statistics of the scopes created from the container.
+	ScopeStats() ScopeStats
+	// ReadOnlyView creates a restricted view of the container, e.g. to pass into plugin code. The view only resolves
+	// the allowed names, and the types declared by their instance methods. Other instances are reported as
+	// *LookupError with ErrNotAllowed. The view implements no extension interface.
+	ReadOnlyView(allowedNames ...string) Container
+}
+
+// extendedContainer is a container implementing all extension interfaces.
+type extendedContainer interface {
+	Container
+	Lifecycle
+	Introspector
+	Rebuilder
+	Scoper
+}
+
+var _ extendedContainer = (*container)(nil)
+
+// container is an implementation of Container interface. Retrieving instances is safe for concurrent use.
 type container struct {
 	modules []Module
+	options options
+	// reflected contains the reflected modules in instantiation order.
+	reflected []*reflectedModule
+	// shortNames maps short instance names to fully qualified names if namespaces are enabled.
+	shortNames *shortNames
+	// graph is the dependency graph of the modules.
+	graph *graph
+	// scopes tracks the open scopes created from the container.
+	scopes scopeTracker
 
+	// mu guards the instance maps and the states of pending and lazy instances.
+	mu             sync.Mutex
 	instanceByName map[string]interface{}
 	instanceByType map[reflect.Type][]interface{}
+	// interfaces indexes the constructed instances by the interfaces known from the graph.
+	interfaces interfaceIndex
+	// typeNames indexes the types known from the graph by their canonical names.
+	typeNames typeNameIndex
+	// rolledBack contains the names of the instances stopped by the rollback of the last Start.
+	rolledBack map[string]bool
+	// adaptedByType contains the dependencies adapted by the adapters, keyed by the types they are adapted to.
+	adaptedByType map[reflect.Type]*adaptation
+	// switches contains the gated instances switched each time they are resolved, keyed by their names.
+	switches map[string]*gateSwitch
+	// pending contains the instances being constructed in background. They are moved to instanceByName and
+	// instanceByType once they are needed.
+	pending map[string]*pendingInstance
+	// lazyByName contains all instances in lazy mode. It is nil otherwise.
+	lazyByName map[string]*lazyInstance
+	// lazyModules contains all modules in instantiation order in lazy mode. It is nil otherwise.
+	lazyModules []*lazyModule
+	// budgetErrors are the overruns of the startup budget.
+	budgetErrors []error
+	// retrievedByName and retrievedByType record the retrievals from the retrieval APIs.
+	retrievedByName map[string]bool
+	retrievedByType map[reflect.Type]bool
+	// constructions tracks the background and lazy instances under construction.
+	constructions *constructions
+	// degradations are the failures of non-critical instances.
+	degradations []Degradation
+	// changeSubscriptions cancel the subscriptions to the changes of dynamic instances.
+	changeSubscriptions map[string]func()
+	// resets counts the calls to Reset, so accessors drop their cached instances.
+	resets atomic.Uint64
+}
+
+// pendingInstance is an instance being constructed on a background goroutine.
+type pendingInstance struct {
+	tp   reflect.Type
+	done chan struct{}
+
+	instance interface{}
+	// recovered is the value recovered if the instance method panics.
+	recovered interface{}
+}
+
+// construct calls the instance method and records the result.
+func (p *pendingInstance) construct(call func() interface{}) {
+	defer close(p.done)
+	defer func() {
+		p.recovered = recover()
+	}()
+	p.instance = call()
 }
 
 func (c *container) Instance(t reflect.Type) interface{} {
-	return c.findInstanceByType(t)
+	return c.options.retrieve(t, func() interface{} {
+		return c.MustInstance(t)
+	})
 }
 
 func (c *container) InstanceByName(name string) interface{} {
-	return c.findInstanceByName(name)
+	return c.options.retrieve(nil, func() interface{} {
+		return c.MustInstanceByName(name)
+	})
 }
 
 func (c *container) populate() {
-	rms := c.reflectModules(c.modules)
-	g, err := createGraph(rms...)
-	if err != nil {
-		panic(err)
-	}
+	start := time.Now()
+	orderedRms, err := c.plan()
+	c.populatePlanned(start, orderedRms, err)
+}
 
-	orderedRms, err := g.instantiationOrder()
+// populatePlanned instantiates the modules planned since start, or panics with the error of the plan.
+func (c *container) populatePlanned(start time.Time, orderedRms []*reflectedModule, err error) {
+	if c.options.errorFormatter != nil {
+		defer c.formatPanic()
+	}
+	if c.options.failureDump != nil {
+		defer func() {
+			if r := recover(); r != nil {
+				c.dumpFailure(r)
+				panic(r)
+			}
+		}()
+	}
+	if c.options.planOutput != nil {
+		c.printPlanAndExit(orderedRms, err)
+	}
+	if err == nil && c.options.pinnedPlan != nil {
+		err = c.verifyPinnedPlan(orderedRms)
+	}
 	if err != nil {
 		panic(err)
 	}
 
+	c.reflected = orderedRms
+	c.warnDeprecations()
+	if c.options.namespaces {
+		c.shortNames = newShortNames(orderedRms)
+	}
+	c.retrievedByName = make(map[string]bool)
+	c.retrievedByType = make(map[reflect.Type]bool)
 	c.instanceByName = make(map[string]interface{})
 	c.instanceByType = make(map[reflect.Type][]interface{})
-	for _, rm := range orderedRms {
-		c.instantiateModule(rm)
+	c.interfaces = newInterfaceIndex(orderedRms)
+	c.typeNames = newTypeNameIndex(orderedRms, c.interfaces)
+	c.adaptedByType = make(map[reflect.Type]*adaptation)
+	c.pending = make(map[string]*pendingInstance)
+	c.constructions = newConstructions()
+	if c.options.lazy {
+		c.prepareLazy(orderedRms)
+	} else {
+		for _, rm := range orderedRms {
+			c.instantiateModule(rm)
+		}
+	}
+	if c.options.budget != nil {
+		c.checkTotalBudget(time.Since(start))
 	}
 }
 
+// plan reflects the modules, validates the dependency graph and returns the modules in instantiation order. All
+// problems are reported together: the graph is built from the modules reflected successfully, and checked for cycles
+// even if some dependencies are invalid.
+func (c *container) plan() ([]*reflectedModule, error) {
+	var errs []error
+	rms, err := c.reflectModules(c.modules)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if err := checkProxies(&c.options, rms); err != nil {
+		errs = append(errs, err)
+	}
+	if c.options.seed != nil {
+		if err := applySeed(c.options.seed, rms); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	g, err := createGraphWithOptions(c.options, rms...)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	orderedRms, err := g.instantiationOrder()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, joinErrors(errs)
+	}
+	c.graph = g
+	return orderedRms, nil
+}
+
 func (c *container) instantiateModule(rm *reflectedModule) {
+	c.injectModule(rm)
+	c.constructions.construct("module "+rm.name, func() {
+		c.migrateModule(rm)
+	})
+
+	for _, instanceMethod := range rm.instances {
+		if instanceMethod.background {
+			p := &pendingInstance{
+				tp:   instanceMethod.tp,
+				done: make(chan struct{}),
+			}
+			c.mu.Lock()
+			c.pending[instanceMethod.name] = p
+			c.mu.Unlock()
+			im := instanceMethod
+			go p.construct(func() interface{} {
+				return c.constructInstance(im)
+			})
+			continue
+		}
+
+		instance := c.constructInstance(instanceMethod)
+		c.addInstance(instanceMethod.name, instanceMethod.tp, instance)
+	}
+}
+
+// injectModule injects the dependencies of a module, tracking it in the construction stack.
+func (c *container) injectModule(rm *reflectedModule) {
+	c.constructions.construct("module "+rm.name, func() {
+		c.injectDependencies(rm)
+	})
+}
+
+// constructInstance calls an instance method, tracking it in the construction stack.
+func (c *container) constructInstance(im *instanceMethod) interface{} {
+	var instance interface{}
+	c.constructions.construct(im.name, func() {
+		start := time.Now()
+		instance = c.callInstanceMethod(im)
+		if c.options.budget != nil {
+			c.checkInstanceBudget(im.name, time.Since(start))
+		}
+	})
+	return instance
+}
+
+// callInstanceMethod calls an instance method with its parameters resolved by type, and returns the instance.
+func (c *container) callInstanceMethod(im *instanceMethod) (instance interface{}) {
+	if im.degradation != nil && c.options.degraded {
+		defer c.degrade(im, &instance)
+	}
+	if im.isolation != nil {
+		return c.isolatedInstance(im)
+	}
+	return c.buildInstance(im)
+}
+
+// buildInstance calls the instance method, and returns the instance adapted by the gate and the post-processors.
+func (c *container) buildInstance(im *instanceMethod) (instance interface{}) {
+	if im.gate != nil && c.switchedOnResolution(im) {
+		return c.switchInstance(im)
+	}
+	if im.gate != nil {
+		instance = c.gatedInstance(im)
+	} else {
+		instance = c.callConstructor(im)
+	}
+	if im.synchronized {
+		instance = c.synchronizedInstance(im, instance)
+	}
+	instance = c.postProcess(im, instance)
+	c.checkNil(im, instance)
+	return instance
+}
+
+// callConstructor calls the constructor of an instance method ignoring its gate.
+func (c *container) callConstructor(im *instanceMethod) interface{} {
+	consumer := func() *reflectedModule {
+		return c.moduleOf(im)
+	}
+	if im.paramsStruct != nil {
+		return c.invoke(im, []reflect.Value{c.buildParams(im, consumer)})
+	}
+	var args []reflect.Value
+	for _, param := range im.params {
+		instance := c.hookInjection(c.findDependencyByType(param, consumer), paramSite(consumer, im, "", "", param))
+		args = append(args, instanceValue(instance, param))
+	}
+	return c.invoke(im, args)
+}
+
+// invoke calls the instance method with the resolved arguments in its sandbox. The sandbox is entered after the
+// dependencies are constructed, so they could be constructed on other goroutines, e.g. isolated ones, without waiting
+// for the sandbox held by this one. The failure of a non-critical instance is marked as a constructorFailure, so
+// degrade tells it from the failures of its dependencies.
+func (c *container) invoke(im *instanceMethod, args []reflect.Value) interface{} {
+	if im.degradation != nil && c.options.degraded {
+		defer func() {
+			if r := recover(); r != nil {
+				panic(constructorFailure{recovered: r})
+			}
+		}()
+	}
+	if im.sandbox != nil {
+		defer im.sandbox.enter()()
+	}
+	return im.method.Call(args)[0].Interface()
+}
+
+// injectDependencies sets the dependency fields of a module.
+func (c *container) injectDependencies(rm *reflectedModule) {
 	for _, dep := range rm.namedDepends {
-		instance := c.findInstanceByName(dep.name)
-		dep.field.Set(reflect.ValueOf(instance))
+		dep := dep
+		resolve := func() reflect.Value {
+			instance := c.findInstanceByName(dep.name)
+			instance = c.hookInjection(instance, fieldSite(rm, dep.fieldName, dep.name, dep.field.Type()))
+			return namedValue(rm.name, dep.name, instance, dep.field.Type())
+		}
+		if dep.lazy {
+			settable(dep.field).Set(c.lazyDependency(dep.field.Type(), resolve))
+			continue
+		}
+		settable(dep.field).Set(resolve())
+	}
+	consumer := func() *reflectedModule {
+		return rm
 	}
 	for _, dep := range rm.typedDepends {
-		instance := c.findInstanceByType(dep.tp)
-		dep.field.Set(reflect.ValueOf(instance))
+		dep := dep
+		resolve := func() reflect.Value {
+			instance := c.findDependencyByType(dep.tp, consumer)
+			instance = c.hookInjection(instance, fieldSite(rm, dep.fieldName, "", dep.tp))
+			return instanceValue(instance, dep.tp)
+		}
+		if dep.lazy {
+			settable(dep.field).Set(c.lazyDependency(dep.tp, resolve))
+			continue
+		}
+		settable(dep.field).Set(resolve())
+	}
+	for _, dep := range rm.listDepends {
+		elemType := dep.elemType()
+		if dep.chain {
+			var funcs []reflect.Value
+			for _, name := range dep.names {
+				instance := c.hookInjection(c.findInstanceByName(name), fieldSite(rm, dep.fieldName, name, elemType))
+				funcs = append(funcs, namedValue(rm.name, name, instance, elemType))
+			}
+			settable(dep.field).Set(chainFuncs(elemType, funcs))
+			continue
+		}
+		if dep.keyed {
+			m := reflect.MakeMapWithSize(dep.field.Type(), len(dep.names))
+			for _, name := range dep.names {
+				instance := c.hookInjection(c.findInstanceByName(name), fieldSite(rm, dep.fieldName, name, elemType))
+				m.SetMapIndex(reflect.ValueOf(strings.TrimPrefix(name, dep.prefix)).Convert(dep.field.Type().Key()),
+					namedValue(rm.name, name, instance, elemType))
+			}
+			settable(dep.field).Set(m)
+			continue
+		}
+		list := reflect.MakeSlice(dep.field.Type(), 0, len(dep.names))
+		for _, name := range dep.names {
+			instance := c.hookInjection(c.findInstanceByName(name), fieldSite(rm, dep.fieldName, name, elemType))
+			list = reflect.Append(list, namedValue(rm.name, name, instance, elemType))
+		}
+		settable(dep.field).Set(list)
 	}
+}
 
-	for _, instanceMethod := range rm.instances {
-		instance := instanceMethod.method.Call(nil)[0].Interface()
+// instanceValue returns the reflect.Value of an instance to be assigned to type t. A nil instance becomes the zero
+// value of t.
+func instanceValue(instance interface{}, t reflect.Type) reflect.Value {
+	if instance == nil {
+		return reflect.Zero(t)
+	}
+	return reflect.ValueOf(instance)
+}
 
-		c.instanceByName[instanceMethod.name] = instance
+func (c *container) addInstance(name string, t reflect.Type, instance interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.instanceByName[name] = instance
+	c.interfaces.add(name, instance)
+
+	typedInstances, _ := c.instanceByType[t]
+	typedInstances = append(typedInstances, instance)
+	c.instanceByType[t] = typedInstances
+}
+
+// awaitPending waits for the background instance with the specified name and registers it. It panics if the
+// instance method panics.
+func (c *container) awaitPending(name string, p *pendingInstance) {
+	c.await(name, p.done)
+	if p.recovered != nil {
+		panic(p.recovered)
+	}
 
-		typedInstances, _ := c.instanceByType[instanceMethod.tp]
-		typedInstances = append(typedInstances, instance)
-		c.instanceByType[instanceMethod.tp] = typedInstances
+	c.mu.Lock()
+	_, ok := c.pending[name]
+	delete(c.pending, name)
+	c.mu.Unlock()
+	if ok {
+		c.addInstance(name, p.tp, p.instance)
+	}
+}
+
+// await waits until done is closed. It panics if the wait would never finish because the instance with the specified
+// name is being constructed by the current goroutine, directly or through other goroutines.
+func (c *container) await(name string, done chan struct{}) {
+	select {
+	case <-done:
+		return
+	default:
+	}
+
+	gid, err := c.constructions.await(name)
+	if err != nil {
+		panic(err)
+	}
+	defer c.constructions.awaited(gid)
+	<-done
+}
+
+// awaitPendingByType waits for the background instances whose type could be assigned to the specified type.
+func (c *container) awaitPendingByType(t reflect.Type) {
+	c.mu.Lock()
+	matched := make(map[string]*pendingInstance)
+	for name, p := range c.pending {
+		if p.tp.AssignableTo(t) {
+			matched[name] = p
+		}
+	}
+	c.mu.Unlock()
+
+	for name, p := range matched {
+		c.awaitPending(name, p)
 	}
 }
 
 func (c *container) findInstanceByType(t reflect.Type) interface{} {
-	instances, ok := c.instanceByType[t]
-	if !ok {
-		instances = c.findAssignableInstances(t)
+	if c.options.instrumentation != nil {
+		defer c.observeByType(t, time.Now())
+	}
+	if winner, ok := c.graph.typeWinners[t]; ok {
+		return c.findInstanceByName(winner)
 	}
+	if c.lazyByName != nil {
+		return c.findLazyInstanceByType(t)
+	}
+
+	c.awaitPendingByType(t)
+	c.mu.Lock()
+	instances, exact := c.instanceByType[t]
+	if !exact && !c.options.strict {
+		if names, indexed := c.interfaces[t]; indexed {
+			instances = c.instancesOf(names)
+		} else {
+			instances = c.findAssignableInstances(t)
+		}
+	}
+	s := c.switchOfType(t, exact)
+	c.mu.Unlock()
 	if len(instances) == 0 {
-		panic(fmt.Sprintf("instance type %s is not defined", t.Name()))
+		panic(&LookupError{Type: t, Err: ErrNotFound})
 	}
 	if len(instances) > 1 {
-		panic(fmt.Sprintf("instance type %s has more than one instances defined", t.Name()))
+		panic(&LookupError{Type: t, Err: ErrAmbiguous})
 	}
 
+	if s != nil {
+		return c.switched(s)
+	}
 	return instances[0]
 }
 
 func (c *container) findInstanceByName(name string) interface{} {
+	if c.options.instrumentation != nil {
+		defer c.observeByName(name, time.Now())
+	}
+	if c.shortNames != nil {
+		qualified, err := c.shortNames.qualify(name)
+		if err != nil {
+			panic(err)
+		}
+		name = qualified
+	}
+	c.mu.Lock()
+	p, isPending := c.pending[name]
+	li, isLazy := c.lazyByName[name]
+	c.mu.Unlock()
+	if isPending {
+		c.awaitPending(name, p)
+	}
+	if isLazy {
+		c.constructLazy(li)
+	}
+
+	c.mu.Lock()
 	instance, ok := c.instanceByName[name]
+	s := c.switches[name]
+	c.mu.Unlock()
 	if !ok {
-		panic(fmt.Sprintf("instance name %s is not defined", name))
+		panic(&LookupError{Name: name, Err: ErrNotFound})
+	}
+	if s != nil {
+		return c.switched(s)
 	}
 	return instance
 }
 
+func (c *container) observeByType(t reflect.Type, start time.Time) {
+	c.options.instrumentation.ObserveByType(t, time.Since(start))
+}
+
+func (c *container) observeByName(name string, start time.Time) {
+	c.options.instrumentation.ObserveByName(name, time.Since(start))
+}
+
+// instancesOf returns the constructed instances with the names. The caller must hold the lock.
+func (c *container) instancesOf(names []string) []interface{} {
+	instances := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		instances = append(instances, c.instanceByName[name])
+	}
+	return instances
+}
+
 func (c *container) findAssignableInstances(t reflect.Type) []interface{} {
 	var instances []interface{}
 	for _, instance := range c.instanceByName {
@@ -116,14 +677,26 @@ func (c *container) findAssignableInstances(t reflect.Type) []interface{} {
 	return instances
 }
 
-func (c *container) reflectModules(modules []Module) []*reflectedModule {
+func (c *container) reflectModules(modules []Module) ([]*reflectedModule, error) {
 	var rms []*reflectedModule
+	var errs []error
+	capabilities := c.options.capabilitySet()
 	for _, m := range c.modules {
 		rm, err := reflectModule(m)
 		if err != nil {
-			panic(err)
+			errs = append(errs, err)
+			continue
+		}
+		if err := removeUnsatisfiedInstances(rm, capabilities); err != nil {
+			errs = append(errs, err)
+		}
+		if c.options.namingStrategy != nil {
+			applyNamingStrategy(c.options.namingStrategy, rm)
+		}
+		if c.options.namespaces {
+			applyNamespace(rm)
 		}
 		rms = append(rms, rm)
 	}
-	return rms
+	return rms, joinErrors(errs)
 }
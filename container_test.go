@@ -165,6 +165,65 @@ func (m *SelfDependModule) D1() D1 {
 	return &D1Impl{}
 }
 
+type BackgroundModule1 struct {
+	BaseModule
+	release chan struct{}
+}
+
+func (m *BackgroundModule1) D1() D1 {
+	<-m.release
+	return &D1Impl{}
+}
+
+func (m *BackgroundModule1) D2() D2 {
+	return &D2Impl{}
+}
+
+func (m *BackgroundModule1) BackgroundInstances() []string {
+	return []string{"D1"}
+}
+
+type PanicBackgroundModule struct {
+	BaseModule
+}
+
+func (m *PanicBackgroundModule) D1() D1 {
+	panic("failed to create D1")
+}
+
+func (m *PanicBackgroundModule) BackgroundInstances() []string {
+	return []string{"D1"}
+}
+
+// privateModule is created by a factory function and keeps its configuration and dependencies private.
+type privateModule struct {
+	BaseModule
+	prefix string
+	d1     D1 `alice:"D1"`
+}
+
+func newPrivateModule(prefix string) Module {
+	return &privateModule{prefix: prefix}
+}
+
+func (m *privateModule) Greeting() string {
+	if m.d1 == nil {
+		return ""
+	}
+	return m.prefix + " D1"
+}
+
+type ParamModule struct {
+	BaseModule
+}
+
+func (m *ParamModule) D5(d1 D1, d3 D3) *D5Impl {
+	if d1 == nil || d3 == nil {
+		panic("parameters are not injected")
+	}
+	return &D5Impl{}
+}
+
 //***********************************************************
 
 func TestPopulate(t *testing.T) {
@@ -243,7 +302,8 @@ func TestPopulate_PanicOnInvalidModule(t *testing.T) {
 			t.Log(r)
 		}
 	}()
-	c := &container{modules: []Module{nonPointerModule{}}}
+	nonStructModule := nonStructModule("module")
+	c := &container{modules: []Module{&nonStructModule}}
 	c.populate()
 }
 
@@ -382,3 +442,148 @@ func TestCreateContainer(t *testing.T) {
 		t.Errorf("bad instance after CreateContainer(): got %v, expected %v", d1, expectedD1)
 	}
 }
+
+func TestPopulate_BackgroundInstance(t *testing.T) {
+	m := &BackgroundModule1{release: make(chan struct{})}
+	m4 := &M4{}
+
+	c := &container{modules: []Module{m}}
+	c.populate()
+
+	if _, ok := c.pending["D1"]; !ok {
+		t.Errorf("D1 is expected to be pending after populate()")
+	}
+	if _, ok := c.instanceByName["D2"]; !ok {
+		t.Errorf("D2 is expected to be instantiated after populate()")
+	}
+
+	close(m.release)
+	d1 := c.InstanceByName("D1").(D1)
+	expectedD1 := &D1Impl{}
+	if !reflect.DeepEqual(d1, expectedD1) {
+		t.Errorf("bad instance from InstanceByName(): got %v, expected %v", d1, expectedD1)
+	}
+	if len(c.pending) != 0 {
+		t.Errorf("bad pending after InstanceByName(): got %v, expected empty", c.pending)
+	}
+
+	// dependent module waits for the background instance
+	m = &BackgroundModule1{release: make(chan struct{})}
+	close(m.release)
+	c = &container{modules: []Module{m, m4}}
+	c.populate()
+	if !reflect.DeepEqual(m4.D1, expectedD1) {
+		t.Errorf("bad m4.D1 after populate(): got %v, expected %v", m4.D1, expectedD1)
+	}
+}
+
+func TestInstance_BackgroundInstance(t *testing.T) {
+	m := &BackgroundModule1{release: make(chan struct{})}
+	close(m.release)
+	c := &container{modules: []Module{m}}
+	c.populate()
+
+	d1 := c.Instance(reflect.TypeOf((*D1)(nil)).Elem()).(D1)
+	expectedD1 := &D1Impl{}
+	if !reflect.DeepEqual(d1, expectedD1) {
+		t.Errorf("bad instance from Instance(): got %v, expected %v", d1, expectedD1)
+	}
+}
+
+func TestInstanceByName_PanicOnBackgroundInstanceFailure(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected panic for InstanceByName() on background instance failure")
+		} else {
+			t.Log(r)
+		}
+	}()
+	c := &container{modules: []Module{&PanicBackgroundModule{}}}
+	c.populate()
+
+	c.InstanceByName("D1")
+}
+
+func TestPopulate_AnonymousModule(t *testing.T) {
+	m := &struct {
+		BaseModule
+		D1 D1 `alice:""`
+	}{}
+
+	c := &container{modules: []Module{&M1{}, m}}
+	c.populate()
+
+	expectedD1 := &D1Impl{}
+	if !reflect.DeepEqual(m.D1, expectedD1) {
+		t.Errorf("bad m.D1 after populate(): got %v, expected %v", m.D1, expectedD1)
+	}
+}
+
+func TestPopulate_FactoryModule(t *testing.T) {
+	c := &container{modules: []Module{&M1{}, newPrivateModule("hello")}}
+	c.populate()
+
+	greeting := c.InstanceByName("Greeting")
+	if greeting != "hello D1" {
+		t.Errorf("bad instance from InstanceByName(): got %v, expected %v", greeting, "hello D1")
+	}
+}
+
+func TestInstance_PanicOnAssignableTypeInStrictMode(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected panic for Instance() on assignable type in strict mode")
+		} else {
+			t.Log(r)
+		}
+	}()
+
+	c := &container{modules: []Module{&M1{}}, options: newOptions(WithStrict())}
+	c.populate()
+
+	c.Instance(reflect.TypeOf((*D1Impl)(nil)))
+}
+
+func TestPopulate_MethodParams(t *testing.T) {
+	var d5 *D5Impl
+	consumer := NewModule("consumer").Require(&d5).Build()
+	c := &container{modules: []Module{&M1{}, &M4{}, &ParamModule{}, consumer}}
+	c.populate()
+
+	expectedD5 := &D5Impl{}
+	if !reflect.DeepEqual(d5, expectedD5) {
+		t.Errorf("bad d5 after populate(): got %v, expected %v", d5, expectedD5)
+	}
+
+	// parameters are resolved lazily as well
+	c = &container{modules: []Module{&M1{}, &M4{}, &ParamModule{}}, options: newOptions(WithLazy())}
+	c.populate()
+	if d5 := c.InstanceByName("D5"); !reflect.DeepEqual(d5, expectedD5) {
+		t.Errorf("bad instance from InstanceByName(): got %v, expected %v", d5, expectedD5)
+	}
+}
+
+func TestPopulate_ListDepends(t *testing.T) {
+	m := &listModule{}
+	CreateContainer(&M1{}, m)
+	if len(m.Ds) != 2 {
+		t.Fatalf("bad length of list dependency: got %d, expected %d", len(m.Ds), 2)
+	}
+	if _, ok := m.Ds[0].(D2); !ok {
+		t.Errorf("bad first element of list dependency: got %T, expected D2", m.Ds[0])
+	}
+	if _, ok := m.Ds[1].(D1); !ok {
+		t.Errorf("bad second element of list dependency: got %T, expected D1", m.Ds[1])
+	}
+
+	var ds []interface{}
+	built := NewModule("built").RequireNames([]string{"D1", "D2"}, &ds).Build()
+	CreateContainer(&M1{}, built)
+	if len(ds) != 2 {
+		t.Errorf("bad length of list dependency of built module: got %d, expected %d", len(ds), 2)
+	}
+
+	if err := Validate(&listModule{}, &M4{}); err == nil {
+		t.Error("expected error for missing names of list dependency")
+	}
+}
@@ -0,0 +1,62 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// containerKey is the context key of the container.
+type containerKey struct{}
+
+// ErrNoContainer is returned when retrieving an instance from a context without a container.
+var ErrNoContainer = errors.New("no container in context")
+
+// NewContext returns a copy of ctx carrying the container, usually a container scoped to a request, so handlers deep
+// in call stacks could retrieve instances by FromContext without plumbing.
+func NewContext(ctx context.Context, c Container) context.Context {
+	return context.WithValue(ctx, containerKey{}, c)
+}
+
+// ContainerFromContext returns the container carried by ctx, if any.
+func ContainerFromContext(ctx context.Context) (Container, bool) {
+	c, ok := ctx.Value(containerKey{}).(Container)
+	return c, ok
+}
+
+// FromContext returns the instance of type T from the container carried by ctx. It returns ErrNoContainer if ctx
+// carries no container, or error if the instance is not found.
+func FromContext[T any](ctx context.Context) (T, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return fromContext[T](ctx, func(c Container) interface{} {
+		return c.MustInstance(t)
+	})
+}
+
+// NamedFromContext returns the instance with the specified name from the container carried by ctx. It returns
+// ErrNoContainer if ctx carries no container, or error if the instance is not found or it is not of type T.
+func NamedFromContext[T any](ctx context.Context, name string) (T, error) {
+	return fromContext[T](ctx, func(c Container) interface{} {
+		return c.MustInstanceByName(name)
+	})
+}
+
+func fromContext[T any](ctx context.Context, resolve func(Container) interface{}) (value T, err error) {
+	c, ok := ContainerFromContext(ctx)
+	if !ok {
+		return value, ErrNoContainer
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(r)
+		}
+	}()
+	instance := resolve(c)
+	value, ok = instance.(T)
+	if !ok {
+		return value, fmt.Errorf("instance of type %T is not a %s", instance, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return value, nil
+}
@@ -0,0 +1,32 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	c := CreateContainer(&M1{})
+	ctx := NewContext(context.Background(), c)
+
+	if actual, ok := ContainerFromContext(ctx); !ok || actual != c {
+		t.Errorf("bad container after ContainerFromContext(): got %v, %v", actual, ok)
+	}
+	if d1, err := FromContext[D1](ctx); err != nil || d1 == nil {
+		t.Errorf("bad instance after FromContext(): got %v, %v", d1, err)
+	}
+	if d2, err := NamedFromContext[D2](ctx, "D2"); err != nil || d2 == nil {
+		t.Errorf("bad instance after NamedFromContext(): got %v, %v", d2, err)
+	}
+
+	if _, err := FromContext[D3](ctx); err == nil {
+		t.Error("expected error after FromContext() with undefined type")
+	}
+	if _, err := NamedFromContext[D3](ctx, "D1"); err == nil {
+		t.Error("expected error after NamedFromContext() with wrong type")
+	}
+	if _, err := FromContext[D1](context.Background()); !errors.Is(err, ErrNoContainer) {
+		t.Errorf("bad error after FromContext() without container: got %v, expected %v", err, ErrNoContainer)
+	}
+}
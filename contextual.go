@@ -0,0 +1,40 @@
+package alice
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+// ContextualProvider derives a dependency customized for the consumer module from the instance provided by the
+// container, e.g. a logger tagged with the module name.
+type ContextualProvider func(consumer ModuleInfo, base interface{}) interface{}
+
+// WithContextual returns an option which makes the dependencies of type t associated by type, as fields or
+// parameters, derived per consumer module by the provider. The base instance is still provided by a module, and
+// retrieving it from the container returns the base instance as is.
+//
+//	c := alice.CreateContainerWithOptions(modules,
+//		alice.WithContextual(reflect.TypeOf((*slog.Logger)(nil)), alice.ModuleLogger))
+func WithContextual(t reflect.Type, provider ContextualProvider) Option {
+	return func(o *options) {
+		if o.contextual == nil {
+			o.contextual = make(map[reflect.Type]ContextualProvider)
+		}
+		o.contextual[t] = provider
+	}
+}
+
+// ModuleLogger is a contextual provider for *slog.Logger, which adds the consumer module name as the "module"
+// attribute.
+func ModuleLogger(consumer ModuleInfo, base interface{}) interface{} {
+	return base.(*slog.Logger).With("module", consumer.Name)
+}
+
+// contextualize derives the dependency of type t for the consumer if a contextual provider is registered for it.
+func (c *container) contextualize(t reflect.Type, instance interface{}, consumer func() *reflectedModule) interface{} {
+	provider, ok := c.options.contextual[t]
+	if !ok {
+		return instance
+	}
+	return provider(newModuleInfo(consumer()), instance)
+}
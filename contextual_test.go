@@ -0,0 +1,44 @@
+package alice
+
+import (
+	"bytes"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type LoggingModule struct {
+	BaseModule
+	Logger *slog.Logger `alice:""`
+}
+
+func (m *LoggingModule) Logged(logger *slog.Logger) bool {
+	logger.Info("constructed")
+	return true
+}
+
+func TestContextual(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewTextHandler(&buf, nil))
+	loggerType := reflect.TypeOf(base)
+	m := &LoggingModule{}
+	c := CreateContainerWithOptions(
+		[]Module{Values("logging", map[string]interface{}{"Logger": base}), m},
+		WithContextual(loggerType, ModuleLogger))
+
+	if m.Logger == base {
+		t.Error("expected logger derived for the consumer")
+	}
+	if !strings.Contains(buf.String(), "module=LoggingModule") {
+		t.Errorf("bad log of derived logger parameter: got %q, expected module=LoggingModule", buf.String())
+	}
+	buf.Reset()
+	m.Logger.Info("injected")
+	if !strings.Contains(buf.String(), "module=LoggingModule") {
+		t.Errorf("bad log of derived logger field: got %q, expected module=LoggingModule", buf.String())
+	}
+	if instance := c.Instance(loggerType); instance != base {
+		t.Errorf("bad instance from Instance(): got %v, expected %v", instance, base)
+	}
+}
@@ -0,0 +1,81 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// cycleError returns the error of cyclic dependencies. path is the modules visited, ending with the module closing
+// the cycle. Each module provides instances to the next one. The error suggests how to break the cycle at the
+// dependency needing the fewest instances.
+func (g *graph) cycleError(path []*reflectedModule) error {
+	var names []string
+	for _, m := range path {
+		names = append(names, m.name)
+	}
+	message := fmt.Sprintf("cyclic dependencies for modules: %s", strings.Join(names, " -> "))
+
+	// the cycle starts from the first occurrence of the last module
+	last := path[len(path)-1]
+	start := 0
+	for i, m := range path {
+		if m == last {
+			start = i
+			break
+		}
+	}
+
+	var suggestion string
+	fewest := -1
+	for i := start; i < len(path)-1; i++ {
+		provider, dependant := path[i], path[i+1]
+		needed := g.neededInstances(provider, dependant)
+		if fewest >= 0 && len(needed) >= fewest {
+			continue
+		}
+		fewest = len(needed)
+		if provider == dependant {
+			suggestion = fmt.Sprintf("module %s depends on its own instances %s. Move them into a separate module.",
+				provider.name, strings.Join(needed, ", "))
+		} else if proxiable(provider, needed) {
+			suggestion = fmt.Sprintf("module %s depends on module %s for %s. Break the cycle by injecting them "+
+				"lazily into fields tagged like `alice:\"%s,lazy\"`, which retrieve them on first call, by having "+
+				"%s set them on %s through a setter, or by replacing the calls with events %s subscribes to.",
+				dependant.name, provider.name, strings.Join(needed, ", "), needed[0], provider.name, dependant.name,
+				dependant.name)
+		} else {
+			suggestion = fmt.Sprintf("module %s depends on module %s for %s. Break the cycle by retrieving them on "+
+				"first use with alice.NewAccessor instead of injection, by having %s set them on %s "+
+				"through a setter, or by replacing the calls with events %s subscribes to.",
+				dependant.name, provider.name, strings.Join(needed, ", "), provider.name, dependant.name,
+				dependant.name)
+		}
+	}
+	return fmt.Errorf("%s\nsuggestion: %s", message, suggestion)
+}
+
+// proxiable checks if the needed instances of provider are all of function or interface types, so they could be
+// injected into lazy fields.
+func proxiable(provider *reflectedModule, needed []string) bool {
+	for _, name := range needed {
+		instance := findInstanceMethod(provider.instances, name)
+		if instance == nil || instance.tp.Kind() != reflect.Func && instance.tp.Kind() != reflect.Interface {
+			return false
+		}
+	}
+	return len(needed) > 0
+}
+
+// neededInstances returns the sorted names of the instances of provider that dependant depends on.
+func (g *graph) neededInstances(provider *reflectedModule, dependant *reflectedModule) []string {
+	var names []string
+	for _, instance := range provider.instances {
+		if g.dependsOn[dependant][instance.name] {
+			names = append(names, instance.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
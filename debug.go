@@ -0,0 +1,48 @@
+package alice
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// debugInstance is the JSON representation of an instance served by the debug handler.
+type debugInstance struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Module      string `json:"module"`
+	Description string `json:"description,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	State       string `json:"state"`
+}
+
+// DebugHandler returns an http.Handler serving the wiring of a container as JSON, so a running service documents its
+// own instances. It is usually mounted on an internal debug endpoint. A container not implementing Introspector is
+// served as having no instances.
+func DebugHandler(c Container) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var infos []InstanceInfo
+		if in, ok := c.(Introspector); ok {
+			infos = in.Instances()
+		}
+		instances := []debugInstance{}
+		for _, info := range infos {
+			instances = append(instances, debugInstance{
+				Name:        info.Name,
+				Type:        info.Type.String(),
+				Module:      info.Module,
+				Description: info.Description,
+				Deprecated:  info.Deprecated,
+				Replacement: info.Replacement,
+				State:       info.State.String(),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string]interface{}{"instances": instances}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
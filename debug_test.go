@@ -0,0 +1,34 @@
+package alice
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDebugHandler(t *testing.T) {
+	c := CreateContainer(&DescribedModule1{})
+
+	w := httptest.NewRecorder()
+	DebugHandler(c).ServeHTTP(w, httptest.NewRequest("GET", "/debug/alice", nil))
+
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("bad content type: got %s, expected %s", contentType, "application/json")
+	}
+	var body struct {
+		Instances []debugInstance `json:"instances"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unexpected error after decoding response: %s", err.Error())
+	}
+	expected := debugInstance{
+		Name:        "D1",
+		Type:        "alice.D1",
+		Module:      "DescribedModule1",
+		Description: "the D1 implementation",
+		State:       "constructed",
+	}
+	if len(body.Instances) != 2 || body.Instances[0] != expected {
+		t.Errorf("bad instances in response: got %v, expected %v first", body.Instances, expected)
+	}
+}
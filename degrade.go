@@ -0,0 +1,125 @@
+package alice
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// Degradation is a failure constructing a non-critical instance.
+type Degradation struct {
+	// Instance is the name of the instance.
+	Instance string
+	// Err is the failure.
+	Err error
+}
+
+// WithDegradedStartup returns an option which makes failures constructing the instances marked non-critical, by
+// DegradableModule or ModuleBuilder.NonCritical, degrade the container instead of aborting it. The failure is logged
+// and recorded in Introspector.Degradations, and the substitute of the instance is used. It suits edge services
+// preferring a degraded startup to crash loops when optional dependencies are down.
+func WithDegradedStartup() Option {
+	return func(o *options) {
+		o.degraded = true
+	}
+}
+
+// degradation is the substitute of a non-critical instance.
+type degradation struct {
+	// substitute is the function returning the substitute, or invalid for the zero value.
+	substitute reflect.Value
+}
+
+// newDegradation validates the substitute of an instance of type t.
+func newDegradation(t reflect.Type, substitute interface{}) (*degradation, error) {
+	if substitute == nil {
+		return &degradation{}, nil
+	}
+	v := reflect.ValueOf(substitute)
+	if v.Kind() != reflect.Func || v.Type().NumIn() != 0 || v.Type().NumOut() != 1 {
+		return nil, fmt.Errorf("substitute %T is not a function without parameters returning one value", substitute)
+	}
+	if !v.Type().Out(0).AssignableTo(t) {
+		return nil, fmt.Errorf("substitute returns %s, which is not assignable to %s", v.Type().Out(0), t)
+	}
+	return &degradation{substitute: v}, nil
+}
+
+// markNonCriticalInstances marks the instances with the specified names non-critical. It returns error if any name
+// is not an instance of the module, or any substitute is invalid.
+func markNonCriticalInstances(
+	moduleName string, instances []*instanceMethod, substitutes map[string]interface{}) error {
+	for name, substitute := range substitutes {
+		instance := findInstanceMethod(instances, name)
+		if instance == nil {
+			return fmt.Errorf("non-critical instance %s.%s is not defined", moduleName, name)
+		}
+		d, err := newDegradation(instance.tp, substitute)
+		if err != nil {
+			return fmt.Errorf("non-critical instance %s.%s: %s", moduleName, name, err.Error())
+		}
+		instance.degradation = d
+	}
+	return nil
+}
+
+// constructorFailure is the value recovered from the instance method of a non-critical instance, raised again to be
+// recovered by degrade.
+type constructorFailure struct {
+	recovered interface{}
+}
+
+// degrade recovers the failure of the instance method of a non-critical instance, records it, and sets the instance
+// to the substitute. Other failures, such as the ones of its dependencies, are raised again. It must be deferred.
+func (c *container) degrade(im *instanceMethod, instance *interface{}) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	failure, ok := r.(constructorFailure)
+	if !ok {
+		panic(r)
+	}
+	err := recoveredError(failure.recovered)
+	c.options.logger.Warn("alice: non-critical instance failed, using its substitute", "instance", im.name,
+		"error", err)
+	c.mu.Lock()
+	c.degradations = append(c.degradations, Degradation{Instance: im.name, Err: err})
+	c.mu.Unlock()
+	*instance = nil
+	if im.degradation.substitute.IsValid() {
+		*instance = im.degradation.substitute.Call(nil)[0].Interface()
+	}
+}
+
+func (c *container) Degradations() []Degradation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Degradation{}, c.degradations...)
+}
+
+// HealthHandler returns an http.Handler serving the health of a container as JSON, with the status "ok" or
+// "degraded" and the failures of non-critical instances. It always responds with 200, as a degraded container still
+// serves. A container not implementing Introspector is served as ok.
+func HealthHandler(c Container) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var degradations []Degradation
+		if in, ok := c.(Introspector); ok {
+			degradations = in.Degradations()
+		}
+		status := "ok"
+		failures := map[string]string{}
+		for _, d := range degradations {
+			status = "degraded"
+			failures[d.Instance] = d.Err.Error()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string]interface{}{"status": status, "degraded": failures}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package alice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type DegradableModule1 struct {
+	BaseModule
+}
+
+func (m *DegradableModule1) Cache() Greeter {
+	panic(errors.New("cache is down"))
+}
+
+func (m *DegradableModule1) Metrics() *D5Impl {
+	panic("metrics is down")
+}
+
+func (m *DegradableModule1) NonCritical() map[string]interface{} {
+	return map[string]interface{}{
+		"Cache":   func() Greeter { return greeterFunc(func(string) string { return "no-op" }) },
+		"Metrics": nil,
+	}
+}
+
+type invalidDegradableModule struct {
+	BaseModule
+}
+
+func (m *invalidDegradableModule) D1() D1 {
+	return &D1Impl{}
+}
+
+func (m *invalidDegradableModule) NonCritical() map[string]interface{} {
+	return map[string]interface{}{
+		"D1": func() *D2Impl { return &D2Impl{} },
+	}
+}
+
+func TestDegradedStartup(t *testing.T) {
+	var cache Greeter
+	consumer := NewModule("consumer").
+		RequireNamed("Cache", &cache).
+		Provide("Consumer", func() *D2Impl { return &D2Impl{} }).
+		Build()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := CreateContainerWithOptions([]Module{&DegradableModule1{}, consumer}, WithDegradedStartup(),
+		WithLogger(logger))
+	if s := cache.Greet("alice"); s != "no-op" {
+		t.Errorf("bad substitute after CreateContainer(): got %q, expected %q", s, "no-op")
+	}
+	if metrics := c.InstanceByName("Metrics"); metrics != nil {
+		t.Errorf("bad substitute after CreateContainer(): got %v, expected nil", metrics)
+	}
+
+	degradations := c.(Introspector).Degradations()
+	var names []string
+	for _, d := range degradations {
+		names = append(names, d.Instance)
+	}
+	if expected := []string{"Cache", "Metrics"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("bad degradations after CreateContainer(): got %v, expected %v", names, expected)
+	}
+	if !strings.Contains(degradations[0].Err.Error(), "cache is down") {
+		t.Errorf("bad error of degradation: got %v, expected cache is down", degradations[0].Err)
+	}
+
+	w := httptest.NewRecorder()
+	HealthHandler(c).ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+	var health struct {
+		Status   string            `json:"status"`
+		Degraded map[string]string `json:"degraded"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &health); err != nil {
+		t.Fatalf("bad response of HealthHandler(): got %s", w.Body.String())
+	}
+	if health.Status != "degraded" || len(health.Degraded) != 2 {
+		t.Errorf("bad health after ServeHTTP(): got %v", health)
+	}
+}
+
+func TestDegradedStartup_Disabled(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic without WithDegradedStartup()")
+		}
+	}()
+	CreateContainer(&DegradableModule1{})
+}
+
+func TestDegradedStartup_Healthy(t *testing.T) {
+	w := httptest.NewRecorder()
+	HealthHandler(CreateContainer(&M1{})).ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+	if !strings.Contains(w.Body.String(), `"status": "ok"`) {
+		t.Errorf("bad health after ServeHTTP(): got %s", w.Body.String())
+	}
+}
+
+func TestDegradedStartup_Builder(t *testing.T) {
+	m := NewModule("built").
+		Provide("Flaky", func() Greeter { panic("down") }).
+		NonCritical("Flaky", nil).
+		Build()
+	c := CreateContainerWithOptions([]Module{m}, WithDegradedStartup(),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	if len(c.(Introspector).Degradations()) != 1 {
+		t.Errorf("bad degradations after CreateContainer(): got %v", c.(Introspector).Degradations())
+	}
+}
+
+func TestDegradedStartup_CriticalDependency(t *testing.T) {
+	critical := NewModule("critical").
+		Provide("Database", func() *D5Impl { panic("database is down") }).
+		Build()
+	m := NewModule("built").
+		Provide("Report", func(db *D5Impl) Greeter { return nil }).
+		NonCritical("Report", nil).
+		Build()
+	c := CreateContainerWithOptions([]Module{critical, m}, WithLazy(), WithDegradedStartup(),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	func() {
+		defer func() {
+			if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "database is down") {
+				t.Errorf("bad panic from InstanceByName(): got %v, expected the failure of Database", r)
+			}
+		}()
+		c.InstanceByName("Report")
+	}()
+	if len(c.(Introspector).Degradations()) != 0 {
+		t.Errorf("bad degradations after InstanceByName(): got %v, expected none", c.(Introspector).Degradations())
+	}
+}
+
+func TestDegradedStartup_Invalid(t *testing.T) {
+	if err := Validate(&invalidDegradableModule{}); err == nil {
+		t.Error("expected error for substitute of unassignable type")
+	}
+	if err := Validate(NewModule("built").NonCritical("Undefined", nil).Build()); err == nil {
+		t.Error("expected error for undefined non-critical instance")
+	}
+}
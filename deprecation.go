@@ -0,0 +1,19 @@
+package alice
+
+// warnDeprecations logs a warning for each deprecated module and instance.
+func (c *container) warnDeprecations() {
+	if c.options.logger == nil {
+		return
+	}
+	for _, rm := range c.reflected {
+		if rm.deprecated {
+			c.options.logger.Warn("alice: deprecated module", "module", rm.name, "replacement", rm.replacement)
+		}
+		for _, instance := range rm.instances {
+			if instance.deprecated {
+				c.options.logger.Warn("alice: deprecated instance", "module", rm.name, "instance", instance.name,
+					"replacement", instance.replacement)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package alice
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type DeprecatedModule1 struct {
+	BaseModule
+}
+
+func (m *DeprecatedModule1) D1() D1 {
+	return &D1Impl{}
+}
+
+func (m *DeprecatedModule1) D2() D2 {
+	return &D2Impl{}
+}
+
+func (m *DeprecatedModule1) Deprecated() map[string]string {
+	return map[string]string{
+		"D1": "NewD1",
+	}
+}
+
+type DeprecatedModule2 struct {
+	BaseModule
+}
+
+func (m *DeprecatedModule2) D3() D3 {
+	return &D3Impl{}
+}
+
+func (m *DeprecatedModule2) Deprecated() map[string]string {
+	return map[string]string{
+		"": "NewModule2",
+	}
+}
+
+type invalidDeprecatedModule struct {
+	BaseModule
+}
+
+func (m *invalidDeprecatedModule) Deprecated() map[string]string {
+	return map[string]string{
+		"D1": "undefined instance",
+	}
+}
+
+func TestDeprecations(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	built := NewModule("built").
+		Provide("D4", func() D4 { return &D4Impl{} }).
+		Deprecate("D4", "").
+		Build()
+	c := CreateContainerWithOptions([]Module{&DeprecatedModule1{}, &DeprecatedModule2{}, built}, WithLogger(logger))
+
+	expected := map[string]string{
+		"D1": "NewD1",
+		"D3": "NewModule2",
+		"D4": "",
+	}
+	for _, info := range c.(Introspector).Instances() {
+		replacement, deprecated := expected[info.Name]
+		if info.Deprecated != deprecated || info.Replacement != replacement {
+			t.Errorf("bad deprecation of %s: got (%v, %q), expected (%v, %q)", info.Name, info.Deprecated,
+				info.Replacement, deprecated, replacement)
+		}
+	}
+
+	output := buf.String()
+	for _, s := range []string{"instance=D1 replacement=NewD1", "module=DeprecatedModule2 replacement=NewModule2",
+		"module=built instance=D4"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("warning %q is not logged: got %s", s, output)
+		}
+	}
+	if strings.Contains(output, "instance=D2") {
+		t.Errorf("unexpected warning of D2: got %s", output)
+	}
+}
+
+func TestDeprecations_Invalid(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Error("expected panic for undefined deprecated instance")
+		}
+		t.Log(r)
+	}()
+	CreateContainer(&invalidDeprecatedModule{})
+}
+
+func TestModuleBuilder_DeprecateUndefined(t *testing.T) {
+	m := NewModule("built").Deprecate("D1", "").Build()
+	if err := Validate(m); err == nil {
+		t.Error("expected error for undefined deprecated instance")
+	}
+}
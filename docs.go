@@ -0,0 +1,244 @@
+package alice
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// DocFormat is the format of the architecture document written by WriteDocs.
+type DocFormat int
+
+const (
+	// DocMarkdown writes Markdown pages, with the module graph as a Mermaid diagram.
+	DocMarkdown DocFormat = iota
+	// DocHTML writes static HTML pages.
+	DocHTML
+)
+
+// WriteDocs renders the wiring of the container into an architecture document in dir: an index page listing the
+// modules and their dependencies, and a page per module with its description, dependencies, dependents and
+// instances. The descriptions come from modules implementing DescribedModule. The pages could be committed to the
+// repository or published on a portal. The container must implement Introspector.
+func WriteDocs(c Container, dir string, format DocFormat) error {
+	introspector, ok := c.(Introspector)
+	if !ok {
+		return fmt.Errorf("container %T doesn't implement Introspector", c)
+	}
+	var ext string
+	var index, module func(io.Writer, interface{}) error
+	switch format {
+	case DocMarkdown:
+		ext = ".md"
+		index, module = _MarkdownIndexTemplate.Execute, _MarkdownModuleTemplate.Execute
+	case DocHTML:
+		ext = ".html"
+		index, module = _HTMLIndexTemplate.Execute, _HTMLModuleTemplate.Execute
+	default:
+		return fmt.Errorf("unknown doc format %d", format)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	modules := newDocModules(introspector, ext)
+	if err := writeDoc(filepath.Join(dir, "index"+ext), index, modules); err != nil {
+		return err
+	}
+	for _, m := range modules {
+		if err := writeDoc(filepath.Join(dir, m.Page), module, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDoc renders a page into the file.
+func writeDoc(path string, render func(io.Writer, interface{}) error, data interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := render(f, data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to render %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// docModule is a module rendered in the architecture document.
+type docModule struct {
+	// ID identifies the module in diagrams.
+	ID          string
+	Name        string
+	Page        string
+	Description string
+	// DependsOn and UsedBy are the modules the module depends on and the ones depending on it, in instantiation
+	// order.
+	DependsOn []*docModule
+	UsedBy    []*docModule
+	// Dependencies are the names of the instances the module depends on, sorted.
+	Dependencies []string
+	Instances    []InstanceInfo
+}
+
+// newDocModules collects the modules of the container in instantiation order.
+func newDocModules(introspector Introspector, ext string) []*docModule {
+	var modules []*docModule
+	byName := make(map[string]*docModule)
+	for i, info := range introspector.Modules() {
+		m := &docModule{
+			ID:          fmt.Sprintf("m%d", i),
+			Name:        info.Name,
+			Page:        docPageName(info.Name) + ext,
+			Description: info.Description,
+		}
+		modules = append(modules, m)
+		byName[m.Name] = m
+	}
+
+	providers := make(map[string]*docModule)
+	for _, instance := range introspector.Instances() {
+		m := byName[instance.Module]
+		m.Instances = append(m.Instances, instance)
+		providers[instance.Name] = m
+	}
+
+	dependencies := make(map[*docModule]map[string]bool)
+	for _, p := range introspector.Plan() {
+		m := byName[p.Module]
+		if dependencies[m] == nil {
+			dependencies[m] = make(map[string]bool)
+		}
+		for _, name := range p.Dependencies {
+			dependencies[m][name] = true
+		}
+	}
+	for _, m := range modules {
+		dependsOn := make(map[*docModule]bool)
+		for name := range dependencies[m] {
+			m.Dependencies = append(m.Dependencies, name)
+			if provider, ok := providers[name]; ok && provider != m {
+				dependsOn[provider] = true
+			}
+		}
+		sort.Strings(m.Dependencies)
+		for _, other := range modules {
+			if dependsOn[other] {
+				m.DependsOn = append(m.DependsOn, other)
+				other.UsedBy = append(other.UsedBy, m)
+			}
+		}
+	}
+	return modules
+}
+
+// docPageName returns the base name of the page of a module, replacing the characters unsafe in file names.
+func docPageName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// markdownCell escapes a value in a Markdown table cell.
+func markdownCell(v interface{}) string {
+	s := fmt.Sprint(v)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+var _MarkdownIndexTemplate = template.Must(template.New("index").Funcs(template.FuncMap{"cell": markdownCell}).Parse(
+	`# Architecture
+
+| Module | Description | Instances |
+| --- | --- | --- |
+{{range .}}| [{{cell .Name}}]({{.Page}}) | {{cell .Description}} | {{len .Instances}} |
+{{end}}
+## Module graph
+
+` + "```mermaid" + `
+graph TD
+{{range .}}    {{.ID}}["{{.Name}}"]
+{{end}}{{range $m := .}}{{range .DependsOn}}    {{$m.ID}} --> {{.ID}}
+{{end}}{{end}}` + "```" + `
+`))
+
+var _MarkdownModuleTemplate = template.Must(template.New("module").Funcs(template.FuncMap{"cell": markdownCell}).Parse(
+	`# {{.Name}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+[Back to index](index.md)
+{{if .DependsOn}}
+## Depends on
+
+{{range .DependsOn}}- [{{.Name}}]({{.Page}})
+{{end}}{{end}}{{if .UsedBy}}
+## Used by
+
+{{range .UsedBy}}- [{{.Name}}]({{.Page}})
+{{end}}{{end}}{{if .Dependencies}}
+## Dependencies
+
+{{range .Dependencies}}- {{.}}
+{{end}}{{end}}
+## Instances
+
+| Name | Type | Description |
+| --- | --- | --- |
+{{range .Instances}}| {{cell .Name}} | ` + "`{{cell .Type}}`" + ` | {{cell .Description}}{{if .Deprecated}} Deprecated.` +
+		`{{if .Replacement}} Use {{cell .Replacement}} instead.{{end}}{{end}} |
+{{end}}`))
+
+var _HTMLIndexTemplate = htmltemplate.Must(htmltemplate.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Architecture</title></head>
+<body>
+<h1>Architecture</h1>
+<table>
+<tr><th>Module</th><th>Description</th><th>Depends on</th><th>Instances</th></tr>
+{{range .}}<tr><td><a href="{{.Page}}">{{.Name}}</a></td><td>{{.Description}}</td>` +
+	`<td>{{range $i, $d := .DependsOn}}{{if $i}}, {{end}}<a href="{{$d.Page}}">{{$d.Name}}</a>{{end}}</td>` +
+	`<td>{{len .Instances}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+var _HTMLModuleTemplate = htmltemplate.Must(htmltemplate.New("module").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Name}}</title></head>
+<body>
+<h1>{{.Name}}</h1>
+{{if .Description}}<p>{{.Description}}</p>
+{{end}}<p><a href="index.html">Back to index</a></p>
+{{if .DependsOn}}<h2>Depends on</h2>
+<ul>
+{{range .DependsOn}}<li><a href="{{.Page}}">{{.Name}}</a></li>
+{{end}}</ul>
+{{end}}{{if .UsedBy}}<h2>Used by</h2>
+<ul>
+{{range .UsedBy}}<li><a href="{{.Page}}">{{.Name}}</a></li>
+{{end}}</ul>
+{{end}}{{if .Dependencies}}<h2>Dependencies</h2>
+<ul>
+{{range .Dependencies}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}<h2>Instances</h2>
+<table>
+<tr><th>Name</th><th>Type</th><th>Description</th></tr>
+{{range .Instances}}<tr><td>{{.Name}}</td><td><code>{{.Type}}</code></td><td>{{.Description}}` +
+	`{{if .Deprecated}} Deprecated.{{if .Replacement}} Use {{.Replacement}} instead.{{end}}{{end}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
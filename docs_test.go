@@ -0,0 +1,105 @@
+package alice
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type docsStorageModule struct {
+	BaseModule
+}
+
+func (m *docsStorageModule) DB() string {
+	return "db"
+}
+
+func (m *docsStorageModule) Describe() map[string]string {
+	return map[string]string{
+		"":   "Owns the | database.",
+		"DB": "The <primary> database.",
+	}
+}
+
+type docsAPIModule struct {
+	BaseModule
+	DB string `alice:"DB"`
+}
+
+func (m *docsAPIModule) Handler() int {
+	return 1
+}
+
+func (m *docsAPIModule) Deprecated() map[string]string {
+	return map[string]string{"Handler": "Router"}
+}
+
+func TestWriteDocs_Markdown(t *testing.T) {
+	c := CreateContainer(&docsAPIModule{}, &docsStorageModule{})
+	dir := t.TempDir()
+	if err := WriteDocs(c, dir, DocMarkdown); err != nil {
+		t.Fatalf("bad result from WriteDocs(): got %v, expected nil", err)
+	}
+
+	index := readDoc(t, filepath.Join(dir, "index.md"))
+	for _, expected := range []string{
+		"| [docsStorageModule](docsStorageModule.md) | Owns the \\| database. | 1 |",
+		"| [docsAPIModule](docsAPIModule.md) |  | 1 |",
+		"    m0[\"docsStorageModule\"]\n    m1[\"docsAPIModule\"]\n    m1 --> m0\n",
+	} {
+		if !strings.Contains(index, expected) {
+			t.Errorf("bad index after WriteDocs(): got %s, expected to contain %q", index, expected)
+		}
+	}
+
+	api := readDoc(t, filepath.Join(dir, "docsAPIModule.md"))
+	for _, expected := range []string{
+		"## Depends on\n\n- [docsStorageModule](docsStorageModule.md)\n",
+		"## Dependencies\n\n- DB\n",
+		"| Handler | `int` |  Deprecated. Use Router instead. |",
+	} {
+		if !strings.Contains(api, expected) {
+			t.Errorf("bad module page after WriteDocs(): got %s, expected to contain %q", api, expected)
+		}
+	}
+	storage := readDoc(t, filepath.Join(dir, "docsStorageModule.md"))
+	if expected := "## Used by\n\n- [docsAPIModule](docsAPIModule.md)\n"; !strings.Contains(storage, expected) {
+		t.Errorf("bad module page after WriteDocs(): got %s, expected to contain %q", storage, expected)
+	}
+}
+
+func TestWriteDocs_HTML(t *testing.T) {
+	c := CreateContainer(&docsAPIModule{}, &docsStorageModule{})
+	dir := t.TempDir()
+	if err := WriteDocs(c, dir, DocHTML); err != nil {
+		t.Fatalf("bad result from WriteDocs(): got %v, expected nil", err)
+	}
+
+	index := readDoc(t, filepath.Join(dir, "index.html"))
+	expected := `<td><a href="docsStorageModule.html">docsStorageModule</a></td>`
+	if !strings.Contains(index, expected) {
+		t.Errorf("bad index after WriteDocs(): got %s, expected to contain %q", index, expected)
+	}
+	storage := readDoc(t, filepath.Join(dir, "docsStorageModule.html"))
+	expected = "<td>DB</td><td><code>string</code></td><td>The &lt;primary&gt; database.</td>"
+	if !strings.Contains(storage, expected) {
+		t.Errorf("bad module page after WriteDocs(): got %s, expected to contain %q", storage, expected)
+	}
+}
+
+func TestWriteDocs_UnknownFormat(t *testing.T) {
+	err := WriteDocs(CreateContainer(), t.TempDir(), DocFormat(9))
+	if err == nil || err.Error() != "unknown doc format 9" {
+		t.Errorf("bad result from WriteDocs(): got %v, expected unknown doc format 9", err)
+	}
+}
+
+func readDoc(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(content)
+}
@@ -0,0 +1,88 @@
+package alice
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// FailureDump is the snapshot of a container whose population failed, written by the options of WithFailureDump and
+// WithFailureDumpFile as JSON.
+type FailureDump struct {
+	// Time is when the population failed.
+	Time time.Time `json:"time"`
+	// Error is the message of the failure.
+	Error string `json:"error"`
+	// Order contains the names of all instances in instantiation order. It is empty if the modules are invalid.
+	Order []string `json:"order"`
+	// Constructed contains the names of the instances constructed before the failure, in instantiation order.
+	Constructed []string `json:"constructed"`
+	// Pending contains the names of the instances not constructed, in instantiation order.
+	Pending []string `json:"pending"`
+	// Stack contains the instances being constructed when the failure happened, the outermost first, like
+	// ConstructionError.Stack. Its last two entries are the failing edge.
+	Stack []string `json:"stack,omitempty"`
+}
+
+// WithFailureDump returns an option which writes a FailureDump to w when the container population panics, before
+// the panic is propagated. It aids the post-mortem debugging of processes whose logs are the only artifact, e.g.
+// crash-looping pods writing it to os.Stderr.
+func WithFailureDump(w io.Writer) Option {
+	return func(o *options) {
+		o.failureDump = func(data []byte) error {
+			_, err := w.Write(data)
+			return err
+		}
+	}
+}
+
+// WithFailureDumpFile returns an option which writes a FailureDump to the file at path when the container population
+// panics, like WithFailureDump. The file is only created on failure, and replaced if it exists.
+func WithFailureDumpFile(path string) Option {
+	return func(o *options) {
+		o.failureDump = func(data []byte) error {
+			return os.WriteFile(path, data, 0o644)
+		}
+	}
+}
+
+// dumpFailure writes the snapshot of the failed population. A failure to write it is logged, as the panic of the
+// population matters more.
+func (c *container) dumpFailure(r interface{}) {
+	dump := c.failureDump(r)
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(dump)
+	if err == nil {
+		err = c.options.failureDump(buf.Bytes())
+	}
+	if err != nil {
+		c.options.logger.Error("alice: failed to write failure dump", "error", err)
+	}
+}
+
+// failureDump creates the snapshot of the population failed with the recovered value.
+func (c *container) failureDump(r interface{}) *FailureDump {
+	dump := &FailureDump{
+		Time:        time.Now(),
+		Error:       fmt.Sprintf("%v", r),
+		Order:       []string{},
+		Constructed: c.Constructed(),
+		Pending:     c.Pending(),
+	}
+	for _, rm := range c.reflected {
+		for _, instance := range rm.instances {
+			dump.Order = append(dump.Order, instance.name)
+		}
+	}
+	var ce *ConstructionError
+	if err, ok := r.(error); ok && errors.As(err, &ce) {
+		dump.Stack = ce.Stack
+	}
+	return dump
+}
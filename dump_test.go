@@ -0,0 +1,77 @@
+package alice
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type failingModule struct {
+	BaseModule
+	D1 D1 `alice:"D1"`
+}
+
+func (m *failingModule) D3() D3 {
+	panic("failed to create D3")
+}
+
+func TestWithFailureDump(t *testing.T) {
+	var buf bytes.Buffer
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic after CreateContainer()")
+			}
+		}()
+		CreateContainerWithOptions([]Module{&failingModule{}, &M1{}}, WithFailureDump(&buf))
+	}()
+
+	var dump FailureDump
+	if err := json.Unmarshal(buf.Bytes(), &dump); err != nil {
+		t.Fatalf("bad dump after CreateContainer(): got %v, expected nil error", err)
+	}
+	if !strings.Contains(dump.Error, "failed to create D3") {
+		t.Errorf("bad error after CreateContainer(): got %v, expected the cause", dump.Error)
+	}
+	if len(dump.Order) != 3 {
+		t.Errorf("bad order after CreateContainer(): got %v, expected 3 instances", dump.Order)
+	}
+	if !reflect.DeepEqual(dump.Pending, []string{"D3"}) {
+		t.Errorf("bad pending after CreateContainer(): got %v, expected %v", dump.Pending, []string{"D3"})
+	}
+	if len(dump.Constructed) != 2 {
+		t.Errorf("bad constructed after CreateContainer(): got %v, expected D1 and D2", dump.Constructed)
+	}
+	if !reflect.DeepEqual(dump.Stack, []string{"D3"}) {
+		t.Errorf("bad stack after CreateContainer(): got %v, expected %v", dump.Stack, []string{"D3"})
+	}
+}
+
+func TestWithFailureDumpFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.json")
+	CreateContainerWithOptions([]Module{&M1{}}, WithFailureDumpFile(path))
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("bad file after CreateContainer(): got %v, expected not exist", err)
+	}
+
+	func() {
+		defer func() {
+			recover()
+		}()
+		// D1 is missing
+		CreateContainerWithOptions([]Module{&M4{}}, WithFailureDumpFile(path))
+	}()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("bad file after CreateContainer() with invalid modules: got %v, expected nil error", err)
+	}
+	var dump FailureDump
+	json.Unmarshal(data, &dump)
+	if dump.Error == "" || len(dump.Order) != 0 || len(dump.Pending) != 0 {
+		t.Errorf("bad dump after CreateContainer() with invalid modules: got %+v", dump)
+	}
+}
@@ -0,0 +1,175 @@
+package alice
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DynamicProvider fetches the value of a dynamic instance from an external source, such as service discovery or a
+// configuration store.
+type DynamicProvider[T any] interface {
+	// Fetch returns the current value.
+	Fetch(ctx context.Context) (T, error)
+}
+
+// DynamicProviderFunc is an adapter to allow the use of ordinary functions as dynamic providers.
+type DynamicProviderFunc[T any] func(ctx context.Context) (T, error)
+
+// Fetch calls f(ctx).
+func (f DynamicProviderFunc[T]) Fetch(ctx context.Context) (T, error) {
+	return f(ctx)
+}
+
+// Dynamic is an instance whose value is refreshed from a DynamicProvider, e.g. rotated endpoints or credentials. It
+// is usually provided by a module, and consumers depending on it observe the updates by Get or Subscribe:
+//
+//	func (m *DiscoveryModule) Endpoints() *alice.Dynamic[[]string] {
+//		return alice.MustDynamic[[]string](m.Consul, 30*time.Second)
+//	}
+//
+// The refresh loop runs between Container.Start and Container.Stop. It is safe for concurrent use.
+type Dynamic[T any] struct {
+	provider DynamicProvider[T]
+	interval time.Duration
+
+	mu          sync.Mutex
+	value       T
+	err         error
+	subscribers map[int]func(T)
+	nextID      int
+	cancel      context.CancelFunc
+	done        chan struct{}
+}
+
+// NewDynamic creates a dynamic instance refreshed from the provider at the interval. The initial value is fetched
+// right away, and its error is returned.
+func NewDynamic[T any](provider DynamicProvider[T], interval time.Duration) (*Dynamic[T], error) {
+	d := &Dynamic[T]{
+		provider:    provider,
+		interval:    interval,
+		subscribers: make(map[int]func(T)),
+	}
+	value, err := provider.Fetch(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	d.value = value
+	return d, nil
+}
+
+// MustDynamic creates a dynamic instance like NewDynamic. It panics if the initial value could not be fetched, so it
+// could be used in instance methods.
+func MustDynamic[T any](provider DynamicProvider[T], interval time.Duration) *Dynamic[T] {
+	d, err := NewDynamic(provider, interval)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Get returns the current value.
+func (d *Dynamic[T]) Get() T {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.value
+}
+
+// Err returns the error of the last refresh, or nil if it succeeded. The value is kept if a refresh fails.
+func (d *Dynamic[T]) Err() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.err
+}
+
+// Subscribe registers a callback invoked with the new value after each successful refresh. It returns a function to
+// cancel the subscription.
+func (d *Dynamic[T]) Subscribe(callback func(T)) (cancel func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	id := d.nextID
+	d.nextID++
+	d.subscribers[id] = callback
+	return func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.subscribers, id)
+	}
+}
+
+// notifyChange subscribes to the refreshes without the value. It implements changeNotifier.
+func (d *Dynamic[T]) notifyChange(callback func()) (cancel func()) {
+	return d.Subscribe(func(T) {
+		callback()
+	})
+}
+
+// Refresh fetches the value from the provider and notifies the subscribers.
+func (d *Dynamic[T]) Refresh(ctx context.Context) error {
+	value, err := d.provider.Fetch(ctx)
+
+	d.mu.Lock()
+	d.err = err
+	if err != nil {
+		d.mu.Unlock()
+		return err
+	}
+	d.value = value
+	var subscribers []func(T)
+	for _, s := range d.subscribers {
+		subscribers = append(subscribers, s)
+	}
+	d.mu.Unlock()
+
+	for _, s := range subscribers {
+		s(value)
+	}
+	return nil
+}
+
+// Start starts refreshing in background at the interval. It implements Starter.
+func (d *Dynamic[T]) Start(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.cancel != nil || d.interval <= 0 {
+		return nil
+	}
+	loopCtx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go d.loop(loopCtx, d.done)
+	return nil
+}
+
+// Stop stops refreshing. It implements Stopper.
+func (d *Dynamic[T]) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	cancel, done := d.cancel, d.done
+	d.cancel, d.done = nil, nil
+	d.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *Dynamic[T]) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.Refresh(ctx)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDynamic(t *testing.T) {
+	var version int32
+	var failing atomic.Bool
+	provider := DynamicProviderFunc[int32](func(ctx context.Context) (int32, error) {
+		if failing.Load() {
+			return 0, errors.New("unavailable")
+		}
+		return atomic.AddInt32(&version, 1), nil
+	})
+	d, err := NewDynamic[int32](provider, 0)
+	if err != nil || d.Get() != 1 {
+		t.Fatalf("bad dynamic after NewDynamic(): got %v, %v", d.Get(), err)
+	}
+
+	var notified int32
+	cancel := d.Subscribe(func(v int32) {
+		notified = v
+	})
+	if err := d.Refresh(context.Background()); err != nil || d.Get() != 2 || notified != 2 {
+		t.Errorf("bad dynamic after Refresh(): got %d, notified %d, err %v", d.Get(), notified, err)
+	}
+
+	failing.Store(true)
+	if err := d.Refresh(context.Background()); err == nil || d.Err() == nil || d.Get() != 2 {
+		t.Errorf("bad dynamic after failed Refresh(): got %d, err %v", d.Get(), err)
+	}
+	failing.Store(false)
+
+	cancel()
+	d.Refresh(context.Background())
+	if notified != 2 {
+		t.Errorf("bad notification after canceled subscription: got %d, expected %d", notified, 2)
+	}
+}
+
+func TestDynamic_Lifecycle(t *testing.T) {
+	var version int32
+	provider := DynamicProviderFunc[int32](func(ctx context.Context) (int32, error) {
+		return atomic.AddInt32(&version, 1), nil
+	})
+	m := NewModule("discovery").
+		Provide("Version", func() *Dynamic[int32] {
+			return MustDynamic[int32](provider, time.Millisecond)
+		}).
+		Build()
+	c := CreateContainer(m)
+	if err := c.(Lifecycle).Start(context.Background()); err != nil {
+		t.Fatalf("bad error after Start(): got %v, expected nil", err)
+	}
+
+	d := c.InstanceByName("Version").(*Dynamic[int32])
+	deadline := time.Now().Add(5 * time.Second)
+	for d.Get() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if d.Get() < 3 {
+		t.Errorf("bad value after refreshing in background: got %d, expected at least 3", d.Get())
+	}
+	if err := c.(Lifecycle).Stop(context.Background()); err != nil {
+		t.Errorf("bad error after Stop(): got %v, expected nil", err)
+	}
+
+	failing := DynamicProviderFunc[int32](func(ctx context.Context) (int32, error) {
+		return 0, errors.New("unavailable")
+	})
+	if _, err := NewDynamic[int32](failing, time.Second); err == nil {
+		t.Error("expected error after NewDynamic() with failing provider")
+	}
+}
@@ -0,0 +1,60 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Effect is the type of instances representing side effects, such as registering gob types or setting a global seed.
+// An instance method returning Effect captures initialization code in the graph instead of hiding it in init
+// functions. The method runs after its parameters are constructed, and before the modules depending on it by name:
+//
+//	func (m *CodecModule) RegisterTypes(cfg *Config) alice.Effect {
+//		gob.Register(&Event{})
+//		return alice.Effect{}
+//	}
+//
+//	type ConsumerModule struct {
+//		alice.BaseModule
+//		Registered alice.Effect `alice:"RegisterTypes"`
+//	}
+//
+// Effects are excluded from Export and Unused. In lazy mode, an effect runs when a dependent is constructed or it is
+// warmed, like any other instance.
+type Effect struct {
+	_ struct{}
+}
+
+var _EffectType = reflect.TypeOf(Effect{})
+
+// Run defines a side effect with the specified name. fn must be a function without return values. The parameters, if
+// any, are dependencies associated by type. See Effect for details.
+func (b *ModuleBuilder) Run(name string, fn interface{}) *ModuleBuilder {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.Type().NumOut() != 0 {
+		b.setError(fmt.Errorf("side effect %s.%s is not a function without return values", b.m.name, name))
+		return b
+	}
+	params, ps, err := reflectParams(v.Type(), 0)
+	if err != nil {
+		b.setError(fmt.Errorf("side effect %s.%s: %s", b.m.name, name, err.Error()))
+		return b
+	}
+	var in []reflect.Type
+	for i := 0; i < v.Type().NumIn(); i++ {
+		in = append(in, v.Type().In(i))
+	}
+	constructor := reflect.MakeFunc(
+		reflect.FuncOf(in, []reflect.Type{_EffectType}, false),
+		func(args []reflect.Value) []reflect.Value {
+			v.Call(args)
+			return []reflect.Value{reflect.ValueOf(Effect{})}
+		})
+	b.m.providers = append(b.m.providers, &builtProvider{
+		name:         name,
+		constructor:  constructor,
+		params:       params,
+		paramsStruct: ps,
+	})
+	return b
+}
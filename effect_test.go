@@ -0,0 +1,74 @@
+package alice
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var effectLog []string
+
+type EffectModule struct {
+	BaseModule
+}
+
+func (m *EffectModule) Register(d1 D1) Effect {
+	effectLog = append(effectLog, "register")
+	return Effect{}
+}
+
+type EffectConsumerModule struct {
+	BaseModule
+	Registered Effect `alice:"Register"`
+}
+
+func (m *EffectConsumerModule) Consumer() *D5Impl {
+	effectLog = append(effectLog, "consumer")
+	return &D5Impl{}
+}
+
+func TestEffect(t *testing.T) {
+	effectLog = nil
+	c := CreateContainer(&EffectConsumerModule{}, &EffectModule{}, &M1{})
+	expected := []string{"register", "consumer"}
+	if !reflect.DeepEqual(effectLog, expected) {
+		t.Errorf("bad order of side effects: got %v, expected %v", effectLog, expected)
+	}
+	if unused := c.(Introspector).Unused(); !reflect.DeepEqual(unused, []string{"Consumer", "D2"}) {
+		t.Errorf("bad result from Unused(): got %v, expected %v", unused, []string{"Consumer", "D2"})
+	}
+	data, err := c.(Introspector).Export()
+	if err != nil {
+		t.Fatalf("failed to export: %s", err.Error())
+	}
+	if strings.Contains(string(data), "Register") {
+		t.Errorf("bad result from Export(): got %s, expected no side effects", data)
+	}
+}
+
+func TestEffect_Builder(t *testing.T) {
+	var log []string
+	var registered Effect
+	m := NewModule("codec").
+		Run("Register", func(d1 D1) {
+			log = append(log, "register")
+		}).
+		Build()
+	consumer := NewModule("consumer").
+		RequireNamed("Register", &registered).
+		Provide("Consumer", func() *D5Impl {
+			log = append(log, "consumer")
+			return &D5Impl{}
+		}).
+		Build()
+	CreateContainer(consumer, m, &M1{})
+	expected := []string{"register", "consumer"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("bad order of side effects of built modules: got %v, expected %v", log, expected)
+	}
+
+	invalid := NewModule("invalid").Run("Register", func() error { return nil }).Build()
+	if err := Validate(invalid); err == nil {
+		t.Error("expected error for side effect with return values")
+	}
+}
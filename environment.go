@@ -0,0 +1,110 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Environments declares the modules of an application per environment. Each environment inherits the base modules,
+// adds its own modules, and could override base modules of the same type, e.g. an in-memory database module in
+// development and the real one in production.
+//
+//	envs := alice.NewEnvironments(&ServiceModule{}, &DatabaseModule{}).
+//		Env("dev", &InMemoryDatabaseModule{}).
+//		Override("dev", &DatabaseModule{}, &InMemoryDatabaseModule{}).
+//		Env("prod", &MetricsModule{})
+//	c := envs.CreateContainer("dev")
+type Environments struct {
+	base []Module
+	envs map[string]*environment
+}
+
+// environment contains the changes an environment makes to the base modules.
+type environment struct {
+	added []Module
+	// overrides maps the types of base modules to their replacements. A nil replacement removes the module.
+	overrides map[reflect.Type]Module
+}
+
+// NewEnvironments creates environments with the base modules shared by all environments.
+func NewEnvironments(base ...Module) *Environments {
+	return &Environments{
+		base: base,
+		envs: make(map[string]*environment),
+	}
+}
+
+// Env declares an environment, adding the modules to it. It could be called multiple times for the same environment.
+func (e *Environments) Env(name string, modules ...Module) *Environments {
+	env := e.env(name)
+	env.added = append(env.added, modules...)
+	return e
+}
+
+// Override replaces the base module of the same type as base with replacement in an environment. A nil replacement
+// removes the base module from the environment.
+func (e *Environments) Override(name string, base Module, replacement Module) *Environments {
+	e.env(name).overrides[reflect.TypeOf(base)] = replacement
+	return e
+}
+
+// Names returns the names of the declared environments, sorted.
+func (e *Environments) Names() []string {
+	var names []string
+	for name := range e.envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Modules returns the modules of an environment. It returns error if the environment is not declared, or an
+// overridden module type is not in the base modules.
+func (e *Environments) Modules(name string) ([]Module, error) {
+	env, ok := e.envs[name]
+	if !ok {
+		return nil, fmt.Errorf("environment %s is not declared in %v", name, e.Names())
+	}
+
+	overridden := make(map[reflect.Type]bool)
+	var modules []Module
+	for _, m := range e.base {
+		t := reflect.TypeOf(m)
+		replacement, ok := env.overrides[t]
+		if !ok {
+			modules = append(modules, m)
+			continue
+		}
+		overridden[t] = true
+		if replacement != nil {
+			modules = append(modules, replacement)
+		}
+	}
+	for t := range env.overrides {
+		if !overridden[t] {
+			return nil, fmt.Errorf("overridden module type %s is not a base module of environment %s", t, name)
+		}
+	}
+	return append(modules, env.added...), nil
+}
+
+// CreateContainer creates a container with the modules of an environment, like CreateContainerWithOptions. It panics
+// if the environment is not declared or any module is invalid.
+func (e *Environments) CreateContainer(name string, opts ...Option) Container {
+	modules, err := e.Modules(name)
+	if err != nil {
+		o := newOptions(opts...)
+		panic(o.formatError(err))
+	}
+	return CreateContainerWithOptions(modules, opts...)
+}
+
+func (e *Environments) env(name string) *environment {
+	env, ok := e.envs[name]
+	if !ok {
+		env = &environment{overrides: make(map[reflect.Type]Module)}
+		e.envs[name] = env
+	}
+	return env
+}
@@ -0,0 +1,66 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvironments(t *testing.T) {
+	devD1 := NewModule("devD1").
+		Provide("D1", func() D1 { return &D1Impl{} }).
+		Provide("D2", func() D2 { return &D2Impl{} }).
+		Build()
+	envs := NewEnvironments(&M1{}, &M4{}).
+		Env("dev").
+		Override("dev", &M1{}, devD1).
+		Env("prod", &M5{}).
+		Env("test").
+		Override("test", &M4{}, nil)
+
+	if names := envs.Names(); !reflect.DeepEqual(names, []string{"dev", "prod", "test"}) {
+		t.Errorf("bad names after Names(): got %v", names)
+	}
+
+	tests := []struct {
+		env      string
+		expected []string
+	}{
+		{"dev", []string{"devD1", "M4"}},
+		{"prod", []string{"M1", "M4", "M5"}},
+		{"test", []string{"M1"}},
+	}
+	for _, test := range tests {
+		modules, err := envs.Modules(test.env)
+		if err != nil {
+			t.Fatalf("bad error after Modules(%s): got %v, expected nil", test.env, err)
+		}
+		var names []string
+		for _, m := range modules {
+			names = append(names, moduleName(m))
+		}
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("bad modules after Modules(%s): got %v, expected %v", test.env, names, test.expected)
+		}
+	}
+
+	c := envs.CreateContainer("dev", WithLazy())
+	if len(c.(Introspector).Instances()) != 4 {
+		t.Errorf("bad instances after CreateContainer(dev): got %v", c.(Introspector).Instances())
+	}
+}
+
+func TestEnvironments_Invalid(t *testing.T) {
+	envs := NewEnvironments(&M1{}).Override("dev", &M4{}, &M5{})
+	if _, err := envs.Modules("dev"); err == nil {
+		t.Error("expected error for overriding a module not in the base modules")
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Error("expected panic for undeclared environment")
+		}
+		t.Log(r)
+	}()
+	envs.CreateContainer("prod")
+}
@@ -0,0 +1,107 @@
+package alice
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrNotFound is the cause of a LookupError when no instance is defined for the name or type.
+var ErrNotFound = errors.New("not defined")
+
+// ErrAmbiguous is the cause of a LookupError when more than one instances are defined for the type.
+var ErrAmbiguous = errors.New("has more than one instances defined")
+
+// LookupError is the panic value of MustInstance and MustInstanceByName, and the error returned by Resolve and
+// ResolveByName, when an instance could not be found. Frameworks recovering from the panic could inspect it by
+// errors.As, or check the cause by errors.Is with ErrNotFound or ErrAmbiguous.
+type LookupError struct {
+	// Name is the name being looked up, or empty if looked up by type.
+	Name string
+	// Type is the type being looked up, or nil if looked up by name.
+	Type reflect.Type
+	// Err is the cause, which is ErrNotFound, ErrAmbiguous or ErrNotAllowed.
+	Err error
+}
+
+// Error returns the message in the form of "instance name X is not defined".
+func (e *LookupError) Error() string {
+	if e.Type != nil {
+		return fmt.Sprintf("instance type %s %s", e.Type.Name(), e.Err.Error())
+	}
+	return fmt.Sprintf("instance name %s %s", e.Name, e.Err.Error())
+}
+
+// Unwrap returns the cause.
+func (e *LookupError) Unwrap() error {
+	return e.Err
+}
+
+// Errors contains all problems found while validating or populating a container, so they could be fixed in one pass.
+type Errors []error
+
+// Error returns the messages of all errors, one per line.
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the errors, so errors.Is and errors.As could inspect each of them.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// ConstructionError is the panic value when an instance fails to be constructed. It includes the construction stack,
+// so the failure could be traced from the instance being retrieved to the one actually failed.
+type ConstructionError struct {
+	// Stack contains the instances being constructed, the outermost first. A module whose dependencies are being
+	// injected is represented as "module <name>".
+	Stack []string
+	// Cause is the value recovered from the failed construction.
+	Cause interface{}
+}
+
+// Error returns the message in the form of "building A -> needs B -> needs C: cause".
+func (e *ConstructionError) Error() string {
+	return fmt.Sprintf("building %s: %v", strings.Join(e.Stack, " -> needs "), e.Cause)
+}
+
+// Unwrap returns the cause if it is an error.
+func (e *ConstructionError) Unwrap() error {
+	err, _ := e.Cause.(error)
+	return err
+}
+
+// recoveredError converts a recovered panic value to an error, keeping it if it is an error already.
+func recoveredError(r interface{}) error {
+	if err, ok := r.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", r)
+}
+
+// joinErrors returns nil if there is no error, the error itself if there is only one, or Errors otherwise. Nested
+// Errors are flattened.
+func joinErrors(errs []error) error {
+	var flattened Errors
+	for _, err := range errs {
+		if nested, ok := err.(Errors); ok {
+			flattened = append(flattened, nested...)
+		} else if err != nil {
+			flattened = append(flattened, err)
+		}
+	}
+
+	switch len(flattened) {
+	case 0:
+		return nil
+	case 1:
+		return flattened[0]
+	default:
+		return flattened
+	}
+}
@@ -0,0 +1,76 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestJoinErrors(t *testing.T) {
+	if err := joinErrors(nil); err != nil {
+		t.Errorf("bad result of joinErrors() with no error: got %v, expected nil", err)
+	}
+
+	err1 := errors.New("error 1")
+	if err := joinErrors([]error{nil, err1}); err != err1 {
+		t.Errorf("bad result of joinErrors() with one error: got %v, expected %v", err, err1)
+	}
+
+	err2 := errors.New("error 2")
+	err3 := errors.New("error 3")
+	err := joinErrors([]error{err1, Errors{err2, err3}})
+	errs, ok := err.(Errors)
+	if !ok || len(errs) != 3 {
+		t.Fatalf("bad result of joinErrors() with nested errors: got %v, expected 3 errors", err)
+	}
+	if errs.Error() != "error 1\nerror 2\nerror 3" {
+		t.Errorf("bad message of Errors: got %q", errs.Error())
+	}
+	if !errors.Is(err, err3) {
+		t.Errorf("Errors is expected to wrap %v", err3)
+	}
+}
+
+func TestConstructionError_Lazy(t *testing.T) {
+	consumer := NewModule("consumer").
+		Provide("X", func(d3 D3) D1 { return &D1Impl{} }).
+		Build()
+	c := CreateContainerWithOptions([]Module{&PanicModule{}, consumer}, WithLazy())
+
+	err := c.(Lifecycle).Warm(context.Background(), "X")
+	var ce *ConstructionError
+	if !errors.As(err, &ce) {
+		t.Fatalf("bad error after Warm(): got %v, expected ConstructionError", err)
+	}
+	if expected := []string{"X", "D3"}; !reflect.DeepEqual(ce.Stack, expected) {
+		t.Errorf("bad stack of ConstructionError: got %v, expected %v", ce.Stack, expected)
+	}
+	if expected := "building X -> needs D3: failed to create D3"; ce.Error() != expected {
+		t.Errorf("bad message of ConstructionError: got %s, expected %s", ce.Error(), expected)
+	}
+}
+
+func TestConstructionError_Eager(t *testing.T) {
+	defer func() {
+		r := recover()
+		ce, ok := r.(*ConstructionError)
+		if !ok {
+			t.Fatalf("bad panic after CreateContainer(): got %v, expected ConstructionError", r)
+		}
+		if expected := []string{"D3"}; !reflect.DeepEqual(ce.Stack, expected) {
+			t.Errorf("bad stack of ConstructionError: got %v, expected %v", ce.Stack, expected)
+		}
+	}()
+	CreateContainer(&PanicModule{})
+}
+
+func TestConstructionError_Unwrap(t *testing.T) {
+	cause := errors.New("cause")
+	if err := (&ConstructionError{Stack: []string{"D1"}, Cause: cause}); !errors.Is(err, cause) {
+		t.Errorf("bad errors.Is() on ConstructionError: got false, expected true")
+	}
+	if err := (&ConstructionError{Stack: []string{"D1"}, Cause: "cause"}); err.Unwrap() != nil {
+		t.Errorf("bad Unwrap() on ConstructionError: got %v, expected nil", err.Unwrap())
+	}
+}
@@ -0,0 +1,96 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+func (c *container) Explain(t reflect.Type) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "resolving type %s\n", typeName(t))
+	if winner, ok := c.graph.typeWinners[t]; ok {
+		fmt.Fprintf(&b, "  conflict policy picked %s\n", c.describeInstance(winner))
+		fmt.Fprintf(&b, "result: %s\n", c.describeInstance(winner))
+		return b.String()
+	}
+	if a, ok := c.graph.adapted[t]; ok {
+		c.explainAdaptation(&b, a)
+		return b.String()
+	}
+
+	var exact []string
+	for _, rm := range c.reflected {
+		for _, instance := range rm.instances {
+			if instance.tp == t {
+				fmt.Fprintf(&b, "  accepted %s: exact type match\n", c.describeInstance(instance.name))
+				exact = append(exact, instance.name)
+			}
+		}
+	}
+	candidates := exact
+	if len(exact) == 0 {
+		if c.options.strict {
+			fmt.Fprintf(&b, "  no exact type match, assignable types are not considered in strict mode\n")
+		} else {
+			fmt.Fprintf(&b, "  no exact type match, considering assignable types\n")
+			for _, rm := range c.reflected {
+				for _, instance := range rm.instances {
+					if instance.tp.AssignableTo(t) {
+						fmt.Fprintf(&b, "  accepted %s: %s is assignable\n", c.describeInstance(instance.name),
+							typeName(instance.tp))
+						candidates = append(candidates, instance.name)
+					} else {
+						fmt.Fprintf(&b, "  rejected %s: %s is not assignable\n", c.describeInstance(instance.name),
+							typeName(instance.tp))
+					}
+				}
+			}
+		}
+	}
+	writeExplainResult(&b, candidates, c.describeInstance)
+	return b.String()
+}
+
+func (c *container) ExplainName(name string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "resolving name %s\n", name)
+	if c.shortNames != nil {
+		qualified, err := c.shortNames.qualify(name)
+		if err != nil {
+			fmt.Fprintf(&b, "result: %s\n", err.Error())
+			return b.String()
+		}
+		fmt.Fprintf(&b, "  qualified short name as %s\n", qualified)
+		name = qualified
+	}
+	var candidates []string
+	if findInstanceMethodInModules(c.reflected, name) != nil {
+		fmt.Fprintf(&b, "  accepted %s: exact name match\n", c.describeInstance(name))
+		candidates = append(candidates, name)
+	}
+	writeExplainResult(&b, candidates, c.describeInstance)
+	return b.String()
+}
+
+// writeExplainResult writes the result line of an explanation from the accepted candidates.
+func writeExplainResult(b *strings.Builder, candidates []string, describe func(name string) string) {
+	switch len(candidates) {
+	case 0:
+		fmt.Fprintf(b, "result: not found\n")
+	case 1:
+		fmt.Fprintf(b, "result: %s\n", describe(candidates[0]))
+	default:
+		fmt.Fprintf(b, "result: ambiguous, %d candidates\n", len(candidates))
+	}
+}
+
+// describeInstance returns the instance name with its providing module.
+func (c *container) describeInstance(name string) string {
+	for _, rm := range c.reflected {
+		if findInstanceMethod(rm.instances, name) != nil {
+			return fmt.Sprintf("%s (module %s)", name, rm.name)
+		}
+	}
+	return name
+}
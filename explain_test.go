@@ -0,0 +1,54 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	m := NewModule("impls").
+		Provide("D1", func() *D1Impl { return &D1Impl{} }).
+		Provide("D2", func() *D2Impl { return &D2Impl{} }).
+		Build()
+	c := CreateContainer(m)
+
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	expected := "resolving type github.com/magic003/alice.D1\n" +
+		"  no exact type match, considering assignable types\n" +
+		"  accepted D1 (module impls): *github.com/magic003/alice.D1Impl is assignable\n" +
+		"  rejected D2 (module impls): *github.com/magic003/alice.D2Impl is not assignable\n" +
+		"result: D1 (module impls)\n"
+	if s := c.(Introspector).Explain(d1Type); s != expected {
+		t.Errorf("bad result from Explain(): got %q, expected %q", s, expected)
+	}
+
+	expected = "resolving type *github.com/magic003/alice.D2Impl\n" +
+		"  accepted D2 (module impls): exact type match\n" +
+		"result: D2 (module impls)\n"
+	if s := c.(Introspector).Explain(reflect.TypeOf(&D2Impl{})); s != expected {
+		t.Errorf("bad result from Explain() for exact type: got %q, expected %q", s, expected)
+	}
+
+	c = CreateContainerWithOptions([]Module{m}, WithStrict())
+	expected = "resolving type github.com/magic003/alice.D1\n" +
+		"  no exact type match, assignable types are not considered in strict mode\n" +
+		"result: not found\n"
+	if s := c.(Introspector).Explain(d1Type); s != expected {
+		t.Errorf("bad result from Explain() in strict mode: got %q, expected %q", s, expected)
+	}
+}
+
+func TestExplainName(t *testing.T) {
+	c := CreateContainer(&M1{})
+	expected := "resolving name D1\n" +
+		"  accepted D1 (module M1): exact name match\n" +
+		"result: D1 (module M1)\n"
+	if s := c.(Introspector).ExplainName("D1"); s != expected {
+		t.Errorf("bad result from ExplainName(): got %q, expected %q", s, expected)
+	}
+	expected = "resolving name D9\n" +
+		"result: not found\n"
+	if s := c.(Introspector).ExplainName("D9"); s != expected {
+		t.Errorf("bad result from ExplainName() for undefined name: got %q, expected %q", s, expected)
+	}
+}
@@ -0,0 +1,64 @@
+package alice
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+// FailurePolicy decides what Container.Instance and Container.InstanceByName do when an instance fails to be
+// retrieved, i.e. it is not found, ambiguous, or fails to be constructed. MustInstance and Resolve are not affected.
+// The default policy is PanicOnFailure.
+type FailurePolicy struct {
+	// handle returns the instance to use instead, or panics. t is nil for a retrieval by name.
+	handle func(logger *slog.Logger, t reflect.Type, err error) interface{}
+}
+
+// PanicOnFailure is the failure policy panicking with the error, which is a *LookupError or a *ConstructionError.
+var PanicOnFailure = FailurePolicy{}
+
+// LogOnFailure is the failure policy logging the error by the logger of the container, and returning the zero value
+// of the type, or nil for a retrieval by name. It suits embedders, like plugins inside larger hosts, which cannot
+// tolerate panics escaping library code.
+var LogOnFailure = FailurePolicy{
+	handle: func(logger *slog.Logger, t reflect.Type, err error) interface{} {
+		logger.Error("alice: failed to retrieve instance", "error", err)
+		return zeroInstance(t)
+	},
+}
+
+// HandleFailure returns a failure policy calling the handler with the error. The instance returned by the handler
+// is returned instead. The handler could also panic.
+func HandleFailure(handler func(err error) interface{}) FailurePolicy {
+	return FailurePolicy{
+		handle: func(logger *slog.Logger, t reflect.Type, err error) interface{} {
+			return handler(err)
+		},
+	}
+}
+
+// WithFailurePolicy returns an option which sets the failure policy of the container and its forks and scopes.
+func WithFailurePolicy(policy FailurePolicy) Option {
+	return func(o *options) {
+		o.failurePolicy = policy
+	}
+}
+
+// zeroInstance returns the zero value of the type, or nil if the type is nil.
+func zeroInstance(t reflect.Type) interface{} {
+	if t == nil {
+		return nil
+	}
+	return reflect.Zero(t).Interface()
+}
+
+// retrieve calls the retrieval function, applying the failure policy to its panic.
+func (o *options) retrieve(t reflect.Type, must func() interface{}) interface{} {
+	if o.failurePolicy.handle == nil {
+		return must()
+	}
+	instance, err := resolveSafely(must)
+	if err != nil {
+		return o.failurePolicy.handle(o.logger, t, err)
+	}
+	return instance
+}
@@ -0,0 +1,72 @@
+package alice
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithFailurePolicy_Log(t *testing.T) {
+	var buf bytes.Buffer
+	c := CreateContainerWithOptions([]Module{&M1{}}, WithFailurePolicy(LogOnFailure),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+
+	if instance := c.InstanceByName("Undefined"); instance != nil {
+		t.Errorf("bad instance from InstanceByName() with LogOnFailure: got %v, expected nil", instance)
+	}
+	d3Type := reflect.TypeOf((*D3)(nil)).Elem()
+	if instance := c.Instance(d3Type); instance != nil {
+		t.Errorf("bad instance from Instance() with LogOnFailure: got %v, expected nil", instance)
+	}
+	ptrType := reflect.TypeOf((*countedD1)(nil))
+	if instance, ok := c.Instance(ptrType).(*countedD1); !ok || instance != nil {
+		t.Errorf("bad instance from Instance() with LogOnFailure: got %v, expected typed nil", instance)
+	}
+	expected := `msg="alice: failed to retrieve instance" error="instance name Undefined not defined"`
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("bad logs after InstanceByName(): got %s, expected to contain %s", buf.String(), expected)
+	}
+
+	if _, err := c.ResolveByName("Undefined"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("bad error from ResolveByName() with LogOnFailure: got %v, expected %v", err, ErrNotFound)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("MustInstanceByName() is expected to panic with LogOnFailure")
+			}
+		}()
+		c.MustInstanceByName("Undefined")
+	}()
+
+	f := c.(Scoper).Fork()
+	if instance := f.InstanceByName("Undefined"); instance != nil {
+		t.Errorf("bad instance from InstanceByName() of fork with LogOnFailure: got %v, expected nil", instance)
+	}
+	v := c.(Scoper).ReadOnlyView("D1")
+	if instance := v.InstanceByName("D2"); instance != nil {
+		t.Errorf("bad instance from InstanceByName() of view with LogOnFailure: got %v, expected nil", instance)
+	}
+}
+
+func TestWithFailurePolicy_Handler(t *testing.T) {
+	var failures []error
+	fallback := &D1Impl{}
+	c := CreateContainerWithOptions([]Module{&M1{}}, WithFailurePolicy(HandleFailure(func(err error) interface{} {
+		failures = append(failures, err)
+		return fallback
+	})))
+
+	if instance := c.InstanceByName("Undefined"); instance != fallback {
+		t.Errorf("bad instance from InstanceByName() with HandleFailure: got %v, expected %v", instance, fallback)
+	}
+	if instance := c.InstanceByName("D1"); instance == nil {
+		t.Error("bad instance from InstanceByName() with HandleFailure: got nil, expected D1")
+	}
+	if len(failures) != 1 || !errors.Is(failures[0], ErrNotFound) {
+		t.Errorf("bad failures handled: got %v, expected 1 not found error", failures)
+	}
+}
@@ -0,0 +1,74 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+type FallbackModule1 struct {
+	BaseModule
+}
+
+func (m *FallbackModule1) NoopD1() D1 {
+	return &countedD1{n: 0}
+}
+
+func (m *FallbackModule1) D2() D2 {
+	return &D2Impl{}
+}
+
+func (m *FallbackModule1) Fallbacks() []string {
+	return []string{"NoopD1", "D2"}
+}
+
+type invalidFallbackModule struct {
+	BaseModule
+}
+
+func (m *invalidFallbackModule) Fallbacks() []string {
+	return []string{"Undefined"}
+}
+
+func TestFallback(t *testing.T) {
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	c := CreateContainer(&FallbackModule1{})
+	if n := c.Instance(d1Type).(*countedD1).n; n != 0 {
+		t.Errorf("bad instance without other providers: got %d, expected fallback", n)
+	}
+
+	real := NewModule("real").
+		Provide("D1", func() D1 { return &countedD1{n: 1} }).
+		Provide("D2", func() *D2Impl { return &D2Impl{} }).
+		Build()
+	c = CreateContainer(&FallbackModule1{}, real)
+	if n := c.Instance(d1Type).(*countedD1).n; n != 1 {
+		t.Errorf("bad instance with other providers: got %d, expected %d", n, 1)
+	}
+	if n := len(c.(Introspector).Instances()); n != 2 {
+		t.Errorf("bad number of instances with other providers: got %d, expected %d", n, 2)
+	}
+
+	built := NewModule("built").
+		Provide("D1", func() D1 { return &countedD1{n: 2} }).
+		Fallback("D1").
+		Build()
+	c = CreateContainer(built, real)
+	if n := c.Instance(d1Type).(*countedD1).n; n != 1 {
+		t.Errorf("bad instance with fallback of built module: got %d, expected %d", n, 1)
+	}
+
+	concrete := NewModule("concrete").
+		Provide("Counted", func() *countedD1 { return &countedD1{n: 3} }).
+		Build()
+	c = CreateContainer(&FallbackModule1{}, concrete)
+	if n := c.Instance(d1Type).(*countedD1).n; n != 3 {
+		t.Errorf("bad instance with assignable provider: got %d, expected %d", n, 3)
+	}
+	if n := len(c.(Introspector).Instances()); n != 2 {
+		t.Errorf("bad number of instances with assignable provider: got %d, expected %d", n, 2)
+	}
+
+	if err := Validate(&invalidFallbackModule{}); err == nil {
+		t.Error("expected error for undefined fallback instance")
+	}
+}
@@ -0,0 +1,176 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FeatureFlags reports whether a feature flag is enabled.
+type FeatureFlags interface {
+	Enabled(flag string) bool
+}
+
+// FeatureFlagsFunc is an adapter to allow the use of an ordinary function as FeatureFlags.
+type FeatureFlagsFunc func(flag string) bool
+
+// Enabled calls f(flag).
+func (f FeatureFlagsFunc) Enabled(flag string) bool {
+	return f(flag)
+}
+
+// Gate binds an instance to a feature flag.
+type Gate struct {
+	// Flag is the name of the feature flag.
+	Flag string
+	// Alternative is a function without parameters, returning a value assignable to the instance type. It is called
+	// instead of the instance method if the flag is disabled, for example to return a no-op implementation.
+	Alternative interface{}
+}
+
+// GatedModule is an optional interface a module could implement to bind some of its instances to feature flags. The
+// flags are evaluated when the instances are constructed, by the FeatureFlags set by WithFeatureFlags.
+//
+// An instance of function or interface type is re-evaluated per call: both the instance and the alternative are
+// constructed, and the proxy created by the ProxyFactory set by WithProxyFactory calls either of them depending on the
+// current flag value. Go can't create an implementation of an arbitrary interface at runtime, so if the factory can't
+// proxy the type, e.g. an interface without a constructor registered to a GeneratedProxyFactory, the flag is
+// re-evaluated each time the instance is injected or retrieved instead. An instance of concrete type can't be proxied,
+// and is switched only once.
+type GatedModule interface {
+	// Gates returns the gates keyed by the instance names.
+	Gates() map[string]Gate
+}
+
+// WithFeatureFlags returns an option which sets the feature flags evaluated by gated instances. Without the option,
+// all flags are considered enabled, so gated instances are constructed by their own instance methods.
+func WithFeatureFlags(flags FeatureFlags) Option {
+	return func(o *options) {
+		o.featureFlags = flags
+	}
+}
+
+// instanceGate is a validated gate of an instance.
+type instanceGate struct {
+	flag        string
+	alternative reflect.Value
+}
+
+// newInstanceGate validates the gate of an instance of type t.
+func newInstanceGate(t reflect.Type, gate Gate) (*instanceGate, error) {
+	v := reflect.ValueOf(gate.Alternative)
+	if v.Kind() != reflect.Func || v.Type().NumIn() != 0 || v.Type().NumOut() != 1 {
+		return nil, fmt.Errorf("alternative of flag %s is not a function without parameters and with 1 return value",
+			gate.Flag)
+	}
+	if !v.Type().Out(0).AssignableTo(t) {
+		return nil, fmt.Errorf("alternative of flag %s returns %s, which is not assignable to %s", gate.Flag,
+			v.Type().Out(0), t)
+	}
+	return &instanceGate{
+		flag:        gate.Flag,
+		alternative: v,
+	}, nil
+}
+
+// gateInstances sets the gates of instances. It returns error if any name is not an instance of the module, or any
+// gate is invalid.
+func gateInstances(moduleName string, instances []*instanceMethod, gates map[string]Gate) error {
+	for name, gate := range gates {
+		instance := findInstanceMethod(instances, name)
+		if instance == nil {
+			return fmt.Errorf("gated instance %s.%s is not defined", moduleName, name)
+		}
+		ig, err := newInstanceGate(instance.tp, gate)
+		if err != nil {
+			return fmt.Errorf("gated instance %s.%s: %s", moduleName, name, err.Error())
+		}
+		instance.gate = ig
+	}
+	return nil
+}
+
+// flagEnabled reports whether a feature flag is enabled.
+func (c *container) flagEnabled(flag string) bool {
+	return c.options.featureFlags == nil || c.options.featureFlags.Enabled(flag)
+}
+
+// gatedInstance constructs an instance bound to a feature flag. Instances of function and interface types are
+// proxied, so the flag is evaluated per call, unless the proxy factory can't proxy them, see switchInstance.
+func (c *container) gatedInstance(im *instanceMethod) interface{} {
+	if im.tp.Kind() != reflect.Func && im.tp.Kind() != reflect.Interface {
+		return c.switchedInstance(im)
+	}
+	enabled := c.callConstructor(im)
+	disabled := im.gate.alternative.Call(nil)[0].Interface()
+	proxy, ok := c.options.proxies().Proxy(im.tp, func() interface{} {
+		if c.flagEnabled(im.gate.flag) {
+			return enabled
+		}
+		return disabled
+	}, nil)
+	if !ok {
+		panic(fmt.Errorf("gated instance %s of type %s could not be proxied by the proxy factory", im.name, im.tp))
+	}
+	return proxy
+}
+
+// switchedOnResolution reports whether a gated instance is of function or interface type the proxy factory can't
+// proxy, so it is switched each time it is resolved instead of per call.
+func (c *container) switchedOnResolution(im *instanceMethod) bool {
+	kind := im.tp.Kind()
+	return (kind == reflect.Func || kind == reflect.Interface) && !c.options.canProxy(im.tp)
+}
+
+// gateSwitch is a gated instance switched each time it is resolved, by name or by type. Both the instance and the
+// alternative are constructed, so either is injected or retrieved by the flag value at the time.
+type gateSwitch struct {
+	flag     string
+	tp       reflect.Type
+	enabled  interface{}
+	disabled interface{}
+}
+
+// switchInstance constructs both the instance and the alternative of a gated instance switched on resolution, and
+// returns the one of the current flag value.
+func (c *container) switchInstance(im *instanceMethod) interface{} {
+	s := &gateSwitch{flag: im.gate.flag, tp: im.tp}
+	s.enabled = c.postProcess(im, c.callConstructor(im))
+	c.checkNil(im, s.enabled)
+	s.disabled = c.postProcess(im, im.gate.alternative.Call(nil)[0].Interface())
+	c.checkNil(im, s.disabled)
+	c.mu.Lock()
+	if c.switches == nil {
+		c.switches = make(map[string]*gateSwitch)
+	}
+	c.switches[im.name] = s
+	c.mu.Unlock()
+	return c.switched(s)
+}
+
+// switched returns the instance of a switch by the current flag value.
+func (c *container) switched(s *gateSwitch) interface{} {
+	if c.flagEnabled(s.flag) {
+		return s.enabled
+	}
+	return s.disabled
+}
+
+// switchOfType returns the switch of the instance found by type t, if it is switched on resolution. If exact is
+// true, the instance is the only one of type t, otherwise the only one assignable to it. The caller must hold the
+// lock.
+func (c *container) switchOfType(t reflect.Type, exact bool) *gateSwitch {
+	for _, s := range c.switches {
+		if s.tp == t || !exact && s.tp.AssignableTo(t) {
+			return s
+		}
+	}
+	return nil
+}
+
+// switchedInstance constructs an instance bound to a feature flag once, by the current flag value.
+func (c *container) switchedInstance(im *instanceMethod) interface{} {
+	if c.flagEnabled(im.gate.flag) {
+		return c.callConstructor(im)
+	}
+	return im.gate.alternative.Call(nil)[0].Interface()
+}
@@ -0,0 +1,93 @@
+package alice
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+type GatedModule1 struct {
+	BaseModule
+}
+
+func (m *GatedModule1) D1() D1 {
+	return &countedD1{n: 1}
+}
+
+func (m *GatedModule1) Greet() func() string {
+	return func() string { return "hello" }
+}
+
+func (m *GatedModule1) Gates() map[string]Gate {
+	return map[string]Gate{
+		"D1":    {Flag: "d1", Alternative: func() D1 { return &countedD1{n: 0} }},
+		"Greet": {Flag: "greet", Alternative: func() func() string { return func() string { return "" } }},
+	}
+}
+
+type invalidGatedModule struct {
+	BaseModule
+}
+
+func (m *invalidGatedModule) D1() D1 {
+	return &D1Impl{}
+}
+
+func (m *invalidGatedModule) Gates() map[string]Gate {
+	return map[string]Gate{
+		"D1": {Flag: "d1", Alternative: func() *D2Impl { return &D2Impl{} }},
+	}
+}
+
+func TestFeatureGate(t *testing.T) {
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	c := CreateContainer(&GatedModule1{})
+	if n := c.Instance(d1Type).(*countedD1).n; n != 1 {
+		t.Errorf("bad instance without feature flags: got %d, expected %d", n, 1)
+	}
+
+	var greet, d1 atomic.Bool
+	flags := FeatureFlagsFunc(func(flag string) bool {
+		return flag == "greet" && greet.Load() || flag == "d1" && d1.Load()
+	})
+	c = CreateContainerWithOptions([]Module{&GatedModule1{}}, WithFeatureFlags(flags))
+	if n := c.Instance(d1Type).(*countedD1).n; n != 0 {
+		t.Errorf("bad instance with disabled flag: got %d, expected %d", n, 0)
+	}
+	f := c.InstanceByName("Greet").(func() string)
+	if s := f(); s != "" {
+		t.Errorf("bad function with disabled flag: got %q, expected %q", s, "")
+	}
+	greet.Store(true)
+	if s := f(); s != "hello" {
+		t.Errorf("bad function after enabling flag: got %q, expected %q", s, "hello")
+	}
+	d1.Store(true)
+	if n := c.Instance(d1Type).(*countedD1).n; n != 1 {
+		t.Errorf("bad instance after enabling flag: got %d, expected %d", n, 1)
+	}
+	if n := c.InstanceByName("D1").(*countedD1).n; n != 1 {
+		t.Errorf("bad instance by name after enabling flag: got %d, expected %d", n, 1)
+	}
+	d1.Store(false)
+
+	built := NewModule("built").
+		Provide("D1", func() D1 { return &countedD1{n: 1} }).
+		Gate("D1", "d1", func() D1 { return &countedD1{n: 0} }).
+		Build()
+	c = CreateContainerWithOptions([]Module{built}, WithFeatureFlags(flags))
+	if n := c.Instance(d1Type).(*countedD1).n; n != 0 {
+		t.Errorf("bad instance of built module with disabled flag: got %d, expected %d", n, 0)
+	}
+
+	if err := Validate(&invalidGatedModule{}); err == nil {
+		t.Error("expected error for unassignable alternative")
+	}
+	invalid := NewModule("invalid").
+		Provide("D1", func() D1 { return &D1Impl{} }).
+		Gate("D1", "d1", &D1Impl{}).
+		Build()
+	if err := Validate(invalid); err == nil {
+		t.Error("expected error for alternative which isn't a function")
+	}
+}
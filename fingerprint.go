@@ -0,0 +1,60 @@
+package alice
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+func (c *container) Fingerprint() string {
+	var lines []string
+	for _, rm := range c.reflected {
+		lines = append(lines, fmt.Sprintf("module %s %s", rm.name, moduleTypeName(rm.m)))
+		for _, instance := range rm.instances {
+			lines = append(lines, fmt.Sprintf("instance %s %s %s", rm.name, instance.name, typeName(instance.tp)))
+		}
+		for name := range c.graph.dependsOn[rm] {
+			lines = append(lines, fmt.Sprintf("depends %s %s", rm.name, name))
+		}
+		for dependant := range c.graph.g[rm] {
+			lines = append(lines, fmt.Sprintf("edge %s %s", rm.name, dependant.name))
+		}
+	}
+	sort.Strings(lines)
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// moduleTypeName returns the canonical type name of a module.
+func moduleTypeName(m Module) string {
+	if _, ok := m.(*builtModule); ok {
+		return "built"
+	}
+	return typeName(modulePtrType(m))
+}
+
+// typeName returns the canonical name of a type, which includes the full package path of named types, e.g.
+// "*github.com/magic003/alice.BaseModule".
+func typeName(t reflect.Type) string {
+	if t.Name() != "" && t.PkgPath() != "" {
+		return t.PkgPath() + "." + t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + typeName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), typeName(t.Elem()))
+	case reflect.Map:
+		return "map[" + typeName(t.Key()) + "]" + typeName(t.Elem())
+	case reflect.Chan:
+		return t.ChanDir().String() + " " + typeName(t.Elem())
+	default:
+		return t.String()
+	}
+}
@@ -0,0 +1,39 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	f1 := CreateContainer(&M1{}, &M2{}, &M3{}, &M4{}, &M5{}).(Introspector).Fingerprint()
+	f2 := CreateContainerWithOptions([]Module{&M5{}, &M4{}, &M3{}, &M2{}, &M1{}}, WithLazy()).(Introspector).Fingerprint()
+	if f1 != f2 {
+		t.Errorf("fingerprints of the same wiring are expected to be equal: got %s and %s", f1, f2)
+	}
+
+	f3 := CreateContainer(&M1{}, &M2{}, &M3{}, &M4{}).(Introspector).Fingerprint()
+	if f1 == f3 {
+		t.Errorf("fingerprints of different wirings are expected to be different: got %s", f1)
+	}
+	if len(f1) != 64 {
+		t.Errorf("bad length of fingerprint: got %d, expected %d", len(f1), 64)
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		tp       reflect.Type
+		expected string
+	}{
+		{reflect.TypeOf((*D1)(nil)).Elem(), "github.com/magic003/alice.D1"},
+		{reflect.TypeOf(&D1Impl{}), "*github.com/magic003/alice.D1Impl"},
+		{reflect.TypeOf(map[string][]*D1Impl{}), "map[string][]*github.com/magic003/alice.D1Impl"},
+		{reflect.TypeOf(""), "string"},
+	}
+	for _, test := range tests {
+		if actual := typeName(test.tp); actual != test.expected {
+			t.Errorf("bad type name of %s: got %s, expected %s", test.tp, actual, test.expected)
+		}
+	}
+}
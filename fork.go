@@ -0,0 +1,67 @@
+package alice
+
+import "reflect"
+
+// Override replaces an instance in a fork of a container.
+type Override struct {
+	name     string
+	tp       reflect.Type
+	instance interface{}
+}
+
+// OverrideName resolves the specified name to the instance in a fork.
+func OverrideName(name string, instance interface{}) Override {
+	return Override{name: name, instance: instance}
+}
+
+// OverrideType resolves the specified type to the instance in a fork.
+func OverrideType(t reflect.Type, instance interface{}) Override {
+	return Override{tp: t, instance: instance}
+}
+
+// fork is a view of a container resolving some names and types to alternate instances. Everything else, including
+// the lifecycle methods, is delegated to the parent container.
+type fork struct {
+	extendedContainer
+	byName map[string]interface{}
+	byType map[reflect.Type]interface{}
+}
+
+func (c *container) Fork(overrides ...Override) Container {
+	return newFork(c, overrides)
+}
+
+func (f *fork) Fork(overrides ...Override) Container {
+	return newFork(f, overrides)
+}
+
+// newFork creates a fork of the parent container with the overrides.
+func newFork(parent extendedContainer, overrides []Override) *fork {
+	f := &fork{
+		extendedContainer: parent,
+		byName:            make(map[string]interface{}),
+		byType:            make(map[reflect.Type]interface{}),
+	}
+	for _, o := range overrides {
+		if o.tp != nil {
+			f.byType[o.tp] = o.instance
+		} else {
+			f.byName[o.name] = o.instance
+		}
+	}
+	return f
+}
+
+func (f *fork) Instance(t reflect.Type) interface{} {
+	if instance, ok := f.byType[t]; ok {
+		return instance
+	}
+	return f.extendedContainer.Instance(t)
+}
+
+func (f *fork) InstanceByName(name string) interface{} {
+	if instance, ok := f.byName[name]; ok {
+		return instance
+	}
+	return f.extendedContainer.InstanceByName(name)
+}
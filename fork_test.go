@@ -0,0 +1,36 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFork(t *testing.T) {
+	c := CreateContainer(&M1{})
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	d2Type := reflect.TypeOf((*D2)(nil)).Elem()
+	alternate := &countedD1{n: 1}
+	f := c.(Scoper).Fork(OverrideName("D1", alternate), OverrideType(d1Type, alternate))
+
+	if instance := f.InstanceByName("D1"); instance != alternate {
+		t.Errorf("bad instance by name from Fork(): got %v, expected %v", instance, alternate)
+	}
+	if instance := f.Instance(d1Type); instance != alternate {
+		t.Errorf("bad instance by type from Fork(): got %v, expected %v", instance, alternate)
+	}
+	if instance := f.Instance(d2Type); instance != c.Instance(d2Type) {
+		t.Errorf("bad shared instance from Fork(): got %v, expected %v", instance, c.Instance(d2Type))
+	}
+	if instance := c.Instance(d1Type); instance == alternate {
+		t.Error("parent container is expected to be unchanged after Fork()")
+	}
+
+	nested := &countedD1{n: 2}
+	ff := f.(Scoper).Fork(OverrideName("D1", nested))
+	if instance := ff.InstanceByName("D1"); instance != nested {
+		t.Errorf("bad instance by name from nested Fork(): got %v, expected %v", instance, nested)
+	}
+	if instance := ff.Instance(d1Type); instance != alternate {
+		t.Errorf("bad instance by type from nested Fork(): got %v, expected %v", instance, alternate)
+	}
+}
@@ -0,0 +1,79 @@
+package alice
+
+import "errors"
+
+// ErrorFormatter renders the messages of container failures, e.g. to add team routing hints or internal wiki links,
+// or to localize them. The error is the structured failure, which could be inspected by errors.As with LookupError,
+// ConstructionError or Errors.
+type ErrorFormatter interface {
+	// Format returns the message of the error.
+	Format(err error) string
+}
+
+// ErrorFormatterFunc is an adapter to allow the use of an ordinary function as ErrorFormatter.
+type ErrorFormatterFunc func(err error) string
+
+// Format calls f(err).
+func (f ErrorFormatterFunc) Format(err error) string {
+	return f(err)
+}
+
+// FormattedError is a container failure whose message is rendered by the ErrorFormatter set by WithErrorFormatter.
+// It is the panic value, or the error returned, instead of the failure itself.
+type FormattedError struct {
+	// Err is the failure.
+	Err error
+	// Message is the message rendered by the formatter.
+	Message string
+}
+
+// Error returns the rendered message.
+func (e *FormattedError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the failure, so errors.Is and errors.As still inspect it.
+func (e *FormattedError) Unwrap() error {
+	return e.Err
+}
+
+// WithErrorFormatter returns an option which renders the messages of the container failures by the formatter: the
+// panics of the container creation, Environments.CreateContainer, MustInstance, MustInstanceByName, GetNamed and
+// Accessor.Get, and the errors of Resolve, ResolveByName, Start, Stop and ValidateWithOptions.
+func WithErrorFormatter(formatter ErrorFormatter) Option {
+	return func(o *options) {
+		o.errorFormatter = formatter
+	}
+}
+
+// formatError returns err as a FormattedError if a formatter is set. An error formatted already is kept, so a failure
+// passing through nested calls is formatted once.
+func (o *options) formatError(err error) error {
+	if err == nil || o.errorFormatter == nil {
+		return err
+	}
+	var formatted *FormattedError
+	if errors.As(err, &formatted) {
+		return err
+	}
+	return &FormattedError{Err: err, Message: o.errorFormatter.Format(err)}
+}
+
+// formatContainerError formats err by the formatter of c, if c is created by CreateContainer.
+func formatContainerError(c Container, err error) error {
+	if fc, ok := c.(*container); ok {
+		return fc.options.formatError(err)
+	}
+	return err
+}
+
+// formatPanic is deferred to panic again with the recovered error formatted. Panics of other values are propagated
+// as they are.
+func (c *container) formatPanic() {
+	if r := recover(); r != nil {
+		if err, ok := r.(error); ok {
+			panic(c.options.formatError(err))
+		}
+		panic(r)
+	}
+}
@@ -0,0 +1,87 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var wikiFormatter = ErrorFormatterFunc(func(err error) string {
+	return err.Error() + " (see wiki/alice)"
+})
+
+func TestWithErrorFormatter(t *testing.T) {
+	c := CreateContainerWithOptions([]Module{&M1{}}, WithErrorFormatter(wikiFormatter))
+
+	_, err := c.ResolveByName("D6")
+	if err == nil || err.Error() != "instance name D6 not defined (see wiki/alice)" {
+		t.Errorf("bad error after ResolveByName(): got %v, expected the formatted message", err)
+	}
+	var lookupErr *LookupError
+	if !errors.As(err, &lookupErr) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("bad error after ResolveByName(): got %v, expected to wrap %v", err, ErrNotFound)
+	}
+
+	func() {
+		defer func() {
+			r := recover()
+			if _, ok := r.(*FormattedError); !ok {
+				t.Errorf("bad panic after MustInstance(): got %v, expected *FormattedError", r)
+			}
+		}()
+		c.MustInstance(reflect.TypeOf(&D5Impl{}))
+	}()
+
+	if err := c.(Lifecycle).Start(context.Background()); err != nil {
+		t.Errorf("bad error after Start(): got %v, expected nil", err)
+	}
+}
+
+func TestWithErrorFormatter_Populate(t *testing.T) {
+	defer func() {
+		r := recover()
+		err, ok := r.(*FormattedError)
+		if !ok {
+			t.Fatalf("bad panic after CreateContainer(): got %v, expected *FormattedError", r)
+		}
+		var ce *ConstructionError
+		if !errors.As(err, &ce) || err.Message != ce.Error()+" (see wiki/alice)" {
+			t.Errorf("bad panic after CreateContainer(): got %v, expected the formatted construction error", err)
+		}
+	}()
+	CreateContainerWithOptions([]Module{&failingModule{}, &M1{}}, WithErrorFormatter(wikiFormatter))
+}
+
+func TestWithErrorFormatter_TypeMismatch(t *testing.T) {
+	c := CreateContainerWithOptions([]Module{&M1{}}, WithErrorFormatter(wikiFormatter))
+	expectFormatted := func(name string, f func()) {
+		defer func() {
+			r := recover()
+			if _, ok := r.(*FormattedError); !ok {
+				t.Errorf("bad panic after %s(): got %v, expected *FormattedError", name, r)
+			}
+		}()
+		f()
+	}
+	expectFormatted("GetNamed", func() { GetNamed[*D5Impl](c, "D1") })
+	expectFormatted("Get", func() { NewAccessor[*D5Impl](c, "D1").Get() })
+	expectFormatted("CreateContainer", func() {
+		NewEnvironments(&M1{}).CreateContainer("undeclared", WithErrorFormatter(wikiFormatter))
+	})
+}
+
+func TestFormatError(t *testing.T) {
+	o := newOptions(WithErrorFormatter(wikiFormatter))
+	if err := o.formatError(nil); err != nil {
+		t.Errorf("bad error after formatError(nil): got %v, expected nil", err)
+	}
+	once := o.formatError(errors.New("failed"))
+	if twice := o.formatError(once); twice != once {
+		t.Errorf("bad error after formatError() again: got %v, expected %v", twice, once)
+	}
+	plain := newOptions()
+	if err := plain.formatError(errors.New("failed")); err.Error() != "failed" {
+		t.Errorf("bad error after formatError() without formatter: got %v, expected failed", err)
+	}
+}
@@ -0,0 +1,98 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ModuleFunc is a module written as a function returning its instances keyed by names. A value is either a
+// constructor, which is a function with 1 return value like the ones passed to ModuleBuilder.Provide, or the instance
+// itself. A function instance with 1 return value must be wrapped by a constructor. It is handy for tiny modules and
+// scripts:
+//
+//	alice.CreateContainer(alice.ModuleFunc(func() map[string]interface{} {
+//		return map[string]interface{}{
+//			"Addr":   ":8080",
+//			"Server": func(addr string) *http.Server { return &http.Server{Addr: addr} },
+//		}
+//	}))
+//
+// The function is called whenever the module is reflected, i.e. when a container is created, and again by Plan,
+// Analyze, Validate and Rebuilder.Without. The module is named after the function, e.g. "main.func1" for a function
+// literal, which changes as the code around it is edited. Its instances are defined in the order of their names.
+// NewModuleFunc names the module explicitly, and calls the function only once.
+type ModuleFunc func() map[string]interface{}
+
+// IsModule indicates it is a module.
+func (f ModuleFunc) IsModule() bool {
+	return true
+}
+
+// build builds the module defined by the function.
+func (f ModuleFunc) build() *builtModule {
+	return buildFuncModule(funcModuleName(f), f)
+}
+
+// NewModuleFunc returns a module defined by the function like ModuleFunc, with the specified name. The function is
+// called once, when the module is reflected for the first time, and the module keeps the instances it returns, so
+// every container created from the module, as well as Plan, Analyze and Fingerprint, sees the same module.
+func NewModuleFunc(name string, f func() map[string]interface{}) Module {
+	return &funcModule{name: name, f: f}
+}
+
+// funcModule is a module created by NewModuleFunc.
+type funcModule struct {
+	BaseModule
+	name  string
+	f     func() map[string]interface{}
+	once  sync.Once
+	built *builtModule
+}
+
+// build builds the module on first call, and returns the same module afterwards.
+func (m *funcModule) build() *builtModule {
+	m.once.Do(func() {
+		m.built = buildFuncModule(m.name, m.f)
+	})
+	return m.built
+}
+
+// buildFuncModule builds the module with the specified name from the instances returned by the function.
+func buildFuncModule(name string, f func() map[string]interface{}) *builtModule {
+	b := NewModule(name)
+	providers := f()
+	var names []string
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := providers[name]
+		if value == nil {
+			b.setError(fmt.Errorf("instance %s.%s is nil", b.m.name, name))
+			continue
+		}
+		v := reflect.ValueOf(value)
+		if v.Kind() == reflect.Func && v.Type().NumOut() == 1 {
+			b.Provide(name, value)
+			continue
+		}
+		constructor := reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{v.Type()}, false),
+			func([]reflect.Value) []reflect.Value {
+				return []reflect.Value{v}
+			})
+		b.Provide(name, constructor.Interface())
+	}
+	return b.Build().(*builtModule)
+}
+
+// funcModuleName returns the name of the function without the package path, e.g. "main.storageModule".
+func funcModuleName(f ModuleFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+	return name[strings.LastIndex(name, "/")+1:]
+}
@@ -0,0 +1,78 @@
+package alice
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type funcServer struct {
+	addr string
+}
+
+func funcConfigModule() map[string]interface{} {
+	return map[string]interface{}{
+		"Addr": ":8080",
+	}
+}
+
+func TestModuleFunc(t *testing.T) {
+	server := ModuleFunc(func() map[string]interface{} {
+		return map[string]interface{}{
+			"Server": func(addr string) *funcServer { return &funcServer{addr: addr} },
+		}
+	})
+	c := CreateContainer(ModuleFunc(funcConfigModule), server)
+
+	if addr := c.InstanceByName("Addr"); addr != ":8080" {
+		t.Errorf("bad instance Addr from ModuleFunc: got %v, expected :8080", addr)
+	}
+	s, ok := c.InstanceByName("Server").(*funcServer)
+	if !ok || s.addr != ":8080" {
+		t.Errorf("bad instance Server from ModuleFunc: got %v, expected server at :8080", c.InstanceByName("Server"))
+	}
+
+	infos := c.(Introspector).Instances()
+	if len(infos) != 2 || infos[0].Module != "alice.funcConfigModule" {
+		t.Errorf("bad module name of ModuleFunc: got %v, expected alice.funcConfigModule", infos)
+	}
+}
+
+func TestModuleFunc_Invalid(t *testing.T) {
+	m := ModuleFunc(func() map[string]interface{} {
+		return map[string]interface{}{"Missing": nil}
+	})
+	_, err := reflectModule(m)
+	if err == nil || !strings.HasSuffix(err.Error(), ".Missing is nil") {
+		t.Errorf("bad error from reflectModule() with nil instance: got %v, expected instance is nil", err)
+	}
+}
+
+func TestNewModuleFunc(t *testing.T) {
+	calls := 0
+	config := NewModuleFunc("config", func() map[string]interface{} {
+		calls++
+		return map[string]interface{}{"Addr": ":8080"}
+	})
+	c := CreateContainer(config, &M5{})
+	if _, err := Plan([]Module{config}); err != nil {
+		t.Fatalf("bad error after Plan(): got %v, expected nil", err)
+	}
+	if _, err := c.(Rebuilder).Without(reflect.TypeOf(M5{})); err != nil {
+		t.Fatalf("bad error after Without(): got %v, expected nil", err)
+	}
+	if calls != 1 {
+		t.Errorf("bad calls of the module function: got %d, expected %d", calls, 1)
+	}
+	if infos := c.(Introspector).Instances(); len(infos) != 1 || infos[0].Module != "config" {
+		t.Errorf("bad module name of NewModuleFunc(): got %v, expected config", infos)
+	}
+
+	other := NewModuleFunc("other", func() map[string]interface{} {
+		return map[string]interface{}{"Addr": ":8080"}
+	})
+	fingerprint := CreateContainer(other).(Introspector).Fingerprint()
+	if fingerprint == CreateContainer(config).(Introspector).Fingerprint() {
+		t.Errorf("bad fingerprints of modules with different names: got the same %s", fingerprint)
+	}
+}
@@ -0,0 +1,98 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Get returns the instance of type T from the container, which could be an instantiation of a generic type:
+//
+//	users := alice.Get[*Cache[User]](c)
+//
+// Instantiations are distinct types, so Cache[User] and Cache[Order] never resolve to each other. Failures are
+// handled as by Container.Instance.
+func Get[T any](c Container) T {
+	value, _ := c.Instance(reflect.TypeOf((*T)(nil)).Elem()).(T)
+	return value
+}
+
+// GetNamed returns the instance with the specified name from the container. It panics if the instance is not of
+// type T. Other failures are handled as by Container.InstanceByName.
+func GetNamed[T any](c Container, name string) T {
+	instance := c.InstanceByName(name)
+	if instance == nil {
+		var zero T
+		return zero
+	}
+	value, ok := instance.(T)
+	if !ok {
+		panic(formatContainerError(c, fmt.Errorf("instance of type %T is not a %s", instance,
+			reflect.TypeOf((*T)(nil)).Elem())))
+	}
+	return value
+}
+
+// packageQualifier matches the package qualifiers of the type arguments in a type name, like "example.com/app.".
+var packageQualifier = regexp.MustCompile(`(?:[\w.\-~%]+/)*[\w\-~%]+\.`)
+
+// shortTypeName returns the name of a type to name instances and modules after. The type arguments of a generic type
+// are named without package paths, e.g. "Cache[User]" instead of "Cache[example.com/app.User]", as dots separate the
+// namespaces of qualified names. The names colliding this way are reported by checkTypeNameCollisions.
+func shortTypeName(t reflect.Type) string {
+	name := t.Name()
+	for i := 0; i < len(name); i++ {
+		if name[i] == '[' {
+			return name[:i] + packageQualifier.ReplaceAllString(name[i:], "")
+		}
+	}
+	return name
+}
+
+// checkTypeNameCollisions reports the instances and modules named after instantiations of generic types which are
+// only told apart by the package paths of their type arguments, e.g. Cache[a.User] and Cache[b.User], before the
+// name conflicts are resolved, so they are never silently taken as duplicates.
+func (g *graph) checkTypeNameCollisions() error {
+	var errs []error
+	instanceTypes := make(map[string]reflect.Type)
+	moduleTypes := make(map[string]reflect.Type)
+	for _, rm := range g.modules {
+		if t := reflect.TypeOf(rm.m); t != nil {
+			if err := checkTypeNameCollision(moduleTypes, "module", rm.name, t); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		for _, instance := range rm.instances {
+			if err := checkTypeNameCollision(instanceTypes, "instance", instance.name, instance.tp); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return joinErrors(errs)
+}
+
+// checkTypeNameCollision records the type of the named instance or module, and returns error if another generic type
+// of the same short name is recorded with the name, which both are named after.
+func checkTypeNameCollision(types map[string]reflect.Type, kind string, name string, t reflect.Type) error {
+	existing, ok := types[name]
+	if !ok {
+		types[name] = t
+		return nil
+	}
+	short := shortTypeName(indirectType(t))
+	if existing == t || short == indirectType(t).Name() || short != shortTypeName(indirectType(existing)) ||
+		name != short && !strings.HasSuffix(name, "."+short) {
+		return nil
+	}
+	return fmt.Errorf("%s name %s of types %s and %s collides, as type arguments are named without package paths; "+
+		"name one of them explicitly", kind, name, typeName(existing), typeName(t))
+}
+
+// indirectType returns the element type of a pointer type, or the type itself otherwise.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
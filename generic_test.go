@@ -0,0 +1,121 @@
+package alice
+
+import (
+	htmltemplate "html/template"
+	"reflect"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+)
+
+type Cache[T any] struct {
+	values []T
+}
+
+type Store[T any] interface {
+	Load() T
+}
+
+type memoryStore[T any] struct {
+	value T
+}
+
+func (s *memoryStore[T]) Load() T {
+	return s.value
+}
+
+type StoreModule[T any] struct {
+	BaseModule
+	Store Store[T] `alice:""`
+}
+
+func TestGet(t *testing.T) {
+	m := NewTypedModule("caches").
+		Provide(
+			Singleton(func() *Cache[string] { return &Cache[string]{values: []string{"a"}} }),
+			Singleton(func() *Cache[int] { return &Cache[int]{values: []int{1}} }),
+			Singleton(func() Store[string] { return &memoryStore[string]{value: "s"} }),
+			Singleton(func() Store[int] { return &memoryStore[int]{value: 1} }),
+		).
+		Build()
+	c := CreateContainer(m)
+
+	if cache := Get[*Cache[string]](c); len(cache.values) != 1 || cache.values[0] != "a" {
+		t.Errorf("bad instance after Get[*Cache[string]](): got %v", cache)
+	}
+	if cache := Get[*Cache[int]](c); len(cache.values) != 1 || cache.values[0] != 1 {
+		t.Errorf("bad instance after Get[*Cache[int]](): got %v", cache)
+	}
+	if s := Get[Store[string]](c).Load(); s != "s" {
+		t.Errorf("bad instance after Get[Store[string]](): got %q, expected %q", s, "s")
+	}
+	if n := Get[Store[int]](c).Load(); n != 1 {
+		t.Errorf("bad instance after Get[Store[int]](): got %d, expected %d", n, 1)
+	}
+
+	if cache := GetNamed[*Cache[int]](c, "Cache[int]"); cache.values[0] != 1 {
+		t.Errorf("bad instance after GetNamed(): got %v", cache)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for instance of another instantiation")
+		}
+	}()
+	GetNamed[*Cache[string]](c, "Cache[int]")
+}
+
+func TestGet_GenericModule(t *testing.T) {
+	m := NewTypedModule("stores").
+		Provide(Singleton(func() Store[int] { return &memoryStore[int]{value: 2} })).
+		Build()
+	module := &StoreModule[int]{}
+	c := CreateContainerWithOptions([]Module{m, module}, WithNamespaces())
+	if module.Store.Load() != 2 {
+		t.Errorf("bad dependency of generic module: got %v", module.Store)
+	}
+	names := c.(Introspector).Modules()
+	if len(names) != 2 || names[1].Name != "StoreModule[int]" {
+		t.Errorf("bad modules after CreateContainer(): got %v", names)
+	}
+}
+
+func TestCreateContainer_TypeNameCollision(t *testing.T) {
+	text := Singleton(func() *Cache[*texttemplate.Template] { return &Cache[*texttemplate.Template]{} })
+	html := Singleton(func() *Cache[*htmltemplate.Template] { return &Cache[*htmltemplate.Template]{} })
+	_, err := Plan([]Module{NewTypedModule("templates").Provide(text, html).Build()},
+		WithConflictPolicy(FirstWins))
+	if err == nil || !strings.Contains(err.Error(), "instance name Cache[*Template] of types") {
+		t.Errorf("bad error after Plan() with colliding instance names: got %v", err)
+	}
+	_, err = Plan([]Module{&StoreModule[*texttemplate.Template]{}, &StoreModule[*htmltemplate.Template]{},
+		NewTypedModule("stores").Provide(
+			Singleton(func() Store[*texttemplate.Template] { return nil }),
+			Singleton(func() Store[*htmltemplate.Template] { return nil }).Named("HTMLStore"),
+		).Build()})
+	if err == nil || !strings.Contains(err.Error(), "module name StoreModule[*Template] of types") ||
+		strings.Contains(err.Error(), "instance name") {
+		t.Errorf("bad error after Plan() with colliding module names: got %v", err)
+	}
+
+	c := CreateContainer(NewTypedModule("templates").Provide(text, html.Named("HTMLCache")).Build())
+	if Get[*Cache[*htmltemplate.Template]](c) == nil {
+		t.Error("bad instance after CreateContainer() with an explicitly named instance: got nil")
+	}
+}
+
+func TestShortTypeName(t *testing.T) {
+	cases := []struct {
+		t        reflect.Type
+		expected string
+	}{
+		{reflect.TypeOf(Cache[string]{}), "Cache[string]"},
+		{reflect.TypeOf(Cache[*D5Impl]{}), "Cache[*D5Impl]"},
+		{reflect.TypeOf(Cache[map[string]Cache[D5Impl]]{}), "Cache[map[string]Cache[D5Impl]]"},
+		{reflect.TypeOf(D5Impl{}), "D5Impl"},
+	}
+	for _, c := range cases {
+		if name := shortTypeName(c.t); name != c.expected {
+			t.Errorf("bad name after shortTypeName(%s): got %s, expected %s", c.t, name, c.expected)
+		}
+	}
+}
@@ -3,27 +3,62 @@ package alice
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 )
 
 // createGraph creates a graph of modules.
 func createGraph(modules ...*reflectedModule) (*graph, error) {
+	return createGraphWithOptions(options{}, modules...)
+}
+
+// createGraphWithOptions creates a graph of modules with the options of the container. If any dependency is invalid,
+// the graph of the valid dependencies is still returned with the error, so the cycles among them could be detected.
+func createGraphWithOptions(o options, modules ...*reflectedModule) (*graph, error) {
 	g := &graph{
-		modules: modules,
-		g:       make(map[*reflectedModule]map[*reflectedModule]bool),
+		modules:     modules,
+		options:     o,
+		g:           make(map[*reflectedModule]map[*reflectedModule]bool),
+		lazyEdges:   make(map[*reflectedModule]map[*reflectedModule]bool),
+		depended:    make(map[string]bool),
+		dependsOn:   make(map[*reflectedModule]map[string]bool),
+		typeWinners: make(map[reflect.Type]string),
+		adapted:     make(map[reflect.Type]*adapter),
+	}
+	var errs []error
+	adapters, err := newAdapters(o.adapters)
+	if err != nil {
+		errs = append(errs, err)
 	}
+	g.adapters = adapters
 	if err := g.constructGraph(); err != nil {
-		return nil, err
+		errs = append(errs, err)
 	}
-	return g, nil
+	return g, joinErrors(errs)
 }
 
 // graph maintains the dependency relationship of the modules and gives an instantiation order.
 type graph struct {
 	modules []*reflectedModule
+	options options
 	// g is map representing the dependency graph. Modules in value depend on the key.
 	// Value is a map to avoid duplication.
 	g map[*reflectedModule]map[*reflectedModule]bool
+	// lazyEdges contains the dependencies of lazy fields, in the same form as g. They don't order the instantiation,
+	// so they could break cycles. deferred indicates the dependencies being created are lazy.
+	lazyEdges map[*reflectedModule]map[*reflectedModule]bool
+	deferred  bool
+	// depended contains the names of instances depended on by modules.
+	depended map[string]bool
+	// dependsOn contains the names of instances each module depends on.
+	dependsOn map[*reflectedModule]map[string]bool
+	// typeWinners contains the names of the instances picked by the conflict policy for types declared by multiple
+	// instances.
+	typeWinners map[reflect.Type]string
+	// adapters contains the adapters registered by WithAdapters, keyed by the types they convert to. adapted contains
+	// the ones used for dependencies not provided.
+	adapters map[reflect.Type]*adapter
+	adapted  map[reflect.Type]*adapter
 }
 
 // moduleSlice is a container of reflected module slice.
@@ -32,17 +67,12 @@ type moduleSlice struct {
 	modules []*reflectedModule
 }
 
-// stringSlice is a container of string slice. The purpose is to be passed in recursive calls and update the slice.
-type stringSlice struct {
-	strings []string
-}
-
 // instantiationOrder returns the instantiation order of the modules. It returns error if there is cyclic dependencies.
 func (g *graph) instantiationOrder() ([]*reflectedModule, error) {
 	visited := make(map[*reflectedModule]bool)
 	stack := &moduleSlice{}
 	recVisited := make(map[*reflectedModule]bool)
-	recPath := &stringSlice{}
+	recPath := &moduleSlice{}
 
 	for _, m := range g.modules {
 		if !visited[m] {
@@ -61,14 +91,14 @@ func (g *graph) dfs(
 	visited map[*reflectedModule]bool,
 	stack *moduleSlice,
 	recVisited map[*reflectedModule]bool,
-	recPath *stringSlice) error {
-	recPath.strings = append(recPath.strings, m.name)
+	recPath *moduleSlice) error {
+	recPath.modules = append(recPath.modules, m)
 	if recVisited[m] { // cyclic
-		return fmt.Errorf("cyclic dependencies for modules: %s", strings.Join(recPath.strings, " -> "))
+		return g.cycleError(recPath.modules)
 	}
 
 	recVisited[m] = true
-	for dependant := range g.g[m] {
+	for _, dependant := range g.dependantsOf(m) {
 		if !visited[dependant] {
 			if err := g.dfs(dependant, visited, stack, recVisited, recPath); err != nil {
 				return err
@@ -79,35 +109,112 @@ func (g *graph) dfs(
 	visited[m] = true
 	stack.modules = append(stack.modules, m)
 	recVisited[m] = false
-	recPath.strings = recPath.strings[:len(recPath.strings)-1]
+	recPath.modules = recPath.modules[:len(recPath.modules)-1]
 
 	return nil
 }
 
-// constructGraph constructs a graph based on the dependency of the modules.
+// dependantsOf returns the modules depending on a module, in the order the modules are specified, so the
+// instantiation order is deterministic.
+func (g *graph) dependantsOf(m *reflectedModule) []*reflectedModule {
+	var dependants []*reflectedModule
+	for _, other := range g.modules {
+		if g.g[m][other] {
+			dependants = append(dependants, other)
+		}
+	}
+	return dependants
+}
+
+// constructGraph constructs a graph based on the dependency of the modules. It reports all problems found, rather
+// than stopping at the first one.
 func (g *graph) constructGraph() error {
+	var errs []error
+	if err := g.checkTypeNameCollisions(); err != nil {
+		errs = append(errs, err)
+	}
+	g.removeShadowedFallbacks()
+	if err := g.resolveNameConflicts(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := g.resolveTypeConflicts(); err != nil {
+		errs = append(errs, err)
+	}
 	nameToProviderMap, typeToProvidersMap, err := g.computeProviders()
 	if err != nil {
-		return err
+		errs = append(errs, err)
+	}
+
+	var names *shortNames
+	if g.options.namespaces {
+		names = newShortNames(g.modules)
 	}
 
+	groups := g.computeGroups()
+
 	// construct dependency graph
 	for _, rm := range g.modules {
+		for _, depField := range rm.listDepends {
+			if depField.group != "" {
+				depField.names = groups[depField.group]
+			} else if depField.keyed {
+				depField.names = withPrefix(g.assignableInstances(rm, depField.field.Type().Elem()), depField.prefix)
+			}
+		}
+		if names != nil {
+			if err := g.qualifyDependencies(rm, names); err != nil {
+				errs = append(errs, err)
+			}
+		}
 		if err := g.createDependenciesByNames(rm, nameToProviderMap); err != nil {
-			return err
+			errs = append(errs, err)
 		}
 		if err := g.createDependenciesByTypes(rm, typeToProvidersMap); err != nil {
-			return err
+			errs = append(errs, err)
+		}
+		if err := g.createDependenciesByParams(rm, names, nameToProviderMap, typeToProvidersMap); err != nil {
+			errs = append(errs, err)
 		}
 		if _, ok := g.g[rm]; !ok {
 			g.g[rm] = make(map[*reflectedModule]bool)
 		}
 	}
+	if err := g.checkRules(); err != nil {
+		errs = append(errs, err)
+	}
 
-	return nil
+	return joinErrors(errs)
+}
+
+// qualifyDependencies replaces the short names of named dependencies with the fully qualified names.
+func (g *graph) qualifyDependencies(rm *reflectedModule, names *shortNames) error {
+	var errs []error
+	for _, depField := range rm.namedDepends {
+		qualified, err := names.qualify(depField.name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dependency name %s.%s: %s", rm.name, depField.name, err.Error()))
+			continue
+		}
+		depField.name = qualified
+	}
+	for _, depField := range rm.listDepends {
+		if depField.computed() {
+			continue // computed from qualified names
+		}
+		for i, name := range depField.names {
+			qualified, err := names.qualify(name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("dependency name %s.%s: %s", rm.name, name, err.Error()))
+				continue
+			}
+			depField.names[i] = qualified
+		}
+	}
+	return joinErrors(errs)
 }
 
 // computeProviders figures out instance names and types, and the corresponding modules that provide them.
+// For duplicated names, the first provider is kept and an error is reported.
 func (g *graph) computeProviders() (
 	map[string]*reflectedModule,
 	map[reflect.Type][]*reflectedModule,
@@ -115,13 +222,15 @@ func (g *graph) computeProviders() (
 
 	nameToProviderMap := make(map[string]*reflectedModule)
 	typeToProvidersMap := make(map[reflect.Type][]*reflectedModule)
+	var errs []error
 
 	for _, provider := range g.modules {
 		for _, instance := range provider.instances {
 			name := instance.name
 			if existingProvider, ok := nameToProviderMap[name]; ok {
-				return nil, nil,
-					fmt.Errorf("duplicated name %s in module %s and %s", name, existingProvider.name, provider.name)
+				errs = append(errs,
+					fmt.Errorf("duplicated name %s in module %s and %s", name, existingProvider.name, provider.name))
+				continue
 			}
 			nameToProviderMap[name] = provider
 
@@ -132,49 +241,249 @@ func (g *graph) computeProviders() (
 		}
 	}
 
-	return nameToProviderMap, typeToProvidersMap, nil
+	return nameToProviderMap, typeToProvidersMap, joinErrors(errs)
+}
+
+// removeShadowedFallbacks removes the fallback instances whose names are provided by other instances which are not
+// fallbacks, or whose types the types of such instances are assignable to.
+func (g *graph) removeShadowedFallbacks() {
+	var types []reflect.Type
+	names := make(map[string]bool)
+	for _, rm := range g.modules {
+		for _, instance := range rm.instances {
+			if !instance.fallback {
+				types = append(types, instance.tp)
+				names[instance.name] = true
+			}
+		}
+	}
+	shadowed := func(instance *instanceMethod) bool {
+		if names[instance.name] {
+			return true
+		}
+		for _, t := range types {
+			if t.AssignableTo(instance.tp) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, rm := range g.modules {
+		var kept []*instanceMethod
+		for _, instance := range rm.instances {
+			if !instance.fallback || !shadowed(instance) {
+				kept = append(kept, instance)
+			}
+		}
+		rm.instances = kept
+	}
+}
+
+// computeGroups figures out the names of instances contributed to each group, ordered by priority. Instances with the
+// same priority keep the order of the modules and their instance methods.
+func (g *graph) computeGroups() map[string][]string {
+	type member struct {
+		name     string
+		priority int
+	}
+	members := make(map[string][]member)
+	for _, rm := range g.modules {
+		for _, instance := range rm.instances {
+			for _, contribution := range instance.contributions {
+				members[contribution.Group] = append(members[contribution.Group], member{
+					name:     instance.name,
+					priority: contribution.Priority,
+				})
+			}
+		}
+	}
+
+	groups := make(map[string][]string)
+	for group, ms := range members {
+		sort.SliceStable(ms, func(i, j int) bool {
+			return ms[i].priority < ms[j].priority
+		})
+		for _, m := range ms {
+			groups[group] = append(groups[group], m.name)
+		}
+	}
+	return groups
+}
+
+// assignableInstances returns the names of instances of other modules whose types are the same as or, unless in strict
+// mode, assignable to the specified type.
+func (g *graph) assignableInstances(rm *reflectedModule, t reflect.Type) []string {
+	var names []string
+	for _, provider := range g.modules {
+		if provider == rm {
+			continue
+		}
+		for _, instance := range provider.instances {
+			if instance.tp == t || (!g.options.strict && instance.tp.AssignableTo(t)) {
+				names = append(names, instance.name)
+			}
+		}
+	}
+	return names
+}
+
+// withPrefix returns the names with the prefix. An empty prefix matches all names.
+func withPrefix(names []string, prefix string) []string {
+	if prefix == "" {
+		return names
+	}
+	var matched []string
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			matched = append(matched, name)
+		}
+	}
+	return matched
 }
 
 // createDependenciesByNames creates dependencies of a module using its named dependencies.
 func (g *graph) createDependenciesByNames(rm *reflectedModule, nameToProviderMap map[string]*reflectedModule) error {
+	var errs []error
 	for _, depField := range rm.namedDepends {
 		depName := depField.name
 		provider, ok := nameToProviderMap[depName]
 		if !ok {
-			return fmt.Errorf("dependency name %s.%s is not found", rm.name, depName)
+			errs = append(errs, fmt.Errorf("dependency name %s.%s is not found", rm.name, depName))
+			continue
 		}
+		if err := checkNamedType(rm, depName, depField.field.Type(), provider); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		g.deferred = depField.lazy
 		g.addDependencyEdge(provider, rm)
+		g.deferred = false
+		g.addInstanceDependency(rm, depName)
+	}
+	for _, depField := range rm.listDepends {
+		for _, depName := range depField.names {
+			provider, ok := nameToProviderMap[depName]
+			if !ok {
+				errs = append(errs, fmt.Errorf("dependency name %s.%s is not found", rm.name, depName))
+				continue
+			}
+			if !depField.computed() {
+				if err := checkNamedType(rm, depName, depField.field.Type().Elem(), provider); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+			}
+			g.addDependencyEdge(provider, rm)
+			g.addInstanceDependency(rm, depName)
+		}
 	}
 
-	return nil
+	return joinErrors(errs)
+}
+
+// checkNamedType checks if the instance with the specified name, declared by the provider, could be assigned to the
+// expected type. An instance declared by an interface type is checked when it is injected instead, as its dynamic type
+// is unknown until it is constructed.
+func checkNamedType(rm *reflectedModule, name string, expected reflect.Type, provider *reflectedModule) error {
+	instance := findInstanceMethod(provider.instances, name)
+	if instance == nil || instance.tp.Kind() == reflect.Interface || instance.tp.AssignableTo(expected) {
+		return nil
+	}
+	return fmt.Errorf("dependency name %s.%s expects type %s, but instance %s.%s has type %s", rm.name, name,
+		expected, provider.name, name, instance.tp)
+}
+
+// namedValue returns the reflect.Value of an instance associated by name to be assigned to type t. It panics with an
+// error naming both types if the instance is not assignable.
+func namedValue(consumer string, name string, instance interface{}, t reflect.Type) reflect.Value {
+	if instance != nil && !reflect.TypeOf(instance).AssignableTo(t) {
+		panic(fmt.Errorf("dependency name %s.%s expects type %s, but instance has type %T", consumer, name, t,
+			instance))
+	}
+	return instanceValue(instance, t)
 }
 
 // createDependenciesByTypes creates dependencies of a module using its typed dependencies.
 func (g *graph) createDependenciesByTypes(
 	rm *reflectedModule, typeToProvidersMap map[reflect.Type][]*reflectedModule) error {
+	var errs []error
 	for _, depField := range rm.typedDepends {
-		depType := depField.tp
-		providers, ok := typeToProvidersMap[depType]
-		if !ok { // no exact type match, find assignable types
-			assignableProviders, err := g.findAssignableProviders(rm, depType, typeToProvidersMap)
-			if err != nil {
-				return err
+		g.deferred = depField.lazy
+		err := g.createDependencyByType(rm, depField.tp, typeToProvidersMap)
+		g.deferred = false
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, instance := range rm.instances {
+		for _, param := range instance.params {
+			if err := g.createDependencyByType(rm, param, typeToProvidersMap); err != nil {
+				errs = append(errs, fmt.Errorf("parameter of %s.%s: %s", rm.name, instance.name, err.Error()))
 			}
-			providers = assignableProviders
 		}
+	}
 
-		if len(providers) == 0 {
-			return fmt.Errorf("dependency type %s.%s is not found", rm.name, depType.Name())
-		}
-		if len(providers) > 1 {
-			var names []string
-			for _, p := range providers {
-				names = append(names, p.name)
+	return joinErrors(errs)
+}
+
+// createDependencyByType creates the dependency of a module on the provider of a type.
+func (g *graph) createDependencyByType(
+	rm *reflectedModule, depType reflect.Type, typeToProvidersMap map[reflect.Type][]*reflectedModule) error {
+	if isContainerProvided(depType) {
+		return nil
+	}
+	if winner, ok := g.typeWinners[depType]; ok {
+		for _, provider := range g.modules {
+			if findInstanceMethod(provider.instances, winner) != nil {
+				g.addDependencyEdge(provider, rm)
+				g.addInstanceDependency(rm, winner)
+				return nil
 			}
-			return fmt.Errorf("dependency type %s.%s is found in mutiple modules: %s",
-				rm.name, depType.Name(), names)
 		}
-		g.addDependencyEdge(providers[0], rm)
+	}
+	if a, ok := g.adapters[depType]; ok && !providesType(typeToProvidersMap, depType, g.options.strict) {
+		return g.createAdaptedDependency(rm, depType, a, typeToProvidersMap)
+	}
+	providers, ok := typeToProvidersMap[depType]
+	if !ok && g.options.strict {
+		return fmt.Errorf("dependency type %s.%s is not provided explicitly in strict mode",
+			rm.name, depType.Name())
+	}
+	if !ok { // no exact type match, find assignable types
+		if resolved, err := g.resolveAssignableConflict(depType); err != nil {
+			return err
+		} else if resolved {
+			return g.createDependencyByType(rm, depType, typeToProvidersMap)
+		}
+		assignableProviders, err := g.findAssignableProviders(rm, depType, typeToProvidersMap)
+		if err != nil {
+			return err
+		}
+		providers = assignableProviders
+	}
+
+	if len(providers) == 0 {
+		return fmt.Errorf("dependency type %s.%s is not found", rm.name, depType.Name())
+	}
+	if g.options.strict {
+		if err := g.checkInterfaceAmbiguity(rm, depType, providers); err != nil {
+			return err
+		}
+	}
+	if len(providers) > 1 {
+		var names []string
+		for _, p := range providers {
+			names = append(names, p.name)
+		}
+		return fmt.Errorf("dependency type %s.%s is found in mutiple modules: %s",
+			rm.name, depType.Name(), names)
+	}
+	g.addDependencyEdge(providers[0], rm)
+	for _, instance := range providers[0].instances {
+		if instance.tp == depType || (!ok && instance.tp.AssignableTo(depType)) {
+			g.addInstanceDependency(rm, instance.name)
+		}
 	}
 
 	return nil
@@ -204,16 +513,32 @@ func (g *graph) findAssignableProviders(
 	return providers, nil
 }
 
-// addDependencyEdge creates a dependency edge in the graph. dependant depends on parent.
+// addDependencyEdge creates a dependency edge in the graph. dependant depends on parent. The edge is recorded in
+// lazyEdges if the dependency is deferred.
 func (g *graph) addDependencyEdge(parent *reflectedModule, dependant *reflectedModule) {
-	dependants, ok := g.g[parent]
+	edges := g.g
+	if g.deferred {
+		edges = g.lazyEdges
+	}
+	dependants, ok := edges[parent]
 	if !ok {
 		dependants = make(map[*reflectedModule]bool)
-		g.g[parent] = dependants
+		edges[parent] = dependants
 	}
 	dependants[dependant] = true
 }
 
+// addInstanceDependency records that a module depends on an instance.
+func (g *graph) addInstanceDependency(rm *reflectedModule, name string) {
+	g.depended[name] = true
+	names, ok := g.dependsOn[rm]
+	if !ok {
+		names = make(map[string]bool)
+		g.dependsOn[rm] = names
+	}
+	names[name] = true
+}
+
 // reverseSlice reverses the slice of Modules.
 func (g *graph) reverseSlice(l []*reflectedModule) []*reflectedModule {
 	for i, j := 0, len(l)-1; i < j; i, j = i+1, j-1 {
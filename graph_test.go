@@ -2,6 +2,7 @@ package alice
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -147,7 +148,10 @@ func TestInstantiationOrder_Cycle(t *testing.T) {
 
 	_, err = g.instantiationOrder()
 	if err == nil {
-		t.Error("expected error after instantiationOrder() with cycle")
+		t.Fatal("expected error after instantiationOrder() with cycle")
+	}
+	if !strings.Contains(err.Error(), "suggestion: module") || strings.Contains(err.Error(), "D3, D4") {
+		t.Errorf("bad suggestion after instantiationOrder() with cycle: got %s", err.Error())
 	}
 	t.Log(err.Error())
 }
@@ -162,7 +166,97 @@ func TestInstantiationOrder_CycleSingleModule(t *testing.T) {
 
 	_, err = g.instantiationOrder()
 	if err == nil {
-		t.Error("expected error after instantiationOrder() with single module cycle")
+		t.Fatal("expected error after instantiationOrder() with single module cycle")
+	}
+	if !strings.Contains(err.Error(), "Move them into a separate module") {
+		t.Errorf("bad suggestion after instantiationOrder() with single module cycle: got %s", err.Error())
 	}
 	t.Log(err.Error())
 }
+
+func TestConstructGraph_Strict(t *testing.T) {
+	var (
+		rm1, _ = reflectModule(&M1{})
+		rm4, _ = reflectModule(&M4{})
+		rm2, _ = reflectModule(&M2{})
+	)
+	if _, err := createGraphWithOptions(newOptions(WithStrict()), rm1, rm4, rm2); err != nil {
+		t.Errorf("unexpected error after createGraphWithOptions() with exact types: %s", err.Error())
+	}
+
+	var (
+		m3, _  = reflectModule(&M3{})
+		m2, _  = reflectModule(&ModuleWithD5Impl1{})
+		opts   = newOptions(WithStrict())
+		_, err = createGraphWithOptions(opts, m3, m2)
+	)
+	if err == nil {
+		t.Error("expect error after createGraphWithOptions() of assignable type in strict mode")
+	}
+	t.Log(err.Error())
+}
+
+func TestConstructGraph_MethodParams(t *testing.T) {
+	var (
+		rm1, _ = reflectModule(&M1{})
+		rm4, _ = reflectModule(&M4{})
+		rmp, _ = reflectModule(&ParamModule{})
+	)
+	g, err := createGraph(rm1, rm4, rmp)
+	if err != nil {
+		t.Errorf("unexpected error after createGraph(): %s", err.Error())
+	}
+	if !g.g[rm1][rmp] || !g.g[rm4][rmp] {
+		t.Errorf("bad g in graph: got %v, expected edges to module with method parameters", g.g)
+	}
+
+	if _, err := createGraph(rm1, rmp); err == nil {
+		t.Error("expect error after createGraph() of parameter type not found")
+	} else {
+		t.Log(err.Error())
+	}
+}
+
+type PingModule struct {
+	BaseModule
+	Pong func() string `alice:"Pong"`
+}
+
+func (m *PingModule) Ping() func() string {
+	return func() string { return "ping" }
+}
+
+type PongModule struct {
+	BaseModule
+	Ping func() string `alice:"Ping"`
+}
+
+func (m *PongModule) Pong() func() string {
+	return func() string { return m.Ping() + "-pong" }
+}
+
+type LazyPingModule struct {
+	BaseModule
+	Pong func() string `alice:"Pong,lazy"`
+}
+
+func (m *LazyPingModule) Ping() func() string {
+	return func() string { return "ping" }
+}
+
+func TestInstantiationOrder_CycleLazySuggestion(t *testing.T) {
+	_, err := Plan([]Module{&PingModule{}, &PongModule{}})
+	if err == nil || !strings.Contains(err.Error(), "`alice:\"Ping,lazy\"`") &&
+		!strings.Contains(err.Error(), "`alice:\"Pong,lazy\"`") {
+		t.Fatalf("bad suggestion after Plan() with cycle: got %v", err)
+	}
+
+	ping := &LazyPingModule{}
+	c := CreateContainer(ping, &PongModule{})
+	if s := c.InstanceByName("Pong").(func() string)(); s != "ping-pong" {
+		t.Errorf("bad instance after breaking the cycle lazily: got %q, expected %q", s, "ping-pong")
+	}
+	if s := ping.Pong(); s != "ping-pong" {
+		t.Errorf("bad lazy dependency after breaking the cycle: got %q, expected %q", s, "ping-pong")
+	}
+}
@@ -0,0 +1,167 @@
+package alice
+
+import (
+	"testing"
+)
+
+type Middleware interface {
+	Name() string
+}
+
+type namedMiddleware string
+
+func (m namedMiddleware) Name() string {
+	return string(m)
+}
+
+type MiddlewareModule1 struct {
+	BaseModule
+}
+
+func (m *MiddlewareModule1) Logging() Middleware {
+	return namedMiddleware("logging")
+}
+
+func (m *MiddlewareModule1) Metrics() Middleware {
+	return namedMiddleware("metrics")
+}
+
+func (m *MiddlewareModule1) Groups() map[string]Contribution {
+	return map[string]Contribution{
+		"Logging": {Group: "middleware", Priority: 10},
+		"Metrics": {Group: "middleware", Priority: 10},
+	}
+}
+
+type ServerModule struct {
+	BaseModule
+	Middlewares []Middleware `alice:"group=middleware"`
+	Handlers    []Middleware `alice:"group=handlers"`
+}
+
+type invalidGroupModule struct {
+	BaseModule
+}
+
+func (m *invalidGroupModule) Groups() map[string]Contribution {
+	return map[string]Contribution{
+		"Undefined": {Group: "middleware"},
+	}
+}
+
+func TestGroup(t *testing.T) {
+	auth := NewModule("auth").
+		Provide("Auth", func() Middleware { return namedMiddleware("auth") }).
+		Contribute("Auth", "middleware", 0).
+		Build()
+	server := &ServerModule{}
+	CreateContainer(&MiddlewareModule1{}, server, auth)
+
+	var names []string
+	for _, m := range server.Middlewares {
+		names = append(names, m.Name())
+	}
+	expected := []string{"auth", "logging", "metrics"}
+	if len(names) != len(expected) {
+		t.Fatalf("bad group after CreateContainer(): got %v, expected %v", names, expected)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("bad group after CreateContainer(): got %v, expected %v", names, expected)
+			break
+		}
+	}
+	if len(server.Handlers) != 0 {
+		t.Errorf("bad empty group after CreateContainer(): got %v, expected empty", server.Handlers)
+	}
+}
+
+func TestGroup_RequireGroup(t *testing.T) {
+	var middlewares []Middleware
+	consumer := NewModule("consumer").RequireGroup("middleware", &middlewares).Build()
+	c := CreateContainerWithOptions([]Module{&MiddlewareModule1{}, consumer}, WithNamespaces())
+	if len(middlewares) != 2 {
+		t.Errorf("bad group after CreateContainer(): got %v, expected 2 instances", middlewares)
+	}
+	if unused := c.(Introspector).Unused(); len(unused) != 0 {
+		t.Errorf("bad unused instances after CreateContainer(): got %v, expected empty", unused)
+	}
+}
+
+func TestGroup_Invalid(t *testing.T) {
+	if err := Validate(&invalidGroupModule{}); err == nil {
+		t.Error("expected error for undefined contributed instance")
+	}
+	if err := Validate(NewModule("built").Contribute("Undefined", "middleware", 0).Build()); err == nil {
+		t.Error("expected error for undefined contributed instance of built module")
+	}
+}
+
+type RouterModule struct {
+	BaseModule
+	Middlewares map[string]Middleware `alice:",map"`
+}
+
+type invalidMapModule struct {
+	BaseModule
+	Middlewares map[int]Middleware `alice:",map"`
+}
+
+func TestMap(t *testing.T) {
+	router := &RouterModule{}
+	CreateContainer(&MiddlewareModule1{}, router)
+	if len(router.Middlewares) != 2 || router.Middlewares["Logging"].Name() != "logging" ||
+		router.Middlewares["Metrics"].Name() != "metrics" {
+		t.Errorf("bad map after CreateContainer(): got %v", router.Middlewares)
+	}
+
+	var middlewares map[string]Middleware
+	consumer := NewModule("consumer").RequireMap(&middlewares).Build()
+	CreateContainerWithOptions([]Module{&MiddlewareModule1{}, consumer}, WithNamespaces())
+	if _, ok := middlewares["MiddlewareModule1.Logging"]; !ok || len(middlewares) != 2 {
+		t.Errorf("bad map of built module after CreateContainer(): got %v", middlewares)
+	}
+
+	if err := Validate(&invalidMapModule{}); err == nil {
+		t.Error("expected error for map not keyed by string")
+	}
+}
+
+type paymentRouterModule struct {
+	BaseModule
+	Processors map[string]Middleware `alice:"prefix=payment."`
+}
+
+type invalidPrefixModule struct {
+	BaseModule
+	Processors []Middleware `alice:"prefix=payment."`
+}
+
+func TestMap_Prefix(t *testing.T) {
+	plugins := NewModule("plugins").
+		Provide("payment.stripe", func() Middleware { return namedMiddleware("stripe") }).
+		Provide("payment.paypal", func() Middleware { return namedMiddleware("paypal") }).
+		Provide("shipping.ups", func() Middleware { return namedMiddleware("ups") }).
+		Provide("payment.config", func() *D5Impl { return &D5Impl{} }).
+		Build()
+	router := &paymentRouterModule{}
+	CreateContainer(plugins, router)
+	if len(router.Processors) != 2 || router.Processors["stripe"].Name() != "stripe" ||
+		router.Processors["paypal"].Name() != "paypal" {
+		t.Errorf("bad map after CreateContainer(): got %v", router.Processors)
+	}
+
+	var middlewares map[string]Middleware
+	consumer := NewModule("consumer").RequirePrefix("MiddlewareModule1.", &middlewares).Build()
+	CreateContainerWithOptions([]Module{&MiddlewareModule1{}, plugins, consumer}, WithNamespaces())
+	if _, ok := middlewares["Logging"]; !ok || len(middlewares) != 2 {
+		t.Errorf("bad map of built module after CreateContainer(): got %v", middlewares)
+	}
+
+	if err := Validate(&invalidPrefixModule{}); err == nil {
+		t.Error("expected error for prefix field not of map")
+	}
+	if err := Validate(NewModule("consumer").RequirePrefix("", &middlewares).Build()); err == nil {
+		t.Error("expected error for empty prefix")
+	}
+}
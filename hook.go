@@ -0,0 +1,100 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// InjectionSite describes where a dependency is injected.
+type InjectionSite struct {
+	// Consumer is the module depending on the instance.
+	Consumer ModuleInfo
+	// Instance is the name of the instance method taking the dependency as a parameter, or empty for a module field.
+	Instance string
+	// Field is the name of the module field or the parameters struct field, or empty for a positional parameter or a
+	// dependency of a built module.
+	Field string
+	// Name is the instance name if the dependency is associated by name, or empty if associated by type.
+	Name string
+	// Type is the type of the field or parameter.
+	Type reflect.Type
+}
+
+// InjectionHook adapts a dependency per injection site, such as selecting a metrics namespace or a database schema
+// per consumer. It returns the instance to inject, which must be assignable to the site type.
+type InjectionHook func(site InjectionSite, instance interface{}) interface{}
+
+// WithInjectionHooks returns an option which invokes the hooks, in order, at each injection site of module fields
+// and instance method parameters. Instances retrieved from the container directly are not affected.
+func WithInjectionHooks(hooks ...InjectionHook) Option {
+	return func(o *options) {
+		o.injectionHooks = append(o.injectionHooks, hooks...)
+	}
+}
+
+// PostProcessor adapts every constructed instance before it is registered in the container, e.g. to wrap all
+// http.Handler instances with panic recovery in one place. It returns the instance to register, which must be
+// assignable to the type declared by the instance method.
+type PostProcessor func(name string, instance interface{}) interface{}
+
+// WithPostProcessors returns an option which invokes the post-processors, in order, on each instance returned by its
+// instance method. The substitutes of degraded instances are not post-processed.
+func WithPostProcessors(processors ...PostProcessor) Option {
+	return func(o *options) {
+		o.postProcessors = append(o.postProcessors, processors...)
+	}
+}
+
+// postProcess invokes the post-processors on a constructed instance. It panics if the result is not assignable to the
+// instance type.
+func (c *container) postProcess(im *instanceMethod, instance interface{}) interface{} {
+	for _, process := range c.options.postProcessors {
+		instance = process(im.name, instance)
+		if instance == nil {
+			continue
+		}
+		if t := reflect.TypeOf(instance); !t.AssignableTo(im.tp) {
+			panic(fmt.Errorf("post-processed instance %s of type %s is not assignable to %s", im.name, t, im.tp))
+		}
+	}
+	return instance
+}
+
+// hookInjection invokes the injection hooks for an instance injected at the site. site is only called if there is
+// any hook.
+func (c *container) hookInjection(instance interface{}, site func() InjectionSite) interface{} {
+	if len(c.options.injectionHooks) == 0 {
+		return instance
+	}
+	s := site()
+	for _, hook := range c.options.injectionHooks {
+		instance = hook(s, instance)
+	}
+	return instance
+}
+
+// fieldSite returns a function creating the injection site of a module field.
+func fieldSite(rm *reflectedModule, field string, name string, t reflect.Type) func() InjectionSite {
+	return func() InjectionSite {
+		return InjectionSite{
+			Consumer: newModuleInfo(rm),
+			Field:    field,
+			Name:     name,
+			Type:     t,
+		}
+	}
+}
+
+// paramSite returns a function creating the injection site of an instance method parameter.
+func paramSite(consumer func() *reflectedModule, im *instanceMethod, field string, name string,
+	t reflect.Type) func() InjectionSite {
+	return func() InjectionSite {
+		return InjectionSite{
+			Consumer: newModuleInfo(consumer()),
+			Instance: im.name,
+			Field:    field,
+			Name:     name,
+			Type:     t,
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+type HookedModule struct {
+	BaseModule
+	Named D1 `alice:"D1"`
+	Typed D2 `alice:""`
+}
+
+func (m *HookedModule) D5(d1 D1) *D5Impl {
+	return &D5Impl{}
+}
+
+func TestInjectionHooks(t *testing.T) {
+	var sites []InjectionSite
+	adapted := &countedD1{n: 1}
+	hook := func(site InjectionSite, instance interface{}) interface{} {
+		sites = append(sites, site)
+		if site.Name == "D1" {
+			return adapted
+		}
+		return instance
+	}
+	m := &HookedModule{}
+	c := CreateContainerWithOptions([]Module{&M1{}, m}, WithInjectionHooks(hook))
+
+	if m.Named != adapted {
+		t.Errorf("bad field adapted by hook: got %v, expected %v", m.Named, adapted)
+	}
+	if m.Typed != c.InstanceByName("D2") {
+		t.Errorf("bad field not adapted by hook: got %v, expected %v", m.Typed, c.InstanceByName("D2"))
+	}
+	if instance := c.InstanceByName("D1"); instance == adapted {
+		t.Error("instance retrieved from the container is not expected to be adapted")
+	}
+
+	info := ModuleInfo{Name: "HookedModule", Instances: []string{"D5"}}
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	expected := []InjectionSite{
+		{Consumer: info, Field: "Named", Name: "D1", Type: d1Type},
+		{Consumer: info, Field: "Typed", Type: reflect.TypeOf((*D2)(nil)).Elem()},
+		{Consumer: info, Instance: "D5", Type: d1Type},
+	}
+	if !reflect.DeepEqual(sites, expected) {
+		t.Errorf("bad injection sites: got %v, expected %v", sites, expected)
+	}
+}
+
+func TestPostProcessors(t *testing.T) {
+	var names []string
+	wrapped := &countedD1{n: 1}
+	record := func(name string, instance interface{}) interface{} {
+		names = append(names, name)
+		return instance
+	}
+	wrap := func(name string, instance interface{}) interface{} {
+		if _, ok := instance.(D1); ok {
+			return wrapped
+		}
+		return instance
+	}
+	m4 := &M4{}
+	c := CreateContainerWithOptions([]Module{&M1{}, m4}, WithPostProcessors(record, wrap))
+
+	expected := []string{"D1", "D2", "D3", "D4"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("bad post-processed names: got %v, expected %v", names, expected)
+	}
+	if instance := c.InstanceByName("D1"); instance != wrapped {
+		t.Errorf("bad instance after InstanceByName(): got %v, expected %v", instance, wrapped)
+	}
+	if m4.D1 != wrapped {
+		t.Errorf("bad injected field: got %v, expected %v", m4.D1, wrapped)
+	}
+}
+
+func TestPostProcessors_NotAssignable(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Error("expected panic for a post-processed instance not assignable to the instance type")
+		}
+		t.Log(r)
+	}()
+	CreateContainerWithOptions([]Module{&M1{}}, WithPostProcessors(func(name string, instance interface{}) interface{} {
+		return "not an instance"
+	}))
+}
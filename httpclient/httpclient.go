@@ -0,0 +1,83 @@
+// Package httpclient assembles the outbound *http.Client of alice containers from middlewares contributed by feature
+// modules, centralizing the retry, auth and tracing layers of outbound HTTP calls in one place:
+//
+//	func (m *AuthModule) Groups() map[string]alice.Contribution {
+//		return map[string]alice.Contribution{"AuthMiddleware": {Group: httpclient.MiddlewareGroup, Priority: 10}}
+//	}
+//
+//	func (m *AuthModule) AuthMiddleware() httpclient.Middleware {
+//		return func(next http.RoundTripper) http.RoundTripper {
+//			return httpclient.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+//				req = req.Clone(req.Context())
+//				req.Header.Set("Authorization", "Bearer "+m.Token)
+//				return next.RoundTrip(req)
+//			})
+//		}
+//	}
+//
+//	c := alice.CreateContainer(httpclient.NewModule(), &AuthModule{}, &TracingModule{})
+package httpclient
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/magic003/alice"
+)
+
+// MiddlewareGroup is the group the middlewares of the client are contributed to.
+const MiddlewareGroup = "HTTPClientMiddleware"
+
+// Middleware wraps the round tripper of the next layer, e.g. to retry, authenticate or trace requests.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to allow the use of an ordinary function as http.RoundTripper.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Module provides the "HTTPClient" instance of type *http.Client, whose transport is wrapped by the middlewares
+// contributed to MiddlewareGroup by other modules. The middlewares are applied in order of priority, so the one of
+// the lowest priority is the outermost, and sees a request first.
+type Module struct {
+	alice.BaseModule
+	Middlewares []Middleware `alice:"group=HTTPClientMiddleware"`
+}
+
+// NewModule creates the client module.
+func NewModule() *Module {
+	return &Module{}
+}
+
+// ClientParams are the optional dependencies of the client.
+type ClientParams struct {
+	alice.Params
+	// Transport is the innermost round tripper. The default is http.DefaultTransport.
+	Transport http.RoundTripper `alice:"HTTPTransport,optional"`
+	// Timeout is the timeout of the requests. The default is no timeout.
+	Timeout time.Duration `alice:"HTTPClientTimeout,optional"`
+}
+
+// HTTPClient returns the client with the middlewares applied.
+func (m *Module) HTTPClient(p ClientParams) *http.Client {
+	return &http.Client{
+		Transport: Chain(p.Transport, m.Middlewares...),
+		Timeout:   p.Timeout,
+	}
+}
+
+// Chain wraps the transport by the middlewares, the first one outermost. A nil transport is http.DefaultTransport.
+func Chain(transport http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if middlewares[i] != nil {
+			transport = middlewares[i](transport)
+		}
+	}
+	return transport
+}
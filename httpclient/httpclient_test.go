@@ -0,0 +1,89 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/magic003/alice"
+)
+
+// recordingTransport records the layers a request passed through, and responds without network.
+type recordingTransport struct {
+	layers *[]string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	*t.layers = append(*t.layers, "transport "+req.Header.Get("Authorization"))
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok")), Request: req}, nil
+}
+
+func layer(name string, layers *[]string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			*layers = append(*layers, name)
+			if name == "auth" {
+				req = req.Clone(req.Context())
+				req.Header.Set("Authorization", "token")
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+type layersModule struct {
+	alice.BaseModule
+	layers *[]string
+}
+
+func (m *layersModule) Groups() map[string]alice.Contribution {
+	return map[string]alice.Contribution{
+		"Auth":    {Group: MiddlewareGroup, Priority: 10},
+		"Tracing": {Group: MiddlewareGroup, Priority: 0},
+	}
+}
+
+func (m *layersModule) Auth() Middleware {
+	return layer("auth", m.layers)
+}
+
+func (m *layersModule) Tracing() Middleware {
+	return layer("tracing", m.layers)
+}
+
+func (m *layersModule) HTTPTransport() http.RoundTripper {
+	return &recordingTransport{layers: m.layers}
+}
+
+func (m *layersModule) HTTPClientTimeout() time.Duration {
+	return time.Second
+}
+
+func TestModule(t *testing.T) {
+	var layers []string
+	c := alice.CreateContainer(NewModule(), &layersModule{layers: &layers})
+	client := c.InstanceByName("HTTPClient").(*http.Client)
+	if client.Timeout != time.Second {
+		t.Errorf("bad timeout of HTTPClient: got %v, expected %v", client.Timeout, time.Second)
+	}
+
+	resp, err := client.Get("http://example.com/")
+	if err != nil {
+		t.Fatalf("bad error after Get(): got %v, expected nil", err)
+	}
+	resp.Body.Close()
+	if expected := []string{"tracing", "auth", "transport token"}; !reflect.DeepEqual(layers, expected) {
+		t.Errorf("bad layers after Get(): got %v, expected %v", layers, expected)
+	}
+}
+
+func TestModule_Defaults(t *testing.T) {
+	c := alice.CreateContainer(NewModule())
+	client := c.InstanceByName("HTTPClient").(*http.Client)
+	if client.Transport != http.DefaultTransport || client.Timeout != 0 {
+		t.Errorf("bad HTTPClient without dependencies: got %v and %v", client.Transport, client.Timeout)
+	}
+}
@@ -0,0 +1,55 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Import creates a module providing the instances with the specified names from another container, which must have
+// been created. It allows containers built per domain to share a few infrastructure instances in a controlled way.
+// Modules could depend on the imported instances by name or by type like any other instance, and names not defined
+// in the other container are reported when the module is used to create a container. The imported instances are
+// started and stopped by the other container only.
+//
+//	infra := alice.CreateContainer(&DatabaseModule{})
+//	orders := alice.CreateContainer(alice.Import(infra, "DB"), &OrderModule{})
+func Import(other Container, names ...string) Module {
+	b := NewModule("import")
+
+	for _, name := range names {
+		tp, ok := importedType(other, name)
+		if !ok {
+			b.setError(fmt.Errorf("imported instance %s is not defined in the other container", name))
+			continue
+		}
+		instanceName := name
+		constructor := reflect.MakeFunc(
+			reflect.FuncOf(nil, []reflect.Type{tp}, false),
+			func([]reflect.Value) []reflect.Value {
+				return []reflect.Value{instanceValue(other.MustInstanceByName(instanceName), tp)}
+			})
+		// nil instances are checked by the other container
+		b.Provide(name, constructor.Interface()).AllowNil(name)
+	}
+
+	b.m.imported = true
+	return b.Build()
+}
+
+// importedType returns the type of an instance of the other container. Without the Introspector extension, the
+// instance is retrieved to figure out its dynamic type.
+func importedType(other Container, name string) (reflect.Type, bool) {
+	if in, ok := other.(Introspector); ok {
+		for _, info := range in.Instances() {
+			if info.Name == name {
+				return info.Type, true
+			}
+		}
+		return nil, false
+	}
+	instance, err := other.ResolveByName(name)
+	if err != nil || instance == nil {
+		return nil, false
+	}
+	return reflect.TypeOf(instance), true
+}
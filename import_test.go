@@ -0,0 +1,55 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	shared := &countedD1{}
+	infra := CreateContainer(NewModule("infra").
+		Provide("D1", func() D1 { return shared }).
+		Provide("D2", func() D2 { return &D2Impl{} }).
+		Build())
+
+	var d1 D1
+	consumer := NewModule("consumer").
+		Require(&d1).
+		Provide("D3", func() D3 { return &D3Impl{} }).
+		Build()
+	c := CreateContainer(Import(infra, "D1"), consumer)
+
+	if d1 != shared {
+		t.Errorf("bad imported instance after CreateContainer(): got %v, expected %v", d1, shared)
+	}
+	if instance := c.InstanceByName("D1"); instance != shared {
+		t.Errorf("bad instance after InstanceByName(): got %v, expected %v", instance, shared)
+	}
+	if instance := c.Instance(reflect.TypeOf((*D1)(nil)).Elem()); instance != shared {
+		t.Errorf("bad instance after Instance(): got %v, expected %v", instance, shared)
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Error("expected panic for not imported instance")
+		}
+		t.Log(r)
+	}()
+	c.InstanceByName("D2")
+}
+
+func TestImport_Undefined(t *testing.T) {
+	infra := CreateContainer(&M1{})
+	if err := Validate(Import(infra, "Undefined")); err == nil {
+		t.Error("expected error for undefined imported instance")
+	}
+}
+
+func TestImport_Nilable(t *testing.T) {
+	infra := CreateContainer(&nilableModule{})
+	c := CreateContainer(Import(infra, "D1", "D5"))
+	if d1 := c.InstanceByName("D1"); d1 != nil {
+		t.Errorf("bad imported instance after CreateContainer(): got %v, expected nil", d1)
+	}
+}
@@ -0,0 +1,92 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// interfaceIndex maps the interface types known from the graph to the names of the constructed instances assignable
+// to them, so retrieving an instance by such an interface doesn't scan all instances. The known interfaces are the
+// ones declared by instance methods or depended on by type.
+type interfaceIndex map[reflect.Type][]string
+
+// newInterfaceIndex creates an index of the interfaces known from the modules, with no instance yet.
+func newInterfaceIndex(rms []*reflectedModule) interfaceIndex {
+	index := make(interfaceIndex)
+	add := func(t reflect.Type) {
+		if t.Kind() == reflect.Interface && !isContainerProvided(t) {
+			index[t] = nil
+		}
+	}
+	for _, rm := range rms {
+		for _, dep := range rm.typedDepends {
+			add(dep.tp)
+		}
+		for _, instance := range rm.instances {
+			add(instance.tp)
+			for _, param := range instance.params {
+				add(param)
+			}
+			if instance.paramsStruct != nil {
+				for _, field := range instance.paramsStruct.fields {
+					if field.name == "" {
+						add(field.tp)
+					}
+				}
+			}
+		}
+	}
+	return index
+}
+
+// add records a constructed instance under the interfaces its concrete type implements.
+func (index interfaceIndex) add(name string, instance interface{}) {
+	if instance == nil {
+		return
+	}
+	instanceType := reflect.TypeOf(instance)
+	for t, names := range index {
+		if instanceType.Implements(t) {
+			index[t] = append(names, name)
+		}
+	}
+}
+
+// remove removes the instances from the index.
+func (index interfaceIndex) remove(removed map[string]bool) {
+	for t, names := range index {
+		var kept []string
+		for _, name := range names {
+			if !removed[name] {
+				kept = append(kept, name)
+			}
+		}
+		index[t] = kept
+	}
+}
+
+// checkInterfaceAmbiguity reports a dependency on an interface type declared by more than one instance in strict
+// mode, as retrieving it by type would always fail. The types picked by the conflict policy never get here.
+func (g *graph) checkInterfaceAmbiguity(rm *reflectedModule, depType reflect.Type, providers []*reflectedModule) error {
+	if depType.Kind() != reflect.Interface {
+		return nil
+	}
+	var names []string
+	visited := make(map[*reflectedModule]bool)
+	for _, provider := range providers {
+		if visited[provider] {
+			continue
+		}
+		visited[provider] = true
+		for _, instance := range provider.instances {
+			if instance.tp == depType {
+				names = append(names, provider.name+"."+instance.name)
+			}
+		}
+	}
+	if len(names) < 2 {
+		return nil
+	}
+	return fmt.Errorf("dependency type %s.%s is declared by multiple instances in strict mode: %v",
+		rm.name, depType.Name(), names)
+}
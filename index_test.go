@@ -0,0 +1,85 @@
+package alice
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type indexedModule struct {
+	BaseModule
+}
+
+func (m *indexedModule) Counted() *countedD1 {
+	return &countedD1{n: 1}
+}
+
+func (m *indexedModule) D2() D2 {
+	return &D2Impl{}
+}
+
+type indexConsumerModule struct {
+	BaseModule
+	D1 D1 `alice:""`
+}
+
+func TestInterfaceIndex(t *testing.T) {
+	c := CreateContainer(&indexedModule{}, &indexConsumerModule{})
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	d2Type := reflect.TypeOf((*D2)(nil)).Elem()
+	index := c.(*container).interfaces
+	if names := index[d1Type]; !reflect.DeepEqual(names, []string{"Counted"}) {
+		t.Errorf("bad names indexed for D1 after CreateContainer(): got %v, expected [Counted]", names)
+	}
+	if names := index[d2Type]; !reflect.DeepEqual(names, []string{"D2"}) {
+		t.Errorf("bad names indexed for D2 after CreateContainer(): got %v, expected [D2]", names)
+	}
+	if instance := c.Instance(d1Type); instance != c.InstanceByName("Counted") {
+		t.Errorf("bad instance by type D1: got %v, expected %v", instance, c.InstanceByName("Counted"))
+	}
+
+	c.(Rebuilder).Reset("Counted")
+	if names := index[d1Type]; !reflect.DeepEqual(names, []string{"Counted"}) {
+		t.Errorf("bad names indexed for D1 after Reset(): got %v, expected [Counted]", names)
+	}
+	if instance := c.Instance(d1Type); instance != c.InstanceByName("Counted") {
+		t.Errorf("bad instance by type D1 after Reset(): got %v, expected %v", instance, c.InstanceByName("Counted"))
+	}
+}
+
+type ambiguousInterfaceModule struct {
+	BaseModule
+}
+
+func (m *ambiguousInterfaceModule) Primary() D1 {
+	return &D1Impl{}
+}
+
+func (m *ambiguousInterfaceModule) Secondary() D1 {
+	return &D1Impl{}
+}
+
+type ambiguousInterfaceConsumer struct {
+	BaseModule
+	D1 D1 `alice:""`
+}
+
+func TestInterfaceIndex_StrictAmbiguity(t *testing.T) {
+	CreateContainer(&ambiguousInterfaceModule{})
+	var primary D1
+	byName := NewModule("byName").RequireNamed("Primary", &primary).Build()
+	CreateContainerWithOptions([]Module{&ambiguousInterfaceModule{}, byName}, WithStrict())
+	if primary == nil {
+		t.Error("bad dependency by name in strict mode: got nil, expected Primary")
+	}
+
+	defer func() {
+		err, ok := recover().(error)
+		expected := "dependency type ambiguousInterfaceConsumer.D1 is declared by multiple instances in strict mode: " +
+			"[ambiguousInterfaceModule.Primary ambiguousInterfaceModule.Secondary]"
+		if !ok || !strings.Contains(err.Error(), expected) {
+			t.Errorf("bad panic from CreateContainerWithOptions(): got %v, expected %s", err, expected)
+		}
+	}()
+	CreateContainerWithOptions([]Module{&ambiguousInterfaceModule{}, &ambiguousInterfaceConsumer{}}, WithStrict())
+}
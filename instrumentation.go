@@ -0,0 +1,91 @@
+package alice
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Instrumentation observes the instance retrievals of a container, including the ones made internally while
+// injecting dependencies. It is called on the retrieval path, so implementations should be cheap and must be safe for
+// concurrent use.
+type Instrumentation interface {
+	// ObserveByName is called after an instance is retrieved by name.
+	ObserveByName(name string, elapsed time.Duration)
+	// ObserveByType is called after an instance is retrieved by type.
+	ObserveByType(t reflect.Type, elapsed time.Duration)
+}
+
+// WithInstrumentation returns an option which sets the instrumentation of instance retrievals.
+func WithInstrumentation(instrumentation Instrumentation) Option {
+	return func(o *options) {
+		o.instrumentation = instrumentation
+	}
+}
+
+// ResolutionStat is the statistics of retrievals for a name or type.
+type ResolutionStat struct {
+	Count int64
+	Total time.Duration
+	Max   time.Duration
+}
+
+// ResolutionStats is an Instrumentation which records call counts and latencies of instance retrievals.
+type ResolutionStats struct {
+	mu     sync.Mutex
+	byName map[string]ResolutionStat
+	byType map[reflect.Type]ResolutionStat
+}
+
+// NewResolutionStats creates an empty ResolutionStats.
+func NewResolutionStats() *ResolutionStats {
+	return &ResolutionStats{
+		byName: make(map[string]ResolutionStat),
+		byType: make(map[reflect.Type]ResolutionStat),
+	}
+}
+
+// ObserveByName records a retrieval by name.
+func (s *ResolutionStats) ObserveByName(name string, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byName[name] = s.byName[name].add(elapsed)
+}
+
+// ObserveByType records a retrieval by type.
+func (s *ResolutionStats) ObserveByType(t reflect.Type, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.byType[t] = s.byType[t].add(elapsed)
+}
+
+// ByName returns a copy of the statistics of retrievals by name.
+func (s *ResolutionStats) ByName() map[string]ResolutionStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make(map[string]ResolutionStat, len(s.byName))
+	for name, stat := range s.byName {
+		stats[name] = stat
+	}
+	return stats
+}
+
+// ByType returns a copy of the statistics of retrievals by type.
+func (s *ResolutionStats) ByType() map[reflect.Type]ResolutionStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make(map[reflect.Type]ResolutionStat, len(s.byType))
+	for t, stat := range s.byType {
+		stats[t] = stat
+	}
+	return stats
+}
+
+func (s ResolutionStat) add(elapsed time.Duration) ResolutionStat {
+	s.Count++
+	s.Total += elapsed
+	if elapsed > s.Max {
+		s.Max = elapsed
+	}
+	return s
+}
@@ -0,0 +1,29 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithInstrumentation(t *testing.T) {
+	stats := NewResolutionStats()
+	c := CreateContainerWithOptions([]Module{&M1{}, &M4{}}, WithInstrumentation(stats))
+
+	c.InstanceByName("D1")
+	c.InstanceByName("D1")
+	d3Type := reflect.TypeOf((*D3)(nil)).Elem()
+	c.Instance(d3Type)
+
+	byName := stats.ByName()
+	// D1 is also retrieved once when injected into M4
+	if byName["D1"].Count != 3 {
+		t.Errorf("bad count of D1: got %d, expected %d", byName["D1"].Count, 3)
+	}
+	if byName["D1"].Max > byName["D1"].Total {
+		t.Errorf("bad latency of D1: max %s is larger than total %s", byName["D1"].Max, byName["D1"].Total)
+	}
+	byType := stats.ByType()
+	if byType[d3Type].Count != 1 {
+		t.Errorf("bad count of D3: got %d, expected %d", byType[d3Type].Count, 1)
+	}
+}
@@ -0,0 +1,135 @@
+package alice
+
+import (
+	"reflect"
+	"sort"
+)
+
+// InstanceInfo describes an instance managed by a container.
+type InstanceInfo struct {
+	// Name is the instance name.
+	Name string
+	// Type is the instance type declared by the instance method.
+	Type reflect.Type
+	// Module is the name of the module providing the instance.
+	Module string
+	// Description is the human-readable description of the instance, if any.
+	Description string
+	// Deprecated indicates the instance or its module is deprecated. Replacement is the hint of what to use instead.
+	Deprecated  bool
+	Replacement string
+	// Synchronized indicates the instance is wrapped in a proxy guarded by a mutex.
+	Synchronized bool
+	// State tells whether the instance is constructed. Instances could be pending in lazy mode or while they are
+	// constructed in background.
+	State InstanceState
+}
+
+// InstanceState is the construction state of an instance.
+type InstanceState int
+
+const (
+	// InstanceConstructed is the state of an instance which is constructed.
+	InstanceConstructed InstanceState = iota
+	// InstancePending is the state of an instance which is not constructed yet, or failed to be constructed.
+	InstancePending
+)
+
+// String returns "constructed" or "pending".
+func (s InstanceState) String() string {
+	if s == InstancePending {
+		return "pending"
+	}
+	return "constructed"
+}
+
+func (c *container) Instances() []InstanceInfo {
+	var infos []InstanceInfo
+	for _, rm := range c.reflected {
+		for _, instance := range rm.instances {
+			info := InstanceInfo{
+				Name:         instance.name,
+				Type:         instance.tp,
+				Module:       rm.name,
+				Description:  instance.description,
+				Deprecated:   instance.deprecated,
+				Replacement:  instance.replacement,
+				Synchronized: instance.synchronized,
+				State:        c.instanceState(instance.name),
+			}
+			if rm.deprecated && !instance.deprecated {
+				info.Deprecated = true
+				info.Replacement = rm.replacement
+			}
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
+func (c *container) Constructed() []string {
+	return c.instancesInState(InstanceConstructed)
+}
+
+func (c *container) Pending() []string {
+	return c.instancesInState(InstancePending)
+}
+
+// instancesInState returns the names of the instances in the state, in instantiation order.
+func (c *container) instancesInState(state InstanceState) []string {
+	names := []string{}
+	for _, rm := range c.reflected {
+		for _, instance := range rm.instances {
+			if c.instanceState(instance.name) == state {
+				names = append(names, instance.name)
+			}
+		}
+	}
+	return names
+}
+
+// instanceState returns the construction state of the instance with the name.
+func (c *container) instanceState(name string) InstanceState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.instanceByName[name]; ok {
+		return InstanceConstructed
+	}
+	return InstancePending
+}
+
+func (c *container) Modules() []ModuleInfo {
+	var infos []ModuleInfo
+	for _, rm := range c.reflected {
+		infos = append(infos, newModuleInfo(rm))
+	}
+	return infos
+}
+
+func (c *container) Unused() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	unused := []string{}
+	for _, rm := range c.reflected {
+		for _, instance := range rm.instances {
+			if instance.tp == _EffectType || c.graph.depended[instance.name] || c.retrievedByName[instance.name] ||
+				c.retrievedByTypeOf(instance.tp) {
+				continue
+			}
+			unused = append(unused, instance.name)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// retrievedByTypeOf checks if an instance of the specified type could have been retrieved by type.
+func (c *container) retrievedByTypeOf(t reflect.Type) bool {
+	for retrievedType := range c.retrievedByType {
+		if t == retrievedType || (!c.options.strict && t.AssignableTo(retrievedType)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+type DescribedModule1 struct {
+	BaseModule
+}
+
+func (m *DescribedModule1) D1() D1 {
+	return &D1Impl{}
+}
+
+func (m *DescribedModule1) D2() D2 {
+	return &D2Impl{}
+}
+
+func (m *DescribedModule1) Describe() map[string]string {
+	return map[string]string{
+		"D1": "the D1 implementation",
+	}
+}
+
+type invalidDescribedModule struct {
+	BaseModule
+}
+
+func (m *invalidDescribedModule) Describe() map[string]string {
+	return map[string]string{
+		"D1": "undefined instance",
+	}
+}
+
+func TestInstances(t *testing.T) {
+	built := NewModule("built").
+		Provide("D3", func() D3 { return &D3Impl{} }).
+		Describe("D3", "the D3 implementation").
+		Build()
+	c := CreateContainer(&DescribedModule1{}, built)
+
+	expected := []InstanceInfo{
+		{
+			Name:        "D1",
+			Type:        reflect.TypeOf((*D1)(nil)).Elem(),
+			Module:      "DescribedModule1",
+			Description: "the D1 implementation",
+		},
+		{
+			Name:   "D2",
+			Type:   reflect.TypeOf((*D2)(nil)).Elem(),
+			Module: "DescribedModule1",
+		},
+		{
+			Name:        "D3",
+			Type:        reflect.TypeOf((*D3)(nil)).Elem(),
+			Module:      "built",
+			Description: "the D3 implementation",
+		},
+	}
+	instances := c.(Introspector).Instances()
+	// modules without dependencies could be instantiated in any order
+	if len(instances) == 3 && instances[0].Name == "D3" {
+		instances = append(instances[1:], instances[0])
+	}
+	if !reflect.DeepEqual(instances, expected) {
+		t.Errorf("bad instances from Instances(): got %v, expected %v", instances, expected)
+	}
+}
+
+func TestReflectModule_InvalidDescription(t *testing.T) {
+	_, err := reflectModule(&invalidDescribedModule{})
+	if err == nil {
+		t.Error("expect error after reflectModule() on undefined described instance")
+	}
+	t.Log(err.Error())
+
+	_, err = reflectModule(NewModule("built").Describe("D1", "undefined instance").Build())
+	if err == nil {
+		t.Error("expect error after reflectModule() on undefined described instance of built module")
+	}
+	t.Log(err.Error())
+}
+
+func TestUnused(t *testing.T) {
+	c := CreateContainer(&M1{}, &M2{}, &M3{}, &M4{}, &M5{})
+
+	expected := []string{"DM3"}
+	if unused := c.(Introspector).Unused(); !reflect.DeepEqual(unused, expected) {
+		t.Errorf("bad result from Unused(): got %v, expected %v", unused, expected)
+	}
+
+	c.InstanceByName("DM3")
+	expected = []string{}
+	if unused := c.(Introspector).Unused(); !reflect.DeepEqual(unused, expected) {
+		t.Errorf("bad result from Unused() after InstanceByName(): got %v, expected %v", unused, expected)
+	}
+
+	c = CreateContainer(&M1{})
+	c.Instance(reflect.TypeOf((*D1)(nil)).Elem())
+	expected = []string{"D2"}
+	if unused := c.(Introspector).Unused(); !reflect.DeepEqual(unused, expected) {
+		t.Errorf("bad result from Unused() after Instance(): got %v, expected %v", unused, expected)
+	}
+}
+
+func TestConstructedAndPending(t *testing.T) {
+	c := CreateContainerWithOptions([]Module{&M1{}}, WithLazy())
+	in := c.(Introspector)
+	if constructed, pending := in.Constructed(), in.Pending(); len(constructed) != 0 || len(pending) != 2 {
+		t.Errorf("bad states before retrieval in lazy mode: got constructed %v and pending %v, expected none and 2",
+			constructed, pending)
+	}
+
+	c.InstanceByName("D1")
+	if constructed := in.Constructed(); !reflect.DeepEqual(constructed, []string{"D1"}) {
+		t.Errorf("bad result from Constructed() after retrieval: got %v, expected [D1]", constructed)
+	}
+	if pending := in.Pending(); !reflect.DeepEqual(pending, []string{"D2"}) {
+		t.Errorf("bad result from Pending() after retrieval: got %v, expected [D2]", pending)
+	}
+	for _, info := range in.Instances() {
+		expected := InstancePending
+		if info.Name == "D1" {
+			expected = InstanceConstructed
+		}
+		if info.State != expected {
+			t.Errorf("bad state of %s from Instances(): got %s, expected %s", info.Name, info.State, expected)
+		}
+	}
+}
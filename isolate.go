@@ -0,0 +1,70 @@
+package alice
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Isolation configures the construction of an instance on a dedicated goroutine, for constructors which recurse
+// deeply, rely on the state of the OS thread, or panic in ways to be translated. Go grows goroutine stacks on demand,
+// so the dedicated goroutine starts with a fresh stack rather than a reserved size. The maximum stack size is
+// process-wide, and could be raised by runtime/debug.SetMaxStack.
+type Isolation struct {
+	// LockOSThread locks the goroutine to its OS thread during the construction, e.g. for C libraries keeping
+	// thread-local state.
+	LockOSThread bool
+	// Translate, if not nil, converts the value recovered from a panicking construction to the error reported as the
+	// cause of the failure.
+	Translate func(recovered interface{}) error
+}
+
+// markIsolatedInstances sets the isolations of the instances with the specified names.
+func markIsolatedInstances(moduleName string, instances []*instanceMethod, isolations map[string]Isolation) error {
+	for name, isolation := range isolations {
+		instance := findInstanceMethod(instances, name)
+		if instance == nil {
+			return fmt.Errorf("isolated instance %s.%s is not defined", moduleName, name)
+		}
+		isolation := isolation
+		instance.isolation = &isolation
+	}
+	return nil
+}
+
+// isolatedInstance builds an instance on a dedicated goroutine and waits for it. The goroutine is tracked as
+// constructing "isolated <name>", which the calling goroutine waits for, so re-entrant resolutions through the
+// goroutine are still detected. A panic is raised again on the calling goroutine, translated if configured.
+func (c *container) isolatedInstance(im *instanceMethod) interface{} {
+	key := "isolated " + im.name
+	started := make(chan struct{})
+	done := make(chan struct{})
+	var instance interface{}
+	var recovered interface{}
+	go func() {
+		defer close(done)
+		if im.isolation.LockOSThread {
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+		}
+		defer func() {
+			recovered = recover()
+		}()
+		gid := c.constructions.begin(key)
+		defer c.constructions.end(gid, key)
+		close(started)
+		instance = c.buildInstance(im)
+	}()
+
+	<-started
+	c.await(key, done)
+	if recovered != nil {
+		if im.isolation.Translate == nil {
+			panic(recovered)
+		}
+		if failure, ok := recovered.(constructorFailure); ok {
+			panic(constructorFailure{recovered: im.isolation.Translate(failure.recovered)})
+		}
+		panic(im.isolation.Translate(recovered))
+	}
+	return instance
+}
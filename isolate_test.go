@@ -0,0 +1,87 @@
+package alice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type isolatedModule struct {
+	BaseModule
+	c           *Container
+	constructor uint64
+}
+
+func (m *isolatedModule) D1() D1 {
+	m.constructor = goroutineID()
+	return &D1Impl{}
+}
+
+func (m *isolatedModule) D2() D2 {
+	panic("failed to create D2")
+}
+
+func (m *isolatedModule) D3() D3 {
+	// re-entrant through the isolated goroutine
+	(*m.c).InstanceByName("D4")
+	return &D3Impl{}
+}
+
+func (m *isolatedModule) D4() D4 {
+	(*m.c).InstanceByName("D3")
+	return &D4Impl{}
+}
+
+func (m *isolatedModule) Isolated() map[string]Isolation {
+	return map[string]Isolation{
+		"D1": {LockOSThread: true},
+		"D2": {Translate: func(recovered interface{}) error {
+			return fmt.Errorf("translated: %v", recovered)
+		}},
+		"D3": {},
+	}
+}
+
+func TestIsolation(t *testing.T) {
+	var c Container
+	m := &isolatedModule{c: &c}
+	c = CreateContainerWithOptions([]Module{m}, WithLazy())
+
+	if c.InstanceByName("D1") == nil {
+		t.Error("expected isolated instance D1")
+	}
+	if m.constructor == 0 || m.constructor == goroutineID() {
+		t.Errorf("bad goroutine constructing D1: got %d, expected a dedicated one", m.constructor)
+	}
+
+	_, err := c.ResolveByName("D2")
+	var ce *ConstructionError
+	if !errors.As(err, &ce) || !strings.Contains(fmt.Sprint(ce.Cause), "translated: failed to create D2") {
+		t.Errorf("bad error after ResolveByName(): got %v, expected the translated panic", err)
+	}
+
+	_, err = c.ResolveByName("D3")
+	if err == nil || !strings.Contains(err.Error(), "re-entrant resolution") {
+		t.Errorf("bad error after re-entrant ResolveByName(): got %v, expected re-entrant resolution", err)
+	}
+}
+
+func TestIsolation_Built(t *testing.T) {
+	var constructor uint64
+	built := NewModule("built").
+		Provide("D1", func() D1 {
+			constructor = goroutineID()
+			return &D1Impl{}
+		}).
+		Isolate("D1", Isolation{}).
+		Build()
+	CreateContainer(built)
+	if constructor == 0 || constructor == goroutineID() {
+		t.Errorf("bad goroutine constructing D1: got %d, expected a dedicated one", constructor)
+	}
+
+	if err := Validate(NewModule("built").Isolate("Undefined", Isolation{}).Build()); err == nil {
+		t.Error("expected error for undefined isolated instance")
+	}
+}
@@ -0,0 +1,249 @@
+package alice
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// lazyInstance is an instance constructed on first use in lazy mode.
+type lazyInstance struct {
+	module *lazyModule
+	method *instanceMethod
+
+	// done is closed when the construction finishes. It is nil if the construction hasn't started.
+	done chan struct{}
+	// recovered is the value recovered if the construction panics.
+	recovered interface{}
+}
+
+// lazyModule is a module whose dependencies are injected on first use in lazy mode.
+type lazyModule struct {
+	rm *reflectedModule
+
+	// done is closed when the dependencies are injected. It is nil if the injection hasn't started.
+	done chan struct{}
+	// recovered is the value recovered if the injection panics.
+	recovered interface{}
+	// migrated indicates the module is migrated, so it isn't migrated again when the dependencies are injected again
+	// after a reset.
+	migrated bool
+}
+
+// withForeground returns an option which constructs background instances on demand in lazy mode, for the containers
+// which must not construct instances other than the ones retrieved.
+func withForeground() Option {
+	return func(o *options) {
+		o.foreground = true
+	}
+}
+
+// prepareLazy records the instances to be constructed lazily. Background instances are constructed in background
+// right away, unless the container is created for Migrate or Validate.
+func (c *container) prepareLazy(rms []*reflectedModule) {
+	c.lazyByName = make(map[string]*lazyInstance)
+	var background []string
+	for _, rm := range rms {
+		lm := &lazyModule{rm: rm}
+		c.lazyModules = append(c.lazyModules, lm)
+		for _, instanceMethod := range rm.instances {
+			c.lazyByName[instanceMethod.name] = &lazyInstance{
+				module: lm,
+				method: instanceMethod,
+			}
+			if instanceMethod.background {
+				background = append(background, instanceMethod.name)
+			}
+		}
+	}
+
+	if c.options.foreground {
+		return
+	}
+	for _, name := range background {
+		go func(name string) {
+			// the failure is recorded and reported when the instance is needed
+			defer func() {
+				recover()
+			}()
+			c.findInstanceByName(name)
+		}(name)
+	}
+}
+
+// constructLazy constructs a lazy instance if it hasn't been constructed. It waits if the instance is being
+// constructed by another goroutine. It panics if the construction fails.
+func (c *container) constructLazy(li *lazyInstance) {
+	c.mu.Lock()
+	done := li.done
+	if done == nil {
+		li.done = make(chan struct{})
+	}
+	c.mu.Unlock()
+
+	if done == nil {
+		c.buildLazy(li)
+	} else {
+		c.await(li.method.name, done)
+	}
+	if li.recovered != nil {
+		panic(li.recovered)
+	}
+}
+
+// buildLazy injects the dependencies of the module and calls the instance method.
+func (c *container) buildLazy(li *lazyInstance) {
+	defer close(li.done)
+	defer func() {
+		li.recovered = recover()
+	}()
+	c.constructions.construct(li.method.name, func() {
+		start := time.Now()
+		c.injectLazyModule(li.module)
+		instance := c.callInstanceMethod(li.method)
+		if c.options.budget != nil {
+			c.checkInstanceBudget(li.method.name, time.Since(start))
+		}
+		c.addInstance(li.method.name, li.method.tp, instance)
+	})
+}
+
+// injectLazyModule injects the dependencies of a module if they haven't been injected.
+func (c *container) injectLazyModule(lm *lazyModule) {
+	c.mu.Lock()
+	done := lm.done
+	if done == nil {
+		lm.done = make(chan struct{})
+	}
+	c.mu.Unlock()
+
+	key := "module " + lm.rm.name
+	if done == nil {
+		func() {
+			defer close(lm.done)
+			defer func() {
+				lm.recovered = recover()
+			}()
+			c.constructions.construct(key, func() {
+				c.injectDependencies(lm.rm)
+				if !lm.migrated {
+					c.migrateModule(lm.rm)
+					lm.migrated = true
+				}
+			})
+		}()
+	} else {
+		c.await(key, done)
+	}
+	if lm.recovered != nil {
+		panic(lm.recovered)
+	}
+}
+
+// findLazyInstanceByType finds an instance by type in lazy mode. As instances may not be constructed yet, it matches
+// the types declared by the instance methods.
+func (c *container) findLazyInstanceByType(t reflect.Type) interface{} {
+	var exact, assignable []string
+	for name, li := range c.lazyByName {
+		if li.method.tp == t {
+			exact = append(exact, name)
+		} else if li.method.tp.AssignableTo(t) {
+			assignable = append(assignable, name)
+		}
+	}
+	names := exact
+	if len(names) == 0 && !c.options.strict {
+		names = assignable
+	}
+	if len(names) == 0 {
+		panic(&LookupError{Type: t, Err: ErrNotFound})
+	}
+	if len(names) > 1 {
+		panic(&LookupError{Type: t, Err: ErrAmbiguous})
+	}
+
+	return c.findInstanceByName(names[0])
+}
+
+func (c *container) Warm(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		c.mu.Lock()
+		for name := range c.instanceByName {
+			names = append(names, name)
+		}
+		for name := range c.pending {
+			names = append(names, name)
+		}
+		for name := range c.lazyByName {
+			if _, ok := c.instanceByName[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		c.mu.Unlock()
+	}
+
+	queue := make(chan string)
+	errs := make(chan error, len(names))
+	var wg sync.WaitGroup
+	var progressMu sync.Mutex
+	warmed := 0
+	for i := 0; i < c.options.warmParallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range queue {
+				if err := c.warmInstance(name); err != nil {
+					errs <- err
+					continue
+				}
+				if c.options.warmProgress != nil {
+					progressMu.Lock()
+					warmed++
+					c.options.warmProgress(name, warmed, len(names))
+					progressMu.Unlock()
+				}
+			}
+		}()
+	}
+
+	var err error
+loop:
+	for _, name := range names {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break loop
+		case err = <-errs:
+			break loop
+		case queue <- name:
+		}
+	}
+	close(queue)
+	wg.Wait()
+	close(errs)
+
+	if err != nil {
+		return err
+	}
+	return <-errs
+}
+
+// warmInstance constructs an instance, converting the panic to an error.
+func (c *container) warmInstance(name string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = fmt.Errorf("failed to warm instance %s: %w", name, e)
+			} else {
+				err = fmt.Errorf("failed to warm instance %s: %v", name, r)
+			}
+		}
+	}()
+	c.findInstanceByName(name)
+	return nil
+}
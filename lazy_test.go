@@ -0,0 +1,233 @@
+package alice
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type CountingModule struct {
+	BaseModule
+	mu    sync.Mutex
+	count map[string]int
+}
+
+func (m *CountingModule) inc(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.count == nil {
+		m.count = make(map[string]int)
+	}
+	m.count[name]++
+}
+
+func (m *CountingModule) D1() D1 {
+	m.inc("D1")
+	return &D1Impl{}
+}
+
+func (m *CountingModule) D2() D2 {
+	m.inc("D2")
+	return &D2Impl{}
+}
+
+type PanicModule struct {
+	BaseModule
+}
+
+func (m *PanicModule) D3() D3 {
+	panic("failed to create D3")
+}
+
+func TestPopulate_Lazy(t *testing.T) {
+	m := &CountingModule{}
+	m4 := &M4{}
+	c := &container{modules: []Module{m, m4}, options: newOptions(WithLazy())}
+	c.populate()
+
+	if len(m.count) != 0 {
+		t.Errorf("bad count after populate(): got %v, expected empty", m.count)
+	}
+	if m4.D1 != nil {
+		t.Errorf("bad m4.D1 after populate(): got %v, expected nil", m4.D1)
+	}
+
+	d3 := c.Instance(reflect.TypeOf((*D3)(nil)).Elem())
+	expectedD3 := &D3Impl{}
+	if !reflect.DeepEqual(d3, expectedD3) {
+		t.Errorf("bad instance from Instance(): got %v, expected %v", d3, expectedD3)
+	}
+	expectedM4 := &M4{D1: &D1Impl{}}
+	if !reflect.DeepEqual(m4, expectedM4) {
+		t.Errorf("bad m4 after Instance(): got %v, expected %v", m4, expectedM4)
+	}
+	expectedCount := map[string]int{"D1": 1}
+	if !reflect.DeepEqual(m.count, expectedCount) {
+		t.Errorf("bad count after Instance(): got %v, expected %v", m.count, expectedCount)
+	}
+
+	c.InstanceByName("D1")
+	if !reflect.DeepEqual(m.count, expectedCount) {
+		t.Errorf("bad count after InstanceByName(): got %v, expected %v", m.count, expectedCount)
+	}
+}
+
+func TestInstance_LazyPanicOnMultipleMatchedType(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected panic for Instance() on multiple matched type")
+		} else {
+			t.Log(r)
+		}
+	}()
+
+	c := &container{modules: []Module{&M1{}, &M2{}, &M3{}, &M4{}}, options: newOptions(WithLazy())}
+	c.populate()
+
+	c.Instance(reflect.TypeOf((*D1)(nil)).Elem())
+}
+
+func TestWarm(t *testing.T) {
+	m := &CountingModule{}
+	var progress []string
+	var totals []int
+	var mu sync.Mutex
+	c := CreateContainerWithOptions([]Module{m, &M4{}},
+		WithLazy(),
+		WithWarmParallelism(2),
+		WithWarmProgress(func(name string, warmed int, total int) {
+			mu.Lock()
+			defer mu.Unlock()
+			progress = append(progress, name)
+			totals = append(totals, total)
+		}))
+
+	if err := c.(Lifecycle).Warm(context.Background(), "D2", "D3"); err != nil {
+		t.Errorf("unexpected error after Warm(): %s", err.Error())
+	}
+	expectedCount := map[string]int{"D1": 1, "D2": 1}
+	if !reflect.DeepEqual(m.count, expectedCount) {
+		t.Errorf("bad count after Warm(): got %v, expected %v", m.count, expectedCount)
+	}
+	if len(progress) != 2 || !reflect.DeepEqual(totals, []int{2, 2}) {
+		t.Errorf("bad progress after Warm(): got %v with totals %v, expected 2 instances", progress, totals)
+	}
+
+	if err := c.(Lifecycle).Warm(context.Background()); err != nil {
+		t.Errorf("unexpected error after Warm(): %s", err.Error())
+	}
+}
+
+func TestWarm_Error(t *testing.T) {
+	c := CreateContainerWithOptions([]Module{&PanicModule{}}, WithLazy())
+
+	err := c.(Lifecycle).Warm(context.Background(), "D3")
+	if err == nil {
+		t.Error("expected error after Warm() on failed instance")
+	}
+	t.Log(err.Error())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := c.(Lifecycle).Warm(ctx, "D3"); err != context.Canceled {
+		t.Errorf("bad error after Warm() with done context: got %v, expected %v", err, context.Canceled)
+	}
+}
+
+type blockingModule struct {
+	BaseModule
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (m *blockingModule) D1() D1 {
+	m.calls.Add(1)
+	<-m.release
+	return &D1Impl{}
+}
+
+type observingModule struct {
+	BaseModule
+	D1       D1 `alice:"D1"`
+	calls    atomic.Int32
+	observed atomic.Value
+}
+
+func (m *observingModule) D3() D3 {
+	m.calls.Add(1)
+	m.observed.Store(m.D1 != nil)
+	return &D3Impl{}
+}
+
+func TestLazy_ConcurrentFirstResolutions(t *testing.T) {
+	slow := &blockingModule{release: make(chan struct{})}
+	observing := &observingModule{}
+	c := CreateContainerWithOptions([]Module{slow, observing}, WithLazy())
+
+	const n = 50
+	var wg sync.WaitGroup
+	d1s := make([]interface{}, n)
+	d3s := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				d1s[i] = c.InstanceByName("D1")
+			} else {
+				d1s[i] = c.Instance(reflect.TypeOf((*D1)(nil)).Elem())
+			}
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			d3s[i] = c.InstanceByName("D3")
+		}(i)
+	}
+	// let the goroutines pile up on the construction in flight
+	for slow.calls.Load() == 0 {
+		runtime.Gosched()
+	}
+	close(slow.release)
+	wg.Wait()
+
+	if calls := slow.calls.Load(); calls != 1 {
+		t.Errorf("bad calls of D1 after concurrent resolutions: got %d, expected %d", calls, 1)
+	}
+	if calls := observing.calls.Load(); calls != 1 {
+		t.Errorf("bad calls of D3 after concurrent resolutions: got %d, expected %d", calls, 1)
+	}
+	if observed := observing.observed.Load(); observed != true {
+		t.Errorf("bad dependency observed by D3: got injected %v, expected %v", observed, true)
+	}
+	for i := 0; i < n; i++ {
+		if d1s[i] != d1s[0] || d3s[i] != d3s[0] {
+			t.Fatalf("bad instances after concurrent resolutions: got %v and %v, expected %v and %v", d1s[i],
+				d3s[i], d1s[0], d3s[0])
+		}
+	}
+}
+
+func TestLazy_ConcurrentFailure(t *testing.T) {
+	c := CreateContainerWithOptions([]Module{&PanicModule{}}, WithLazy())
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = c.ResolveByName("D3")
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err == nil {
+			t.Errorf("bad error of resolution %d: got nil, expected the construction failure", i)
+		}
+	}
+}
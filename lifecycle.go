@@ -0,0 +1,136 @@
+package alice
+
+import (
+	"context"
+	"fmt"
+)
+
+// Starter is an optional interface an instance could implement to be started by Container.Start, e.g. to start
+// serving or consuming after all instances are wired.
+type Starter interface {
+	// Start starts the instance. It should return once the instance is started.
+	Start(ctx context.Context) error
+}
+
+// Stopper is an optional interface an instance could implement to be stopped by Container.Stop, e.g. to release
+// resources.
+type Stopper interface {
+	// Stop stops the instance.
+	Stop(ctx context.Context) error
+}
+
+func (c *container) Start(ctx context.Context) error {
+	c.mu.Lock()
+	c.rolledBack = make(map[string]bool)
+	c.mu.Unlock()
+	var started []string
+	for _, name := range c.ownedInstanceNames() {
+		if err := ctx.Err(); err != nil {
+			return c.options.formatError(c.rollbackStart(ctx, &StartError{Instance: name, Err: err}, started))
+		}
+		// a lazy or background instance is constructed here, so its failure is rolled back like a failed start
+		instance, err := resolveSafely(func() interface{} {
+			return c.findInstanceByName(name)
+		})
+		if err != nil {
+			return c.options.formatError(c.rollbackStart(ctx, &StartError{Instance: name, Err: err}, started))
+		}
+		if notifier, ok := instance.(changeNotifier); ok {
+			c.subscribeChanges(name, notifier)
+		}
+		starter, ok := instance.(Starter)
+		if !ok {
+			continue
+		}
+		if err := starter.Start(ctx); err != nil {
+			// the failed instance could have started partially, so it is rolled back too
+			started = append(started, name)
+			return c.options.formatError(c.rollbackStart(ctx, &StartError{Instance: name, Err: err}, started))
+		}
+		started = append(started, name)
+	}
+	return nil
+}
+
+func (c *container) Stop(ctx context.Context) error {
+	c.unsubscribeChanges()
+	names := c.ownedInstanceNames()
+	c.mu.Lock()
+	rolledBack := c.rolledBack
+	c.mu.Unlock()
+	var stoppers []namedStopper
+	for i := len(names) - 1; i >= 0; i-- {
+		if rolledBack[names[i]] {
+			continue
+		}
+		if stopper, ok := c.constructedInstance(names[i]).(Stopper); ok {
+			stoppers = append(stoppers, namedStopper{name: names[i], stopper: stopper})
+		}
+	}
+	errs := c.stopAll(ctx, stoppers)
+	if err := c.scopes.leakError(); err != nil {
+		errs = append(errs, err)
+	}
+	return c.options.formatError(joinErrors(errs))
+}
+
+// stopAll stops the instances in order, bounded by the stop timeouts if set. It returns the errors of all of them.
+func (c *container) stopAll(ctx context.Context, stoppers []namedStopper) []error {
+	if c.options.stopTimeouts != nil {
+		return c.stopWithTimeouts(ctx, stoppers)
+	}
+	var errs []error
+	for _, s := range stoppers {
+		if err := s.stopper.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop instance %s: %w", s.name, err))
+		}
+	}
+	return errs
+}
+
+// instanceNames returns the names of all instances in instantiation order.
+func (c *container) instanceNames() []string {
+	var names []string
+	for _, rm := range c.reflected {
+		for _, instance := range rm.instances {
+			names = append(names, instance.name)
+		}
+	}
+	return names
+}
+
+// ownedInstanceNames returns the names of the instances in instantiation order, except the ones imported from other
+// containers.
+func (c *container) ownedInstanceNames() []string {
+	var names []string
+	for _, rm := range c.reflected {
+		if bm, ok := rm.m.(*builtModule); ok && bm.imported {
+			continue
+		}
+		for _, instance := range rm.instances {
+			names = append(names, instance.name)
+		}
+	}
+	return names
+}
+
+// constructedInstance returns the instance with the specified name if it has been constructed, or nil otherwise. It
+// waits for the instance if it is being constructed in background.
+func (c *container) constructedInstance(name string) interface{} {
+	c.mu.Lock()
+	p, isPending := c.pending[name]
+	c.mu.Unlock()
+	if isPending {
+		func() {
+			// the failure is reported when the instance is retrieved
+			defer func() {
+				recover()
+			}()
+			c.awaitPending(name, p)
+		}()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.instanceByName[name]
+}
@@ -0,0 +1,113 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// lifecycleLog records the lifecycle events of instances.
+type lifecycleLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *lifecycleLog) add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *lifecycleLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string{}, l.events...)
+}
+
+type lifecycleService struct {
+	name    string
+	log     *lifecycleLog
+	failOn  string
+	started bool
+}
+
+func (s *lifecycleService) Start(ctx context.Context) error {
+	if s.failOn == "start" {
+		return errors.New("start failed")
+	}
+	s.started = true
+	s.log.add("start " + s.name)
+	return nil
+}
+
+func (s *lifecycleService) Stop(ctx context.Context) error {
+	s.log.add("stop " + s.name)
+	if s.failOn == "stop" {
+		return errors.New("stop failed")
+	}
+	return nil
+}
+
+func lifecycleModules(log *lifecycleLog, failOn map[string]string) []Module {
+	db := NewModule("db").
+		Provide("DB", func() *lifecycleService {
+			return &lifecycleService{name: "DB", log: log, failOn: failOn["DB"]}
+		}).
+		Build()
+	var dbService *lifecycleService
+	server := NewModule("server").
+		RequireNamed("DB", &dbService).
+		Provide("Server", func() Starter {
+			return &lifecycleService{name: "Server", log: log, failOn: failOn["Server"]}
+		}).
+		Build()
+	return []Module{server, db}
+}
+
+func TestStartStop(t *testing.T) {
+	log := &lifecycleLog{}
+	c := CreateContainerWithOptions(lifecycleModules(log, nil), WithLazy())
+	if err := c.(Lifecycle).Start(context.Background()); err != nil {
+		t.Fatalf("bad error after Start(): got %v, expected nil", err)
+	}
+	if err := c.(Lifecycle).Stop(context.Background()); err != nil {
+		t.Fatalf("bad error after Stop(): got %v, expected nil", err)
+	}
+
+	expected := []string{"start DB", "start Server", "stop Server", "stop DB"}
+	if events := log.get(); !reflect.DeepEqual(events, expected) {
+		t.Errorf("bad events after Start() and Stop(): got %v, expected %v", events, expected)
+	}
+}
+
+func TestStartStop_Errors(t *testing.T) {
+	log := &lifecycleLog{}
+	c := CreateContainer(lifecycleModules(log, map[string]string{"Server": "start"})...)
+	if err := c.(Lifecycle).Start(context.Background()); err == nil {
+		t.Error("expected error after Start() with failing instance")
+	}
+
+	log = &lifecycleLog{}
+	c = CreateContainer(lifecycleModules(log, map[string]string{"DB": "stop", "Server": "stop"})...)
+	err := c.(Lifecycle).Stop(context.Background())
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("bad error after Stop() with failing instances: got %v, expected 2 errors", err)
+	}
+	if expected := []string{"stop Server", "stop DB"}; !reflect.DeepEqual(log.get(), expected) {
+		t.Errorf("bad events after Stop(): got %v, expected %v", log.get(), expected)
+	}
+}
+
+func TestStop_NotConstructed(t *testing.T) {
+	log := &lifecycleLog{}
+	c := CreateContainerWithOptions(lifecycleModules(log, nil), WithLazy())
+	if err := c.(Lifecycle).Stop(context.Background()); err != nil {
+		t.Errorf("bad error after Stop(): got %v, expected nil", err)
+	}
+	if events := log.get(); len(events) != 0 {
+		t.Errorf("bad events after Stop() on lazy container: got %v, expected none", events)
+	}
+}
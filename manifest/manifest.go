@@ -0,0 +1,79 @@
+// Package manifest assembles alice containers from declarative wiring manifests, so operators could tweak the wiring,
+// e.g. swap the cache implementation, without recompiling. A manifest lists the modules by the names of factories
+// registered in an alice.Registry, with their parameters and the profiles they belong to:
+//
+//	{
+//		"modules": [
+//			{"factory": "redis-cache", "params": {"addr": "localhost:6379"}, "profiles": ["prod"]},
+//			{"factory": "memory-cache", "profiles": ["dev"]},
+//			{"factory": "users"}
+//		],
+//		"bindings": {"Retries": 3}
+//	}
+//
+// Manifests are JSON by default. YAML manifests are decoded by passing the Unmarshal function of a YAML package to
+// Parse, so the package doesn't depend on any of them.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/magic003/alice"
+)
+
+// Manifest is the declarative wiring of a container.
+type Manifest = alice.Spec
+
+// ModuleSpec declares a module created by a registered factory.
+type ModuleSpec = alice.ModuleSpec
+
+// Registry contains the module factories manifests refer to, see alice.Registry.
+type Registry = alice.Registry
+
+// NewRegistry creates an empty registry, like alice.NewRegistry. Manifests could also be passed to
+// alice.CreateContainerFromSpec, which uses the factories registered by alice.RegisterFactory.
+func NewRegistry() *Registry {
+	return alice.NewRegistry()
+}
+
+// Parse decodes a manifest by unmarshal, e.g. yaml.Unmarshal, or as JSON if it is nil. JSON numbers of bindings
+// are decoded as int if they are integers, or float64 otherwise.
+func Parse(data []byte, unmarshal func([]byte, interface{}) error) (*Manifest, error) {
+	if unmarshal == nil {
+		unmarshal = unmarshalJSON
+	}
+	var m Manifest
+	if err := unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	for name, value := range m.Bindings {
+		if n, ok := value.(json.Number); ok {
+			if i, err := strconv.Atoi(n.String()); err == nil {
+				m.Bindings[name] = i
+			} else {
+				m.Bindings[name], _ = n.Float64()
+			}
+		}
+	}
+	return &m, nil
+}
+
+// unmarshalJSON decodes JSON keeping numbers as json.Number.
+func unmarshalJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// Load reads and decodes the manifest file like Parse.
+func Load(path string, unmarshal func([]byte, interface{}) error) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data, unmarshal)
+}
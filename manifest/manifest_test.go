@@ -0,0 +1,174 @@
+package manifest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/magic003/alice"
+)
+
+type Cache interface {
+	Get(key string) string
+}
+
+type remoteCache struct {
+	addr string
+}
+
+func (c *remoteCache) Get(key string) string {
+	return c.addr + "/" + key
+}
+
+type memoryCache struct{}
+
+func (c *memoryCache) Get(key string) string {
+	return "memory/" + key
+}
+
+type RemoteCacheConfig struct {
+	Addr string `json:"addr"`
+}
+
+type RemoteCacheModule struct {
+	alice.BaseModule
+	config RemoteCacheConfig
+}
+
+func (m *RemoteCacheModule) Cache() Cache {
+	return &remoteCache{addr: m.config.Addr}
+}
+
+type MemoryCacheModule struct {
+	alice.BaseModule
+}
+
+func (m *MemoryCacheModule) Cache() Cache {
+	return &memoryCache{}
+}
+
+type UserModule struct {
+	alice.BaseModule
+	Cache   Cache `alice:""`
+	Retries int   `alice:"Retries"`
+}
+
+func (m *UserModule) Users() *users {
+	return &users{cache: m.Cache, retries: m.Retries}
+}
+
+type users struct {
+	cache   Cache
+	retries int
+}
+
+func newRegistry() *Registry {
+	r := NewRegistry()
+	r.Register("remote-cache", func(config RemoteCacheConfig) alice.Module {
+		return &RemoteCacheModule{config: config}
+	})
+	r.Register("memory-cache", func() alice.Module { return &MemoryCacheModule{} })
+	r.Register("users", func() *UserModule { return &UserModule{} })
+	return r
+}
+
+const manifestJSON = `{
+	"modules": [
+		{"factory": "remote-cache", "params": {"addr": "cache:6379"}, "profiles": ["prod"]},
+		{"factory": "memory-cache", "profiles": ["dev", "test"]},
+		{"factory": "users"}
+	],
+	"bindings": {"Retries": 3}
+}`
+
+func TestCreateContainer(t *testing.T) {
+	m, err := Parse([]byte(manifestJSON), nil)
+	if err != nil {
+		t.Fatalf("bad error after Parse(): got %v, expected nil", err)
+	}
+	r := newRegistry()
+
+	c, err := r.CreateContainer(m, []string{"prod"})
+	if err != nil {
+		t.Fatalf("bad error after CreateContainer(): got %v, expected nil", err)
+	}
+	u := c.InstanceByName("Users").(*users)
+	if s := u.cache.Get("k"); s != "cache:6379/k" || u.retries != 3 {
+		t.Errorf("bad instance with prod profile: got %q and %d retries", s, u.retries)
+	}
+
+	c, err = r.CreateContainer(m, []string{"dev"})
+	if err != nil {
+		t.Fatalf("bad error after CreateContainer(): got %v, expected nil", err)
+	}
+	if s := c.InstanceByName("Users").(*users).cache.Get("k"); s != "memory/k" {
+		t.Errorf("bad instance with dev profile: got %q, expected %q", s, "memory/k")
+	}
+
+	if _, err := r.CreateContainer(m, nil); err == nil || !strings.Contains(err.Error(), "Cache") {
+		t.Errorf("bad error after CreateContainer() without a cache: got %v", err)
+	}
+}
+
+func TestModules_Invalid(t *testing.T) {
+	m, _ := Parse([]byte(`{"modules": [
+		{"factory": "missing-cache", "profiles": ["staging"]},
+		{"factory": "remote-cache", "params": {"address": "cache:6379"}, "profiles": ["staging"]},
+		{"factory": "users", "params": {"retries": 3}}
+	]}`), nil)
+	_, err := newRegistry().Modules(m, "prod")
+	var errs alice.Errors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("bad error after Modules(): got %v, expected 3 errors", err)
+	}
+	for i, expected := range []string{"is not registered", "unknown field", "takes no params"} {
+		if !strings.Contains(errs[i].Error(), expected) {
+			t.Errorf("bad error %d after Modules(): got %v, expected %s", i, errs[i], expected)
+		}
+	}
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	r := newRegistry()
+	for _, factory := range []interface{}{"users", func(a, b int) alice.Module { return nil }, func() int { return 0 }} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic after Register() with %T", factory)
+				}
+			}()
+			r.Register("invalid", factory)
+		}()
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic after Register() with a registered name")
+		}
+	}()
+	r.Register("users", func() alice.Module { return &UserModule{} })
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wiring.json")
+	os.WriteFile(path, []byte(manifestJSON), 0o644)
+	m, err := Load(path, nil)
+	if err != nil {
+		t.Fatalf("bad error after Load(): got %v, expected nil", err)
+	}
+	if len(m.Modules) != 3 || !reflect.DeepEqual(m.Modules[1].Profiles, []string{"dev", "test"}) {
+		t.Errorf("bad manifest after Load(): got %v", m)
+	}
+	if factories := newRegistry().Factories(); len(factories) != 3 || factories[0] != "memory-cache" {
+		t.Errorf("bad factories after Factories(): got %v", factories)
+	}
+
+	if _, err := Parse([]byte("modules:"), nil); err == nil {
+		t.Error("expected error after Parse() with invalid JSON")
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json"), nil); err == nil {
+		t.Error("expected error after Load() with missing file")
+	}
+}
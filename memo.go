@@ -0,0 +1,275 @@
+package alice
+
+import (
+	"container/list"
+	"io"
+	"sync"
+	"time"
+)
+
+// Memo is a parameterized factory which memoizes the instances by key, so repeated calls with equal keys return the
+// same instance, e.g. per-tenant clients keyed by tenant ID. It is usually provided as an instance by a module:
+//
+//	func (m *ClientModule) TenantClients() *alice.Memo[string, *Client] {
+//		return alice.NewMemo(func(tenant string) *Client {
+//			return NewClient(m.Config, tenant)
+//		}, func(tenant string, client *Client) {
+//			client.Close()
+//		})
+//	}
+//
+// It is safe for concurrent use. An instance is created only once per key even if it is requested concurrently.
+// Options like MemoIdleTTL and MemoMaxEntries bound the instances kept in long-running processes.
+type Memo[K comparable, T any] struct {
+	create  func(K) T
+	onEvict func(K, T)
+	options memoOptions
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[K]*memoEntry[T]
+	// recent orders the entries by their last use, the most recent first.
+	recent    *list.List
+	lastSweep time.Time
+}
+
+// memoEntry is an instance created by a Memo.
+type memoEntry[T any] struct {
+	once     sync.Once
+	value    T
+	lastUsed time.Time
+	element  *list.Element
+	// created, evicted and released are guarded by the lock of the Memo.
+	created  bool
+	evicted  bool
+	released bool
+}
+
+// claim checks if the instance of an evicted entry is to be released by the caller, so it is released only once,
+// either by the eviction or by the creation finishing after it. The caller must hold the lock.
+func (e *memoEntry[T]) claim() bool {
+	if !e.created || !e.evicted || e.released {
+		return false
+	}
+	e.released = true
+	return true
+}
+
+// MemoOption customizes the eviction of a Memo.
+type MemoOption func(*memoOptions)
+
+type memoOptions struct {
+	idleTTL      time.Duration
+	maxEntries   int
+	closeOnEvict bool
+}
+
+// MemoIdleTTL returns an option which evicts the instances not used for the duration. Idle instances are swept by
+// Get, at most once per half of the duration, or by Sweep.
+func MemoIdleTTL(ttl time.Duration) MemoOption {
+	return func(o *memoOptions) {
+		o.idleTTL = ttl
+	}
+}
+
+// MemoMaxEntries returns an option which keeps at most n instances, evicting the least recently used ones.
+func MemoMaxEntries(n int) MemoOption {
+	return func(o *memoOptions) {
+		o.maxEntries = n
+	}
+}
+
+// MemoCloseOnEvict returns an option which closes the evicted instances implementing io.Closer, after onEvict is
+// called. The errors of Close are ignored.
+func MemoCloseOnEvict() MemoOption {
+	return func(o *memoOptions) {
+		o.closeOnEvict = true
+	}
+}
+
+// NewMemo creates a Memo calling create for each new key. onEvict, if not nil, is called with the instances evicted
+// by Evict, Clear or the eviction options, e.g. to release their resources.
+func NewMemo[K comparable, T any](create func(K) T, onEvict func(K, T), opts ...MemoOption) *Memo[K, T] {
+	m := &Memo[K, T]{
+		create:  create,
+		onEvict: onEvict,
+		now:     time.Now,
+		entries: make(map[K]*memoEntry[T]),
+		recent:  list.New(),
+	}
+	for _, opt := range opts {
+		opt(&m.options)
+	}
+	return m
+}
+
+// Get returns the instance of the key, creating it on first use.
+func (m *Memo[K, T]) Get(key K) T {
+	now := m.now()
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &memoEntry[T]{}
+		entry.element = m.recent.PushFront(key)
+		m.entries[key] = entry
+	} else {
+		m.recent.MoveToFront(entry.element)
+	}
+	entry.lastUsed = now
+	evicted := m.removeOverflow()
+	if m.options.idleTTL > 0 && now.Sub(m.lastSweep) >= m.options.idleTTL/2 {
+		evicted = append(evicted, m.removeIdle(now)...)
+	}
+	m.mu.Unlock()
+
+	for _, e := range evicted {
+		m.evicted(e.key, e.entry)
+	}
+	if !m.fill(key, entry) {
+		// create panicked, or the entry is evicted while being created, so a new one is created
+		return m.Get(key)
+	}
+	return entry.value
+}
+
+// fill creates the instance of an entry once, waiting for it if it is being created. If create panics, the entry is
+// removed, so it is not memoized with the zero value. If the entry is evicted while being created, the instance is
+// released once created. It returns whether the instance could be handed out, i.e. it is created and not evicted.
+func (m *Memo[K, T]) fill(key K, entry *memoEntry[T]) bool {
+	entry.once.Do(func() {
+		created := false
+		defer func() {
+			m.mu.Lock()
+			entry.created = created
+			if !created && m.entries[key] == entry {
+				m.remove(key, entry)
+			}
+			release := entry.claim()
+			m.mu.Unlock()
+			if release {
+				m.release(key, entry.value)
+			}
+		}()
+		entry.value = m.create(key)
+		created = true
+	})
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return entry.created && !entry.evicted
+}
+
+// Sweep evicts the instances idle for longer than the idle TTL. It does nothing without MemoIdleTTL.
+func (m *Memo[K, T]) Sweep() {
+	if m.options.idleTTL <= 0 {
+		return
+	}
+	now := m.now()
+	m.mu.Lock()
+	evicted := m.removeIdle(now)
+	m.mu.Unlock()
+
+	for _, e := range evicted {
+		m.evicted(e.key, e.entry)
+	}
+}
+
+// removedEntry is an entry removed from a Memo, to be passed to the eviction hook.
+type removedEntry[K comparable, T any] struct {
+	key   K
+	entry *memoEntry[T]
+}
+
+// removeIdle removes the entries idle for longer than the idle TTL. The caller must hold the lock.
+func (m *Memo[K, T]) removeIdle(now time.Time) []removedEntry[K, T] {
+	m.lastSweep = now
+	var removed []removedEntry[K, T]
+	for e := m.recent.Back(); e != nil; e = m.recent.Back() {
+		key := e.Value.(K)
+		entry := m.entries[key]
+		if now.Sub(entry.lastUsed) <= m.options.idleTTL {
+			break
+		}
+		m.remove(key, entry)
+		removed = append(removed, removedEntry[K, T]{key: key, entry: entry})
+	}
+	return removed
+}
+
+// removeOverflow removes the least recently used entries beyond the max entries. The caller must hold the lock.
+func (m *Memo[K, T]) removeOverflow() []removedEntry[K, T] {
+	var removed []removedEntry[K, T]
+	for m.options.maxEntries > 0 && len(m.entries) > m.options.maxEntries {
+		key := m.recent.Back().Value.(K)
+		entry := m.entries[key]
+		m.remove(key, entry)
+		removed = append(removed, removedEntry[K, T]{key: key, entry: entry})
+	}
+	return removed
+}
+
+// remove removes an entry, so it is not handed out anymore. The caller must hold the lock.
+func (m *Memo[K, T]) remove(key K, entry *memoEntry[T]) {
+	delete(m.entries, key)
+	m.recent.Remove(entry.element)
+	entry.evicted = true
+}
+
+// Len returns the number of memoized instances.
+func (m *Memo[K, T]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// Evict removes the instance of the key, so the next Get creates a new one. It returns false if there is no instance
+// of the key.
+func (m *Memo[K, T]) Evict(key K) bool {
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	if ok {
+		m.remove(key, entry)
+	}
+	m.mu.Unlock()
+
+	if ok {
+		m.evicted(key, entry)
+	}
+	return ok
+}
+
+// Clear removes all instances.
+func (m *Memo[K, T]) Clear() {
+	m.mu.Lock()
+	entries := m.entries
+	for _, entry := range entries {
+		entry.evicted = true
+	}
+	m.entries = make(map[K]*memoEntry[T])
+	m.recent.Init()
+	m.mu.Unlock()
+
+	for key, entry := range entries {
+		m.evicted(key, entry)
+	}
+}
+
+// evicted releases the instance of an evicted entry. An instance being created is released once created instead,
+// and one whose creation panicked is not released.
+func (m *Memo[K, T]) evicted(key K, entry *memoEntry[T]) {
+	m.mu.Lock()
+	release := entry.claim()
+	m.mu.Unlock()
+	if release {
+		m.release(key, entry.value)
+	}
+}
+
+// release calls the eviction hook with an evicted instance, and closes it with MemoCloseOnEvict.
+func (m *Memo[K, T]) release(key K, value T) {
+	if m.onEvict != nil {
+		m.onEvict(key, value)
+	}
+	if closer, ok := any(value).(io.Closer); ok && m.options.closeOnEvict {
+		closer.Close()
+	}
+}
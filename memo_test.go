@@ -0,0 +1,254 @@
+package alice
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type tenantClient struct {
+	tenant string
+}
+
+func TestMemo(t *testing.T) {
+	created := 0
+	var evicted []string
+	memo := NewMemo(func(tenant string) *tenantClient {
+		created++
+		return &tenantClient{tenant: tenant}
+	}, func(tenant string, client *tenantClient) {
+		evicted = append(evicted, tenant)
+	})
+
+	a := memo.Get("a")
+	if memo.Get("a") != a || a.tenant != "a" {
+		t.Errorf("bad instance after Get(): got %v, expected %v", memo.Get("a"), a)
+	}
+	memo.Get("b")
+	if created != 2 || memo.Len() != 2 {
+		t.Errorf("bad number of instances after Get(): got %d created, %d memoized, expected 2", created,
+			memo.Len())
+	}
+
+	if !memo.Evict("a") || memo.Evict("c") {
+		t.Error("bad result after Evict()")
+	}
+	if memo.Get("a") == a {
+		t.Error("bad instance after Evict() and Get(): got the evicted one")
+	}
+	memo.Clear()
+	if memo.Len() != 0 || len(evicted) != 3 {
+		t.Errorf("bad state after Clear(): got %d memoized, %v evicted", memo.Len(), evicted)
+	}
+}
+
+func TestMemo_Concurrent(t *testing.T) {
+	var mu sync.Mutex
+	created := 0
+	memo := NewMemo(func(key int) int {
+		mu.Lock()
+		created++
+		mu.Unlock()
+		return key * 2
+	}, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if v := memo.Get(i % 5); v != (i%5)*2 {
+				t.Errorf("bad value after Get(): got %d, expected %d", v, (i%5)*2)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if created != 5 {
+		t.Errorf("bad number of created instances: got %d, expected %d", created, 5)
+	}
+}
+
+func TestMemo_Provided(t *testing.T) {
+	m := NewModule("clients").
+		Provide("TenantClients", func() *Memo[string, *tenantClient] {
+			return NewMemo(func(tenant string) *tenantClient {
+				return &tenantClient{tenant: tenant}
+			}, nil)
+		}).
+		Build()
+	c := CreateContainer(m)
+	clients := NewAccessor[*Memo[string, *tenantClient]](c, "TenantClients").Get()
+	if clients.Get("a") != clients.Get("a") {
+		t.Error("bad instance after Get() on provided memo")
+	}
+}
+
+type closableClient struct {
+	closed bool
+}
+
+func (c *closableClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestMemo_IdleTTL(t *testing.T) {
+	var evicted []string
+	memo := NewMemo(func(tenant string) *closableClient {
+		return &closableClient{}
+	}, func(tenant string, client *closableClient) {
+		evicted = append(evicted, tenant)
+	}, MemoIdleTTL(time.Minute), MemoCloseOnEvict())
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	memo.now = func() time.Time { return now }
+
+	a := memo.Get("a")
+	now = now.Add(40 * time.Second)
+	memo.Get("b")
+	now = now.Add(40 * time.Second)
+	memo.Get("b")
+	if !reflect.DeepEqual(evicted, []string{"a"}) || memo.Len() != 1 {
+		t.Errorf("bad evictions after Get() of idle instances: got %v, %d memoized, expected [a], 1", evicted,
+			memo.Len())
+	}
+	if !a.closed {
+		t.Error("evicted instance is expected to be closed with MemoCloseOnEvict()")
+	}
+
+	now = now.Add(2 * time.Minute)
+	memo.Sweep()
+	if !reflect.DeepEqual(evicted, []string{"a", "b"}) || memo.Len() != 0 {
+		t.Errorf("bad evictions after Sweep(): got %v, %d memoized, expected [a b], 0", evicted, memo.Len())
+	}
+}
+
+func TestMemo_MaxEntries(t *testing.T) {
+	var evicted []int
+	memo := NewMemo(func(key int) int {
+		return key
+	}, func(key int, value int) {
+		evicted = append(evicted, key)
+	}, MemoMaxEntries(2))
+
+	memo.Get(1)
+	memo.Get(2)
+	memo.Get(1)
+	memo.Get(3)
+	if !reflect.DeepEqual(evicted, []int{2}) || memo.Len() != 2 {
+		t.Errorf("bad evictions after Get() beyond max entries: got %v, %d memoized, expected [2], 2", evicted,
+			memo.Len())
+	}
+	memo.Evict(1)
+	memo.Get(4)
+	if !reflect.DeepEqual(evicted, []int{2, 1}) || memo.Len() != 2 {
+		t.Errorf("bad evictions after Evict() and Get(): got %v, %d memoized, expected [2 1], 2", evicted, memo.Len())
+	}
+}
+
+func TestMemo_CreatePanic(t *testing.T) {
+	fail := true
+	memo := NewMemo(func(key int) int {
+		if fail {
+			panic("create failed")
+		}
+		return key
+	}, nil)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "create failed" {
+				t.Errorf("bad panic after Get(): got %v, expected %q", r, "create failed")
+			}
+		}()
+		memo.Get(1)
+	}()
+	if memo.Len() != 0 {
+		t.Errorf("bad number of instances after Get() panicked: got %d, expected 0", memo.Len())
+	}
+	fail = false
+	if v := memo.Get(1); v != 1 {
+		t.Errorf("bad value after Get() panicked before: got %d, expected %d", v, 1)
+	}
+}
+
+func TestMemo_EvictCreating(t *testing.T) {
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	var clients []*closableClient
+	memo := NewMemo(func(tenant string) *closableClient {
+		client := &closableClient{}
+		clients = append(clients, client)
+		if len(clients) == 1 {
+			close(started)
+			<-unblock
+		}
+		return client
+	}, nil, MemoCloseOnEvict())
+
+	got := make(chan *closableClient)
+	go func() {
+		got <- memo.Get("a")
+	}()
+	<-started
+	evicted := make(chan bool)
+	go func() {
+		evicted <- memo.Evict("a")
+	}()
+	select {
+	case ok := <-evicted:
+		if !ok {
+			t.Error("bad result after Evict() of the instance being created: got false, expected true")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Evict() is expected not to wait for the instance being created")
+	}
+	close(unblock)
+	if client := <-got; client.closed || len(clients) != 2 || !clients[0].closed {
+		t.Errorf("bad instance after Get() evicted while being created: got closed %v, %d created, expected a new one",
+			client.closed, len(clients))
+	}
+}
+
+type countingClient struct {
+	closes int32
+}
+
+func (c *countingClient) Close() error {
+	atomic.AddInt32(&c.closes, 1)
+	return nil
+}
+
+func TestMemo_ConcurrentEvict(t *testing.T) {
+	var mu sync.Mutex
+	var clients []*countingClient
+	memo := NewMemo(func(key int) *countingClient {
+		client := &countingClient{}
+		mu.Lock()
+		clients = append(clients, client)
+		mu.Unlock()
+		return client
+	}, nil, MemoMaxEntries(3), MemoCloseOnEvict())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				memo.Get((i + j) % 5)
+				if j%5 == 0 {
+					memo.Evict(i % 5)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	memo.Clear()
+	for _, client := range clients {
+		if closes := atomic.LoadInt32(&client.closes); closes != 1 {
+			t.Fatalf("bad number of closes after Clear(): got %d, expected 1", closes)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+//go:build prometheus
+
+// Package metrics wires Prometheus collectors through alice containers. Instances of other modules implementing
+// prometheus.Collector are registered into a registry provided by the module, so registering a metric is a
+// consequence of being in the graph.
+//
+// The package depends on github.com/prometheus/client_golang, so it is only built with the "prometheus" build tag:
+//
+//	go build -tags prometheus ./...
+package metrics
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/magic003/alice"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Module provides a registry of all collectors defined in other modules, as the "MetricsRegistry" instance, and an
+// HTTP handler exposing them, as the "MetricsHandler" instance.
+//
+//	c := alice.CreateContainer(metrics.NewModule(), &StorageModule{}, &ServerModule{})
+//	http.Handle("/metrics", c.InstanceByName("MetricsHandler").(http.Handler))
+type Module struct {
+	alice.BaseModule
+	Collectors map[string]prometheus.Collector `alice:",map"`
+
+	once     sync.Once
+	registry *prometheus.Registry
+}
+
+// NewModule creates the metrics module.
+func NewModule() *Module {
+	return &Module{}
+}
+
+// MetricsRegistry returns the registry of the collectors, registered in the order of their instance names. It panics
+// if any collector fails to be registered, e.g. with a duplicated metric.
+func (m *Module) MetricsRegistry() *prometheus.Registry {
+	return m.collectedRegistry()
+}
+
+// MetricsHandler returns the HTTP handler exposing the metrics of the registry.
+func (m *Module) MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(m.collectedRegistry(), promhttp.HandlerOpts{})
+}
+
+// collectedRegistry creates the registry once, as both instances share it.
+func (m *Module) collectedRegistry() *prometheus.Registry {
+	m.once.Do(func() {
+		var names []string
+		for name := range m.Collectors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		m.registry = prometheus.NewRegistry()
+		for _, name := range names {
+			if err := m.registry.Register(m.Collectors[name]); err != nil {
+				panic(err)
+			}
+		}
+	})
+	return m.registry
+}
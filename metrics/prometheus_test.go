@@ -0,0 +1,40 @@
+//go:build prometheus
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/magic003/alice"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type storageModule struct {
+	alice.BaseModule
+}
+
+func (m *storageModule) QueriesTotal() prometheus.Counter {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "queries_total"})
+	counter.Inc()
+	return counter
+}
+
+func TestModule(t *testing.T) {
+	c := alice.CreateContainer(NewModule(), &storageModule{})
+	handler := c.InstanceByName("MetricsHandler").(http.Handler)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(w.Body.String(), "queries_total 1") {
+		t.Errorf("bad response from MetricsHandler: got %s, expected queries_total 1", w.Body.String())
+	}
+
+	registry := c.InstanceByName("MetricsRegistry").(*prometheus.Registry)
+	families, err := registry.Gather()
+	if err != nil || len(families) != 1 {
+		t.Errorf("bad result from Gather(): got %d families, err %v, expected 1", len(families), err)
+	}
+}
@@ -0,0 +1,53 @@
+package alice
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithMigrations returns an option which migrates the modules implementing MigratedModule while the container is
+// created, or on first use in lazy mode. A failed migration fails the creation like a failed instance method. A module
+// is migrated once, even if its dependencies are injected again by Rebuilder.Reset.
+func WithMigrations(ctx context.Context) Option {
+	return func(o *options) {
+		o.migrations = ctx
+	}
+}
+
+// Migrate only runs the migrations of the modules, in the order of the graph, and returns the first error. The
+// dependencies of the migrated modules are constructed, but no other instance is. It is intended to be run by a
+// separate command before deploying a new version:
+//
+//	if *migrateOnly {
+//		if err := alice.Migrate(ctx, modules...); err != nil {
+//			log.Fatal(err)
+//		}
+//		os.Exit(0)
+//	}
+func Migrate(ctx context.Context, modules ...Module) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(r)
+		}
+	}()
+	c := CreateContainerWithOptions(modules, WithLazy(), withForeground(), WithMigrations(ctx)).(*container)
+	for _, lm := range c.lazyModules {
+		if _, ok := lm.rm.m.(MigratedModule); ok {
+			c.injectLazyModule(lm)
+		}
+	}
+	return nil
+}
+
+// migrateModule migrates a module if it implements MigratedModule and migrations are enabled. It panics if the
+// migration fails.
+func (c *container) migrateModule(rm *reflectedModule) {
+	ctx := c.options.migrations
+	mm, ok := rm.m.(MigratedModule)
+	if ctx == nil || !ok {
+		return
+	}
+	if err := mm.Migrate(ctx); err != nil {
+		panic(fmt.Errorf("failed to migrate module %s: %w", rm.name, err))
+	}
+}
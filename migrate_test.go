@@ -0,0 +1,127 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type migrationLog struct {
+	events []string
+}
+
+type migrationDBModule struct {
+	BaseModule
+	log *migrationLog
+}
+
+func (m *migrationDBModule) Log() *migrationLog {
+	m.log.events = append(m.log.events, "construct Log")
+	return m.log
+}
+
+type migrationSchemaModule struct {
+	BaseModule
+	Log *migrationLog `alice:""`
+	err error
+}
+
+func (m *migrationSchemaModule) Migrate(ctx context.Context) error {
+	m.Log.events = append(m.Log.events, "migrate schema")
+	return m.err
+}
+
+func (m *migrationSchemaModule) Users() []string {
+	m.Log.events = append(m.Log.events, "construct Users")
+	return []string{"alice"}
+}
+
+type migrationReportModule struct {
+	BaseModule
+	Users []string      `alice:"Users"`
+	Log   *migrationLog `alice:""`
+}
+
+func (m *migrationReportModule) Report() string {
+	m.Log.events = append(m.Log.events, "construct Report")
+	return strings.Join(m.Users, ",")
+}
+
+type migrationIndexModule struct {
+	BaseModule
+	Log *migrationLog `alice:""`
+}
+
+func (m *migrationIndexModule) Index() string {
+	m.Log.events = append(m.Log.events, "construct Index")
+	return "index"
+}
+
+func (m *migrationIndexModule) BackgroundInstances() []string {
+	return []string{"Index"}
+}
+
+func TestWithMigrations(t *testing.T) {
+	log := &migrationLog{}
+	modules := []Module{&migrationReportModule{}, &migrationSchemaModule{}, &migrationDBModule{log: log}}
+	CreateContainerWithOptions(modules, WithMigrations(context.Background()))
+	expected := []string{"construct Log", "migrate schema", "construct Users", "construct Report"}
+	if !reflect.DeepEqual(log.events, expected) {
+		t.Errorf("bad events after CreateContainerWithOptions(): got %v, expected %v", log.events, expected)
+	}
+
+	log.events = nil
+	c := CreateContainerWithOptions(modules, WithMigrations(context.Background()), WithLazy())
+	c.InstanceByName("Report")
+	c.(Rebuilder).Reset("Users")
+	c.InstanceByName("Report")
+	expected = []string{"construct Log", "migrate schema", "construct Users", "construct Report", "construct Users",
+		"construct Report"}
+	if !reflect.DeepEqual(log.events, expected) {
+		t.Errorf("bad events after Reset() in lazy mode: got %v, expected %v", log.events, expected)
+	}
+
+	log.events = nil
+	CreateContainer(modules...)
+	expected = []string{"construct Log", "construct Users", "construct Report"}
+	if !reflect.DeepEqual(log.events, expected) {
+		t.Errorf("bad events after CreateContainer(): got %v, expected %v", log.events, expected)
+	}
+}
+
+func TestWithMigrations_Failure(t *testing.T) {
+	errMigration := errors.New("bad migration")
+	modules := []Module{&migrationSchemaModule{err: errMigration}, &migrationDBModule{log: &migrationLog{}}}
+	defer func() {
+		err, ok := recover().(error)
+		expected := "building module migrationSchemaModule: failed to migrate module migrationSchemaModule: bad migration"
+		if !ok || err.Error() != expected || !errors.Is(err, errMigration) {
+			t.Errorf("bad panic from CreateContainerWithOptions(): got %v, expected %s", err, expected)
+		}
+	}()
+	CreateContainerWithOptions(modules, WithMigrations(context.Background()))
+}
+
+func TestMigrate(t *testing.T) {
+	log := &migrationLog{}
+	err := Migrate(context.Background(), &migrationReportModule{}, &migrationSchemaModule{},
+		&migrationIndexModule{}, &migrationDBModule{log: log})
+	if err != nil {
+		t.Errorf("bad result from Migrate(): got %v, expected nil", err)
+	}
+	// the background instance would be constructed by now if it was started
+	time.Sleep(10 * time.Millisecond)
+	expected := []string{"construct Log", "migrate schema"}
+	if !reflect.DeepEqual(log.events, expected) {
+		t.Errorf("bad events after Migrate(): got %v, expected %v", log.events, expected)
+	}
+
+	err = Migrate(context.Background(), &migrationSchemaModule{err: errors.New("bad migration")},
+		&migrationDBModule{log: &migrationLog{}})
+	if err == nil || !strings.HasSuffix(err.Error(), "failed to migrate module migrationSchemaModule: bad migration") {
+		t.Errorf("bad result from Migrate(): got %v, expected migration error", err)
+	}
+}
@@ -1,5 +1,10 @@
 package alice
 
+import (
+	"context"
+	"reflect"
+)
+
 // Module is a marker interface for structs that defines how to initialize instances.
 type Module interface {
 	// IsModule indicates if this is a module.
@@ -27,3 +32,145 @@ type BaseModule struct{}
 func (b *BaseModule) IsModule() bool {
 	return true
 }
+
+// BackgroundModule is an optional interface a module could implement to construct some of its instances on a
+// background goroutine. The container doesn't wait for those instances during creation. It only blocks when an
+// instance is actually needed, either by a dependent module or by a retrieval API.
+type BackgroundModule interface {
+	// BackgroundInstances returns the names of the instances to be constructed in background.
+	BackgroundInstances() []string
+}
+
+// DescribedModule is an optional interface a module could implement to attach human-readable descriptions to its
+// instances. The descriptions are exposed by Container.Instances and the debug handler.
+type DescribedModule interface {
+	// Describe returns the descriptions keyed by instance names. Instances without description could be omitted. The
+	// empty name describes the module itself.
+	Describe() map[string]string
+}
+
+// DeprecatedModule is an optional interface a module could implement to mark itself or some of its instances
+// deprecated. A warning is logged when the module is used to create a container, and the deprecations are exposed by
+// Container.Instances.
+type DeprecatedModule interface {
+	// Deprecated returns the replacement hints keyed by the names of deprecated instances. The empty name marks the
+	// whole module deprecated. A hint could be empty if there is no replacement.
+	Deprecated() map[string]string
+}
+
+// GroupedModule is an optional interface a module could implement to contribute some of its instances to groups. A
+// dependency field of slice type tagged by `alice:"group=Name"` is filled by all instances contributed to the group,
+// ordered by priority.
+type GroupedModule interface {
+	// Groups returns the contributions keyed by instance names.
+	Groups() map[string]Contribution
+}
+
+// Contribution declares that an instance belongs to a group. Instances with lower priority come first in the
+// injected slice. Instances with the same priority keep the order of the modules and their instance methods.
+type Contribution struct {
+	Group    string
+	Priority int
+}
+
+// FallbackModule is an optional interface a module could implement to mark some of its instances as fallbacks. A
+// fallback instance is removed if any other instance, which is not a fallback, has the same name, or a type assignable
+// to the type of the fallback. It lets
+// library modules provide sensible defaults, like a no-op logger, without clashing with the real implementations of
+// the application.
+type FallbackModule interface {
+	// Fallbacks returns the names of the fallback instances.
+	Fallbacks() []string
+}
+
+// SynchronizedModule is an optional interface a module could implement to mark some of its instances synchronized.
+// A synchronized instance is wrapped in a proxy guarded by a mutex, so a legacy implementation which is not safe for
+// concurrent use could be shared. The instances must be of function or interface types, and the proxies are created
+// by the ProxyFactory set by WithProxyFactory. The container creation fails if the factory can't proxy them, e.g. an
+// interface without a constructor registered to a GeneratedProxyFactory.
+type SynchronizedModule interface {
+	// Synchronized returns the names of the synchronized instances.
+	Synchronized() []string
+}
+
+// DegradableModule is an optional interface a module could implement to mark some of its instances non-critical.
+// With WithDegradedStartup, a failure constructing a non-critical instance is recorded instead of aborting the
+// container creation, and a substitute is used.
+type DegradableModule interface {
+	// NonCritical returns the substitutes keyed by the names of non-critical instances. A substitute is a function
+	// without parameters returning a value assignable to the instance type, like a no-op implementation, or nil to use
+	// the zero value.
+	NonCritical() map[string]interface{}
+}
+
+// SandboxedModule is an optional interface a module could implement to run its instance methods in a sandbox of
+// environment variables and working directory, so legacy constructors relying on the ambient environment get
+// isolated, reproducible inputs.
+type SandboxedModule interface {
+	// Sandbox returns the sandbox of the instance methods.
+	Sandbox() Sandbox
+}
+
+// NilableModule is an optional interface a module could implement to allow some of its instances to be nil, like an
+// optional client which is not configured. Other instances returned as nil pointers or nil interfaces fail the
+// container creation, as injecting them causes nil pointer panics far away from the provider.
+type NilableModule interface {
+	// Nilable returns the names of the instances which could be nil.
+	Nilable() []string
+}
+
+// IsolatedModule is an optional interface a module could implement to construct some of its instances on dedicated
+// goroutines, e.g. constructors recursing deeply or relying on the OS thread.
+type IsolatedModule interface {
+	// Isolated returns the isolations keyed by the instance names.
+	Isolated() map[string]Isolation
+}
+
+// ConditionalModule is an optional interface a module could implement to restrict some of its instances to platforms
+// or capabilities. See Condition.
+type ConditionalModule interface {
+	// Conditions returns the conditions keyed by the instance names.
+	Conditions() map[string]Condition
+}
+
+// VerifiedModule is an optional interface a module could implement to check its environmental preconditions, like
+// the required configuration keys being present, when it is validated by Validate.
+type VerifiedModule interface {
+	// Verify checks the preconditions of the module. c is a lazy container of copies of the validated modules, so
+	// only the instances retrieved from it are constructed, even background ones, and the validated modules are not
+	// injected, except for the dependency targets of built modules. The container is stopped after all modules are
+	// verified.
+	Verify(c Container) error
+}
+
+// TestVariantModule is an optional interface a module could implement to provide a deterministic variant of itself
+// for tests, like a fixed clock or a seeded random source. The containers created by the alicetest package use the
+// variant instead of the module.
+type TestVariantModule interface {
+	// TestVariant returns the module used in tests.
+	TestVariant() Module
+}
+
+// ViewedModule is an optional interface a module could implement to register some of its instances by exported
+// views, usually interfaces, instead of the types returned by the instance methods. It lets instances of unexported
+// types be associated by type from other packages, including in strict mode, without exporting the internals.
+type ViewedModule interface {
+	// Views returns the view types keyed by the instance names. The type returned by an instance method must be
+	// assignable to its view.
+	Views() map[string]reflect.Type
+}
+
+// NamespacedModule is an optional interface a module could implement to customize its namespace when the container
+// is created with WithNamespaces.
+type NamespacedModule interface {
+	// Namespace returns the namespace of the instances of the module.
+	Namespace() string
+}
+
+// MigratedModule is an optional interface a module could implement to migrate the resources it owns, like database
+// schemas. With the WithMigrations option, or by Migrate, a module is migrated after its dependencies are injected
+// and before its instances are constructed, so the migrations run in the order of the graph.
+type MigratedModule interface {
+	// Migrate migrates the resources of the module.
+	Migrate(ctx context.Context) error
+}
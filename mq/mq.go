@@ -0,0 +1,170 @@
+// Package mq runs message queue consumers wired by alice containers. Feature modules define handler instances, and
+// the runner module subscribes them when the container starts and drains them when it stops. Message queue clients,
+// like Kafka or NATS, are adapted by implementing Source, so the package doesn't depend on any of them.
+//
+//	c := alice.CreateContainer(&KafkaModule{}, mq.NewModule(), &OrderModule{})
+//	err := c.(alice.Lifecycle).Start(ctx)
+package mq
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/magic003/alice"
+)
+
+// Message is a message received from a topic.
+type Message struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// Handler handles the messages of a topic. Instances of other modules implementing Handler are collected by the
+// runner module.
+type Handler interface {
+	// Topic returns the topic to subscribe.
+	Topic() string
+	// Handle handles a message. The error is logged, and doesn't stop the consumption.
+	Handle(ctx context.Context, msg Message) error
+}
+
+// Source is a message queue client, adapting Kafka, NATS or others.
+type Source interface {
+	// Subscribe starts delivering the messages of the topic to the returned channel until the context is done. The
+	// channel must be closed once the delivery stops.
+	Subscribe(ctx context.Context, topic string) (<-chan Message, error)
+}
+
+// Module provides the "ConsumerRunner" instance running the handlers defined in other modules. It depends on a
+// Source by type. The concurrency of each handler, keyed by the handler instance name, could be provided by an
+// instance named "ConsumerConcurrency" of type map[string]int. A handler not configured is run by one goroutine.
+type Module struct {
+	alice.BaseModule
+	Source   Source             `alice:""`
+	Handlers map[string]Handler `alice:",map"`
+}
+
+// NewModule creates the runner module.
+func NewModule() *Module {
+	return &Module{}
+}
+
+// RunnerParams are the optional dependencies of the runner.
+type RunnerParams struct {
+	alice.Params
+	Concurrency map[string]int `alice:"ConsumerConcurrency,optional"`
+	Logger      *slog.Logger   `alice:",optional"`
+}
+
+// ConsumerRunner returns the runner of the handlers.
+func (m *Module) ConsumerRunner(p RunnerParams) *Runner {
+	logger := p.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Runner{
+		source:      m.Source,
+		handlers:    m.Handlers,
+		concurrency: p.Concurrency,
+		logger:      logger,
+	}
+}
+
+// Runner subscribes the handlers on Start and drains them on Stop. It implements alice.Starter and alice.Stopper.
+type Runner struct {
+	source      Source
+	handlers    map[string]Handler
+	concurrency map[string]int
+	logger      *slog.Logger
+
+	cancel       context.CancelFunc
+	cancelHandle context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// Start subscribes the topics of all handlers, in the order of the handler names, and starts consuming. The
+// consumption outlives ctx, which only bounds the subscriptions, until Stop is called.
+func (r *Runner) Start(ctx context.Context) error {
+	consumeCtx, cancelConsume := context.WithCancel(context.Background())
+	handleCtx, cancelHandle := context.WithCancel(context.Background())
+	cancel := func() {
+		cancelConsume()
+		cancelHandle()
+	}
+	r.cancel, r.cancelHandle = cancelConsume, cancelHandle
+
+	var names []string
+	for name := range r.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			cancel()
+			return err
+		}
+		handler := r.handlers[name]
+		messages, err := r.source.Subscribe(consumeCtx, handler.Topic())
+		if err != nil {
+			cancel()
+			return fmt.Errorf("failed to subscribe topic %s for handler %s: %w", handler.Topic(), name, err)
+		}
+		n := r.concurrency[name]
+		if n < 1 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			r.wg.Add(1)
+			go r.consume(handleCtx, name, handler, messages)
+		}
+	}
+	return nil
+}
+
+// Stop cancels the subscriptions and waits until the messages delivered are handled. If ctx is done first, the
+// context passed to the handlers is cancelled and ctx.Err() is returned.
+func (r *Runner) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	drained := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		r.cancelHandle()
+		return nil
+	case <-ctx.Done():
+		r.cancelHandle()
+		return ctx.Err()
+	}
+}
+
+// consume handles the messages until the channel is closed. A panicking handler is recovered and logged.
+func (r *Runner) consume(ctx context.Context, name string, handler Handler, messages <-chan Message) {
+	defer r.wg.Done()
+	for msg := range messages {
+		r.handle(ctx, name, handler, msg)
+	}
+}
+
+func (r *Runner) handle(ctx context.Context, name string, handler Handler, msg Message) {
+	defer func() {
+		if p := recover(); p != nil {
+			r.logger.Error("mq: handler panicked", "handler", name, "topic", msg.Topic, "panic", p)
+		}
+	}()
+	if err := handler.Handle(ctx, msg); err != nil {
+		r.logger.Error("mq: failed to handle message", "handler", name, "topic", msg.Topic, "error", err)
+	}
+}
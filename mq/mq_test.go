@@ -0,0 +1,172 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/magic003/alice"
+)
+
+// chanSource delivers the messages already queued for a topic, then blocks until the context is done.
+type chanSource struct {
+	topics map[string]chan Message
+}
+
+func (s *chanSource) Subscribe(ctx context.Context, topic string) (<-chan Message, error) {
+	queued, ok := s.topics[topic]
+	if !ok {
+		return nil, errors.New("unknown topic")
+	}
+	out := make(chan Message)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case msg := <-queued:
+				select {
+				case out <- msg:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+type sourceModule struct {
+	alice.BaseModule
+	source *chanSource
+}
+
+func (m *sourceModule) Queue() Source {
+	return m.source
+}
+
+func (m *sourceModule) Logger() *slog.Logger {
+	return discardLogger
+}
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+type countingHandler struct {
+	topic string
+	mu    sync.Mutex
+	keys  []string
+	done  chan struct{}
+	want  int
+}
+
+func (h *countingHandler) Topic() string {
+	return h.topic
+}
+
+func (h *countingHandler) Handle(ctx context.Context, msg Message) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.keys = append(h.keys, string(msg.Key))
+	if len(h.keys) == h.want {
+		close(h.done)
+	}
+	if string(msg.Key) == "bad" {
+		return errors.New("bad message")
+	}
+	if string(msg.Key) == "panic" {
+		panic("bad message")
+	}
+	return nil
+}
+
+type ordersModule struct {
+	alice.BaseModule
+	handler *countingHandler
+}
+
+func (m *ordersModule) OrderHandler() Handler {
+	return m.handler
+}
+
+func (m *ordersModule) ConsumerConcurrency() map[string]int {
+	return map[string]int{"OrderHandler": 2}
+}
+
+func TestRunner(t *testing.T) {
+	queued := make(chan Message, 4)
+	for _, key := range []string{"a", "bad", "panic", "b"} {
+		queued <- Message{Topic: "orders", Key: []byte(key)}
+	}
+	source := &chanSource{topics: map[string]chan Message{"orders": queued}}
+	handler := &countingHandler{topic: "orders", done: make(chan struct{}), want: 4}
+
+	c := alice.CreateContainer(&sourceModule{source: source}, &ordersModule{handler: handler}, NewModule())
+	if err := c.(alice.Lifecycle).Start(context.Background()); err != nil {
+		t.Fatalf("bad result from Start(): got %v, expected nil", err)
+	}
+	select {
+	case <-handler.done:
+	case <-time.After(time.Second):
+		t.Fatalf("bad messages handled after Start(): got %v, expected 4 messages", handler.keys)
+	}
+
+	if err := c.(alice.Lifecycle).Stop(context.Background()); err != nil {
+		t.Errorf("bad result from Stop(): got %v, expected nil", err)
+	}
+	runner := c.InstanceByName("ConsumerRunner").(*Runner)
+	if runner.concurrency["OrderHandler"] != 2 {
+		t.Errorf("bad concurrency after CreateContainer(): got %v, expected 2", runner.concurrency["OrderHandler"])
+	}
+}
+
+type blockingHandler struct {
+	started chan struct{}
+}
+
+func (h *blockingHandler) Topic() string {
+	return "slow"
+}
+
+func (h *blockingHandler) Handle(ctx context.Context, msg Message) error {
+	close(h.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestRunner_StopTimeout(t *testing.T) {
+	queued := make(chan Message, 1)
+	queued <- Message{Topic: "slow"}
+	handler := &blockingHandler{started: make(chan struct{})}
+	r := &Runner{
+		source:   &chanSource{topics: map[string]chan Message{"slow": queued}},
+		handlers: map[string]Handler{"SlowHandler": handler},
+		logger:   discardLogger,
+	}
+	if err := r.Start(context.Background()); err != nil {
+		t.Fatalf("bad result from Start(): got %v, expected nil", err)
+	}
+	<-handler.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("bad result from Stop(): got %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRunner_SubscribeError(t *testing.T) {
+	r := &Runner{
+		source:   &chanSource{},
+		handlers: map[string]Handler{"OrderHandler": &countingHandler{topic: "orders"}},
+	}
+	err := r.Start(context.Background())
+	expected := "failed to subscribe topic orders for handler OrderHandler: unknown topic"
+	if err == nil || err.Error() != expected {
+		t.Errorf("bad result from Start(): got %v, expected %s", err, expected)
+	}
+}
@@ -0,0 +1,40 @@
+package alice
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type mistypedNamedModule struct {
+	BaseModule
+	D5 *D5Impl2 `alice:"D5"`
+}
+
+type mistypedInterfaceModule struct {
+	BaseModule
+	D1 *D2Impl `alice:"D1"`
+}
+
+func TestNamedDependencyType(t *testing.T) {
+	provider := NewModule("provider").
+		Provide("D5", func() *D5Impl { return &D5Impl{} }).
+		Build()
+	err := Validate(provider, &mistypedNamedModule{})
+	expected := "dependency name mistypedNamedModule.D5 expects type *alice.D5Impl2, " +
+		"but instance provider.D5 has type *alice.D5Impl"
+	if err == nil || err.Error() != expected {
+		t.Errorf("bad error after Validate() on mistyped named dependency: got %v, expected %s", err, expected)
+	}
+
+	defer func() {
+		r := recover()
+		expected := "dependency name mistypedInterfaceModule.D1 expects type *alice.D2Impl, " +
+			"but instance has type *alice.D1Impl"
+		if r == nil || !strings.Contains(fmt.Sprint(r), expected) {
+			t.Errorf("bad panic after CreateContainer() on mistyped named dependency: got %v, expected %s", r,
+				expected)
+		}
+	}()
+	CreateContainer(&M1{}, &mistypedInterfaceModule{})
+}
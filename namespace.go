@@ -0,0 +1,72 @@
+package alice
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WithNamespaces returns an option which namespaces instance names by module, e.g. the instance "DB" of module
+// "storage" is named as "storage.DB". The namespace is the module name, or the one returned by Namespace if the
+// module implements NamespacedModule. Named dependencies and InstanceByName could use either the fully qualified
+// name, or the short name if it is unambiguous.
+func WithNamespaces() Option {
+	return func(o *options) {
+		o.namespaces = true
+	}
+}
+
+// applyNamespace prefixes the instance names of a module with its namespace.
+func applyNamespace(rm *reflectedModule) {
+	namespace := rm.name
+	if nm, ok := rm.m.(NamespacedModule); ok {
+		namespace = nm.Namespace()
+	}
+	for _, instance := range rm.instances {
+		instance.shortName = instance.name
+		instance.name = namespace + "." + instance.name
+	}
+}
+
+// shortNames maps short instance names to the fully qualified names. The namespaces and the short names are kept
+// apart, as either could contain dots.
+type shortNames struct {
+	// qualified contains the fully qualified names.
+	qualified map[string]bool
+	// short maps the short names to the fully qualified names.
+	short map[string][]string
+}
+
+// newShortNames creates the short names of the instances of modules.
+func newShortNames(rms []*reflectedModule) *shortNames {
+	names := &shortNames{
+		qualified: make(map[string]bool),
+		short:     make(map[string][]string),
+	}
+	for _, rm := range rms {
+		for _, instance := range rm.instances {
+			names.qualified[instance.name] = true
+			names.short[instance.shortName] = append(names.short[instance.shortName], instance.name)
+		}
+	}
+	return names
+}
+
+// qualify returns the fully qualified name of a name, which is either fully qualified already or short. A name which
+// is neither is returned as it is, so it is reported as not found by the caller. It returns error if the short name
+// is ambiguous.
+func (s *shortNames) qualify(name string) (string, error) {
+	if s.qualified[name] {
+		return name, nil
+	}
+	qualified := s.short[name]
+	if len(qualified) == 0 {
+		return name, nil
+	}
+	if len(qualified) > 1 {
+		sorted := append([]string(nil), qualified...)
+		sort.Strings(sorted)
+		return "", fmt.Errorf("short instance name %s is ambiguous: %s", name, strings.Join(sorted, ", "))
+	}
+	return qualified[0], nil
+}
@@ -0,0 +1,143 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+type StorageModule struct {
+	BaseModule
+}
+
+func (m *StorageModule) Namespace() string {
+	return "storage"
+}
+
+func (m *StorageModule) DB() D1 {
+	return &D1Impl{}
+}
+
+func (m *StorageModule) Table() string {
+	return "table"
+}
+
+type CacheModule struct {
+	BaseModule
+}
+
+func (m *CacheModule) Namespace() string {
+	return "cache"
+}
+
+func (m *CacheModule) DB() D2 {
+	return &D2Impl{}
+}
+
+type NamespaceConsumerModule struct {
+	BaseModule
+	DB    D2     `alice:"cache.DB"`
+	Table string `alice:"Table"`
+}
+
+type AmbiguousConsumerModule struct {
+	BaseModule
+	DB D1 `alice:"DB"`
+}
+
+func TestWithNamespaces(t *testing.T) {
+	consumer := &NamespaceConsumerModule{}
+	c := CreateContainerWithOptions([]Module{&StorageModule{}, &CacheModule{}, consumer}, WithNamespaces())
+
+	if !reflect.DeepEqual(consumer.DB, &D2Impl{}) {
+		t.Errorf("bad consumer.DB after CreateContainerWithOptions(): got %v, expected %v", consumer.DB, &D2Impl{})
+	}
+	if consumer.Table != "table" {
+		t.Errorf("bad consumer.Table after CreateContainerWithOptions(): got %s, expected %s", consumer.Table, "table")
+	}
+	if db := c.InstanceByName("storage.DB"); !reflect.DeepEqual(db, &D1Impl{}) {
+		t.Errorf("bad instance from InstanceByName(): got %v, expected %v", db, &D1Impl{})
+	}
+	if table := c.InstanceByName("Table"); table != "table" {
+		t.Errorf("bad instance from InstanceByName(): got %v, expected %s", table, "table")
+	}
+}
+
+func TestWithNamespaces_DefaultNamespace(t *testing.T) {
+	c := CreateContainerWithOptions([]Module{&M1{}}, WithNamespaces())
+	c.InstanceByName("M1.D1")
+}
+
+func TestWithNamespaces_AmbiguousShortName(t *testing.T) {
+	err := ValidateWithOptions([]Module{&StorageModule{}, &CacheModule{}, &AmbiguousConsumerModule{}}, WithNamespaces())
+	if err == nil {
+		t.Error("expect error after ValidateWithOptions() of ambiguous short name")
+	}
+	t.Log(err.Error())
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected panic for InstanceByName() on ambiguous short name")
+		} else {
+			t.Log(r)
+		}
+	}()
+	c := CreateContainerWithOptions([]Module{&StorageModule{}, &CacheModule{}}, WithNamespaces())
+	c.InstanceByName("DB")
+}
+
+type dottedNamespaceModule struct {
+	BaseModule
+}
+
+func (m *dottedNamespaceModule) Namespace() string {
+	return "payments.v2"
+}
+
+func (m *dottedNamespaceModule) Ledger() D5 {
+	return &D5Impl{}
+}
+
+type dottedConsumerModule struct {
+	BaseModule
+	Ledger D5 `alice:"Ledger"`
+	Stripe D3 `alice:"payment.stripe"`
+}
+
+func TestWithNamespaces_Dots(t *testing.T) {
+	plugins := NewModule("plugins").Provide("payment.stripe", func() D3 { return &D3Impl{} }).Build()
+	consumer := &dottedConsumerModule{}
+	c := CreateContainerWithOptions([]Module{&dottedNamespaceModule{}, plugins, consumer}, WithNamespaces())
+
+	if consumer.Ledger == nil || consumer.Stripe == nil {
+		t.Errorf("bad consumer after CreateContainerWithOptions(): got %+v, expected the short names qualified",
+			consumer)
+	}
+	for _, name := range []string{"Ledger", "payments.v2.Ledger", "payment.stripe", "plugins.payment.stripe"} {
+		if _, err := c.ResolveByName(name); err != nil {
+			t.Errorf("bad error after ResolveByName(%s): got %v, expected nil", name, err)
+		}
+	}
+	if _, err := c.ResolveByName("v2.Ledger"); err == nil {
+		t.Error("expected error for a partial namespace")
+	}
+}
+
+func TestWithNamespaces_BuiltModuleReused(t *testing.T) {
+	names := []string{"Table"}
+	var table string
+	var tables []string
+	consumer := NewModule("consumer").
+		RequireNamed("Table", &table).
+		RequireNames(names, &tables).
+		Build()
+
+	CreateContainerWithOptions([]Module{&StorageModule{}, consumer}, WithNamespaces())
+	if names[0] != "Table" {
+		t.Errorf("bad names after CreateContainerWithOptions(): got %v, expected %v", names, []string{"Table"})
+	}
+	table, tables = "", nil
+	CreateContainer(NewModule("storage").Provide("Table", func() string { return "other" }).Build(), consumer)
+	if table != "other" || !reflect.DeepEqual(tables, []string{"other"}) {
+		t.Errorf("bad dependencies in second container: got %q and %v, expected %q", table, tables, "other")
+	}
+}
@@ -0,0 +1,110 @@
+package alice
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NamingInfo is the information available to a NamingStrategy when naming an instance.
+type NamingInfo struct {
+	// PkgPath is the import path of the package defining the module.
+	PkgPath string
+	// Module is the name of the module.
+	Module string
+	// Method is the name of the instance method.
+	Method string
+}
+
+// NamingStrategy decides the instance names from the instance methods. It is applied uniformly to all modules
+// defined as structs when they are reflected. Instances of modules created by NewModule and Values keep their
+// explicit names. Named dependencies must use the names produced by the strategy.
+type NamingStrategy interface {
+	// InstanceName returns the name of the instance defined by a method.
+	InstanceName(info NamingInfo) string
+}
+
+// NamingStrategyFunc is a function implementing the NamingStrategy interface.
+type NamingStrategyFunc func(info NamingInfo) string
+
+// InstanceName calls the function.
+func (f NamingStrategyFunc) InstanceName(info NamingInfo) string {
+	return f(info)
+}
+
+// WithNamingStrategy returns an option which sets the naming strategy of instances. By default, the method name is
+// used as the instance name.
+func WithNamingStrategy(strategy NamingStrategy) Option {
+	return func(o *options) {
+		o.namingStrategy = strategy
+	}
+}
+
+// LowerCamelNaming is a naming strategy which lower cases the first letter of the method name, e.g. "UserService"
+// becomes "userService".
+var LowerCamelNaming NamingStrategy = NamingStrategyFunc(func(info NamingInfo) string {
+	r, size := utf8.DecodeRuneInString(info.Method)
+	return string(unicode.ToLower(r)) + info.Method[size:]
+})
+
+// PackagePrefixNaming is a naming strategy which prefixes the method name with the last element of the package
+// path, e.g. "UserService" in package "example/user" becomes "user.UserService".
+var PackagePrefixNaming NamingStrategy = NamingStrategyFunc(func(info NamingInfo) string {
+	pkg := info.PkgPath
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	if pkg == "" {
+		return info.Method
+	}
+	return pkg + "." + info.Method
+})
+
+// TrimNaming returns a naming strategy which removes the first matched prefix and suffix from the method name, e.g.
+// with prefix "Provide", "ProvideUserService" becomes "UserService".
+func TrimNaming(prefixes []string, suffixes []string) NamingStrategy {
+	return NamingStrategyFunc(func(info NamingInfo) string {
+		name := info.Method
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+				name = name[len(prefix):]
+				break
+			}
+		}
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+				name = name[:len(name)-len(suffix)]
+				break
+			}
+		}
+		return name
+	})
+}
+
+// ChainNaming returns a naming strategy applying the strategies in order, each one to the result of the previous
+// one. For example, ChainNaming(TrimNaming([]string{"Provide"}, nil), LowerCamelNaming) names the method
+// "ProvideUserService" as "userService".
+func ChainNaming(strategies ...NamingStrategy) NamingStrategy {
+	return NamingStrategyFunc(func(info NamingInfo) string {
+		for _, strategy := range strategies {
+			info.Method = strategy.InstanceName(info)
+		}
+		return info.Method
+	})
+}
+
+// applyNamingStrategy renames the instances of a module reflected from a struct.
+func applyNamingStrategy(strategy NamingStrategy, rm *reflectedModule) {
+	if _, ok := rm.m.(*builtModule); ok {
+		return
+	}
+	pkgPath := reflect.TypeOf(rm.m).Elem().PkgPath()
+	for _, instance := range rm.instances {
+		instance.name = strategy.InstanceName(NamingInfo{
+			PkgPath: pkgPath,
+			Module:  rm.name,
+			Method:  instance.name,
+		})
+	}
+}
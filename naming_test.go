@@ -0,0 +1,53 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+type ProviderModule struct {
+	BaseModule
+}
+
+func (m *ProviderModule) ProvideUserService() D1 {
+	return &D1Impl{}
+}
+
+type ProviderConsumerModule struct {
+	BaseModule
+	D1 D1 `alice:"userService"`
+}
+
+func TestNamingStrategies(t *testing.T) {
+	tests := []struct {
+		strategy NamingStrategy
+		method   string
+		expected string
+	}{
+		{LowerCamelNaming, "UserService", "userService"},
+		{PackagePrefixNaming, "UserService", "user.UserService"},
+		{TrimNaming([]string{"Provide", "New"}, []string{"Impl"}), "NewUserServiceImpl", "UserService"},
+		{TrimNaming([]string{"Provide"}, nil), "Provide", "Provide"},
+		{ChainNaming(TrimNaming([]string{"Provide"}, nil), LowerCamelNaming), "ProvideUserService", "userService"},
+	}
+	for _, test := range tests {
+		info := NamingInfo{PkgPath: "example/user", Module: "UserModule", Method: test.method}
+		if actual := test.strategy.InstanceName(info); actual != test.expected {
+			t.Errorf("bad instance name of %s: got %s, expected %s", test.method, actual, test.expected)
+		}
+	}
+}
+
+func TestWithNamingStrategy(t *testing.T) {
+	consumer := &ProviderConsumerModule{}
+	built := NewModule("built").Provide("ProvideD2", func() D2 { return &D2Impl{} }).Build()
+	c := CreateContainerWithOptions([]Module{&ProviderModule{}, consumer, built},
+		WithNamingStrategy(ChainNaming(TrimNaming([]string{"Provide"}, nil), LowerCamelNaming)))
+
+	if !reflect.DeepEqual(consumer.D1, &D1Impl{}) {
+		t.Errorf("bad consumer.D1 after CreateContainerWithOptions(): got %v, expected %v", consumer.D1, &D1Impl{})
+	}
+	c.InstanceByName("userService")
+	// explicit names are kept
+	c.InstanceByName("ProvideD2")
+}
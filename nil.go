@@ -0,0 +1,53 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithNilInstanceWarnings returns an option which logs a warning for an instance returned as a nil pointer or a nil
+// interface, instead of failing the container creation. It eases adopting the check in existing code bases.
+func WithNilInstanceWarnings() Option {
+	return func(o *options) {
+		o.nilWarnings = true
+	}
+}
+
+// markNilableInstances marks the instances with the specified names nilable.
+func markNilableInstances(moduleName string, instances []*instanceMethod, names []string) error {
+	for _, name := range names {
+		instance := findInstanceMethod(instances, name)
+		if instance == nil {
+			return fmt.Errorf("nilable instance %s.%s is not defined", moduleName, name)
+		}
+		instance.nilable = true
+	}
+	return nil
+}
+
+// checkNil panics if an instance not marked nilable is a nil pointer or a nil interface, or logs a warning with
+// WithNilInstanceWarnings.
+func (c *container) checkNil(im *instanceMethod, instance interface{}) {
+	if im.nilable || !isNilInstance(instance) {
+		return
+	}
+	moduleName := ""
+	if rm := c.moduleOf(im); rm != nil {
+		moduleName = rm.name
+	}
+	if c.options.nilWarnings {
+		c.options.logger.Warn("alice: instance is nil", "module", moduleName, "instance", im.name, "type", im.tp)
+		return
+	}
+	panic(fmt.Errorf("instance %s.%s of type %s is nil, which needs to be marked nilable if intended", moduleName,
+		im.name, im.tp))
+}
+
+// isNilInstance checks if an instance is a nil interface or a typed nil pointer.
+func isNilInstance(instance interface{}) bool {
+	if instance == nil {
+		return true
+	}
+	v := reflect.ValueOf(instance)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
@@ -0,0 +1,76 @@
+package alice
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type nilModule struct {
+	BaseModule
+}
+
+func (m *nilModule) D1() D1 {
+	return nil
+}
+
+func (m *nilModule) D5() *D5Impl {
+	return nil
+}
+
+type nilableModule struct {
+	nilModule
+}
+
+func (m *nilableModule) Nilable() []string {
+	return []string{"D1", "D5"}
+}
+
+func TestCheckNil(t *testing.T) {
+	for _, m := range []Module{
+		&nilModule{},
+		NewModule("built").Provide("D2", func() D2 { return (*D2Impl)(nil) }).Build(),
+	} {
+		func() {
+			defer func() {
+				r := recover()
+				if r == nil {
+					t.Errorf("expected panic for nil instance of module %T", m)
+				} else if !strings.Contains(r.(error).Error(), "is nil") {
+					t.Errorf("bad panic for nil instance: got %v, expected the nil instance identified", r)
+				}
+			}()
+			CreateContainer(m)
+		}()
+	}
+}
+
+func TestCheckNil_Nilable(t *testing.T) {
+	c := CreateContainer(&nilableModule{})
+	if d1 := c.InstanceByName("D1"); d1 != nil {
+		t.Errorf("bad instance after InstanceByName(): got %v, expected nil", d1)
+	}
+
+	built := NewModule("built").Provide("D2", func() D2 { return nil }).AllowNil("D2").Build()
+	if err := Validate(built); err != nil {
+		t.Errorf("bad error after Validate(): got %v, expected nil", err)
+	}
+	CreateContainer(built)
+
+	if err := Validate(NewModule("built").AllowNil("Undefined").Build()); err == nil {
+		t.Error("expected error for undefined nilable instance")
+	}
+}
+
+func TestCheckNil_Warnings(t *testing.T) {
+	var buf bytes.Buffer
+	c := CreateContainerWithOptions([]Module{&nilModule{}}, WithNilInstanceWarnings(),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+	if d5 := c.InstanceByName("D5"); d5.(*D5Impl) != nil {
+		t.Errorf("bad instance after InstanceByName(): got %v, expected nil", d5)
+	}
+	if !strings.Contains(buf.String(), `"alice: instance is nil" module=nilModule instance=D5`) {
+		t.Errorf("bad warnings after CreateContainer(): got %q, expected the nil instance D5", buf.String())
+	}
+}
@@ -0,0 +1,107 @@
+package alice
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"reflect"
+)
+
+// Option customizes the behavior of a container. Options are provided when creating the container.
+type Option func(*options)
+
+// options contains the configurations of a container.
+type options struct {
+	lazy            bool
+	warmParallelism int
+	warmProgress    WarmProgress
+	instrumentation Instrumentation
+	strict          bool
+	seed            []byte
+	namingStrategy  NamingStrategy
+	namespaces      bool
+	logger          *slog.Logger
+	rules           []Rule
+	conflictPolicy  ConflictPolicy
+	planOutput      io.Writer
+	pinnedPlan      []byte
+	featureFlags    FeatureFlags
+	contextual      map[reflect.Type]ContextualProvider
+	injectionHooks  []InjectionHook
+	postProcessors  []PostProcessor
+	scopeTracing    bool
+	migrations      context.Context
+	budget          *StartupBudget
+	failurePolicy   FailurePolicy
+	stopTimeouts    *StopTimeouts
+	proxyFactory    ProxyFactory
+	degraded        bool
+	nilWarnings     bool
+	failureDump     func(data []byte) error
+	capabilities    []string
+	errorFormatter  ErrorFormatter
+	adapters        []interface{}
+	foreground      bool
+}
+
+// WarmProgress is a callback invoked by Container.Warm after each instance is constructed. warmed is the number of
+// instances constructed so far, and total is the number of instances to be warmed.
+type WarmProgress func(name string, warmed int, total int)
+
+// newOptions creates options with default values and applies the specified options.
+func newOptions(opts ...Option) options {
+	o := options{
+		warmParallelism: 1,
+		logger:          slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLazy returns an option which makes the container construct instances lazily. The module graph is still
+// validated during creation, but an instance is only constructed when it is retrieved, needed by another instance,
+// or warmed by Container.Warm. Concurrent first retrievals of an instance construct it once: the other goroutines wait
+// for the construction in flight, and observe the instance with its module dependencies injected, or its failure.
+func WithLazy() Option {
+	return func(o *options) {
+		o.lazy = true
+	}
+}
+
+// WithWarmParallelism returns an option which sets the number of instances Container.Warm constructs in parallel.
+// The default value is 1.
+func WithWarmParallelism(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.warmParallelism = n
+		}
+	}
+}
+
+// WithWarmProgress returns an option which sets the progress callback of Container.Warm. The callback could be
+// invoked from multiple goroutines if the warm parallelism is larger than 1.
+func WithWarmProgress(progress WarmProgress) Option {
+	return func(o *options) {
+		o.warmProgress = progress
+	}
+}
+
+// WithLogger returns an option which sets the logger for warnings, such as the use of deprecated modules. The default
+// logger is slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithStrict returns an option which forbids the implicit assignable type fallback. In strict mode, a dependency
+// associated by type must be satisfied by an instance method declaring exactly that type, or be associated by name
+// instead. The same rule applies to Container.Instance. A dependency on an interface type declared by more than one
+// instance fails the creation, unless the conflict policy picks one, as it could never be satisfied by type.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
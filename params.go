@@ -0,0 +1,250 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const _OptionalTagOption = "optional"
+
+// Params is embedded into a struct to make it the parameters of an instance method or a constructor, as an alternative
+// to positional parameters. The method must take the struct as its only parameter. Each field is tagged like a
+// module field: `alice:""` associates it by type and `alice:"Name"` by name. The option "optional", as in
+// `alice:",optional"` or `alice:"Name,optional"`, leaves the field zero if no instance is provided.
+//
+//	type ServerParams struct {
+//		alice.Params
+//		DB     *sql.DB `alice:""`
+//		Port   int     `alice:"Port"`
+//		Tracer Tracer  `alice:",optional"`
+//	}
+//
+//	func (m *ServerModule) Server(p ServerParams) *Server {
+//		return NewServer(p.DB, p.Port, p.Tracer)
+//	}
+type Params struct{}
+
+var _ParamsType = reflect.TypeOf(Params{})
+
+// paramsStruct is the parameters struct of an instance method.
+type paramsStruct struct {
+	tp     reflect.Type
+	fields []*paramField
+}
+
+// paramField is a field of a parameters struct.
+type paramField struct {
+	// index is the index sequence of the field, which is promoted from an embedded struct if it has more than one
+	// element.
+	index []int
+	// name is the instance name, or empty if the field is associated by type.
+	name     string
+	tp       reflect.Type
+	optional bool
+	// missing indicates the field is optional and no instance is provided. It is figured out during graph
+	// construction.
+	missing bool
+}
+
+// isParamsStruct checks if a type is a struct embedding Params.
+func isParamsStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == _ParamsType {
+			return true
+		}
+	}
+	return false
+}
+
+// reflectParams returns the parameter types of a function type starting from the specified index, or the parameters
+// struct if the function takes one as its only parameter.
+func reflectParams(t reflect.Type, from int) ([]reflect.Type, *paramsStruct, error) {
+	params := methodParams(t, from)
+	if len(params) == 1 && isParamsStruct(params[0]) {
+		ps, err := reflectParamsStruct(params[0])
+		return nil, ps, err
+	}
+	for _, param := range params {
+		if isParamsStruct(param) {
+			return nil, nil, fmt.Errorf("parameters struct %s is not the only parameter", param.Name())
+		}
+	}
+	return params, nil, nil
+}
+
+// reflectParamsStruct extracts the fields of a parameters struct.
+func reflectParamsStruct(t reflect.Type) (*paramsStruct, error) {
+	ps := &paramsStruct{tp: t}
+	if err := ps.reflectFields(t, nil, map[reflect.Type]bool{}); err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+// reflectFields adds the fields of struct type t to the parameters struct. t is the parameters struct itself or an
+// untagged struct embedded in it, like a set of dependencies shared by many constructors, and index is the index
+// sequence of t. Embedded structs, by value or by pointer, are walked recursively.
+func (ps *paramsStruct) reflectFields(t reflect.Type, index []int, visited map[reflect.Type]bool) error {
+	if visited[t] {
+		return nil
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type == _ParamsType {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		tag, exists := field.Tag.Lookup(_Tag)
+		if !exists && field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := ps.reflectFields(embedded, fieldIndex, visited); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !exists {
+			return fmt.Errorf("field %s.%s of parameters struct is not tagged", t.Name(), field.Name)
+		}
+
+		name, option, _ := strings.Cut(tag, ",")
+		if option != "" && option != _OptionalTagOption {
+			return fmt.Errorf("field %s.%s of parameters struct has unknown option %s", t.Name(), field.Name,
+				option)
+		}
+		ps.fields = append(ps.fields, &paramField{
+			index:    fieldIndex,
+			name:     name,
+			tp:       field.Type,
+			optional: option == _OptionalTagOption,
+		})
+	}
+	return nil
+}
+
+// copy returns a copy of the parameters struct, so the fields could be resolved per container.
+func (ps *paramsStruct) copy() *paramsStruct {
+	if ps == nil {
+		return nil
+	}
+	c := &paramsStruct{tp: ps.tp}
+	for _, field := range ps.fields {
+		f := *field
+		c.fields = append(c.fields, &f)
+	}
+	return c
+}
+
+// createDependenciesByParams creates dependencies of a module using the parameters structs of its instance methods.
+func (g *graph) createDependenciesByParams(
+	rm *reflectedModule,
+	names *shortNames,
+	nameToProviderMap map[string]*reflectedModule,
+	typeToProvidersMap map[reflect.Type][]*reflectedModule) error {
+	var errs []error
+	for _, instance := range rm.instances {
+		if instance.paramsStruct == nil {
+			continue
+		}
+		for _, field := range instance.paramsStruct.fields {
+			if err := g.createDependencyByParam(rm, field, names, nameToProviderMap, typeToProvidersMap); err != nil {
+				errs = append(errs, fmt.Errorf("parameter of %s.%s: %s", rm.name, instance.name, err.Error()))
+			}
+		}
+	}
+	return joinErrors(errs)
+}
+
+// createDependencyByParam creates the dependency of a module on the provider of a parameters struct field.
+func (g *graph) createDependencyByParam(
+	rm *reflectedModule,
+	field *paramField,
+	names *shortNames,
+	nameToProviderMap map[string]*reflectedModule,
+	typeToProvidersMap map[reflect.Type][]*reflectedModule) error {
+	if field.name == "" {
+		if field.optional && !g.providesType(field.tp, typeToProvidersMap) {
+			field.missing = true
+			return nil
+		}
+		return g.createDependencyByType(rm, field.tp, typeToProvidersMap)
+	}
+
+	if names != nil {
+		qualified, err := names.qualify(field.name)
+		if err != nil {
+			return fmt.Errorf("dependency name %s.%s: %s", rm.name, field.name, err.Error())
+		}
+		field.name = qualified
+	}
+	provider, ok := nameToProviderMap[field.name]
+	if !ok {
+		if field.optional {
+			field.missing = true
+			return nil
+		}
+		return fmt.Errorf("dependency name %s.%s is not found", rm.name, field.name)
+	}
+	if err := checkNamedType(rm, field.name, field.tp, provider); err != nil {
+		return err
+	}
+	g.addDependencyEdge(provider, rm)
+	g.addInstanceDependency(rm, field.name)
+	return nil
+}
+
+// providesType checks if any instance of the same or, unless in strict mode, assignable type is provided.
+func (g *graph) providesType(t reflect.Type, typeToProvidersMap map[reflect.Type][]*reflectedModule) bool {
+	if isContainerProvided(t) {
+		return true
+	}
+	if _, ok := typeToProvidersMap[t]; ok {
+		return true
+	}
+	if g.options.strict {
+		return false
+	}
+	for providedType := range typeToProvidersMap {
+		if providedType.AssignableTo(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildParams creates the parameters struct of an instance method.
+func (c *container) buildParams(im *instanceMethod, consumer func() *reflectedModule) reflect.Value {
+	ps := im.paramsStruct
+	v := reflect.New(ps.tp).Elem()
+	allocateEmbedded(v)
+	for _, field := range ps.fields {
+		if field.missing {
+			continue
+		}
+		var instance interface{}
+		if field.name != "" {
+			instance = c.findInstanceByName(field.name)
+		} else {
+			instance = c.findDependencyByType(field.tp, consumer)
+		}
+		fieldName := ps.tp.FieldByIndex(field.index).Name
+		instance = c.hookInjection(instance, paramSite(consumer, im, fieldName, field.name, field.tp))
+		if field.name != "" {
+			settable(moduleField(v, field.index)).Set(namedValue(consumer().name, field.name, instance, field.tp))
+		} else {
+			settable(moduleField(v, field.index)).Set(instanceValue(instance, field.tp))
+		}
+	}
+	return v
+}
@@ -0,0 +1,126 @@
+package alice
+
+import (
+	"testing"
+)
+
+type serverParams struct {
+	Params
+	D1    D1 `alice:""`
+	D2    D2 `alice:"D2"`
+	D3    D3 `alice:",optional"`
+	Named D4 `alice:"Undefined,optional"`
+}
+
+type ParamsModule struct {
+	BaseModule
+	params serverParams
+}
+
+func (m *ParamsModule) Server(p serverParams) *paramsServer {
+	m.params = p
+	return &paramsServer{}
+}
+
+type paramsServer struct {
+	addr string
+}
+
+type untaggedParams struct {
+	Params
+	D1 D1
+}
+
+type invalidParamsModule1 struct {
+	BaseModule
+}
+
+func (m *invalidParamsModule1) D5(p untaggedParams) *D5Impl {
+	return &D5Impl{}
+}
+
+type invalidParamsModule2 struct {
+	BaseModule
+}
+
+func (m *invalidParamsModule2) D5(p serverParams, d1 D1) *D5Impl {
+	return &D5Impl{}
+}
+
+func TestParams(t *testing.T) {
+	m := &ParamsModule{}
+	c := CreateContainer(&M1{}, m)
+	if m.params.D1 == nil || m.params.D2 == nil {
+		t.Errorf("bad required parameters after CreateContainer(): got %+v", m.params)
+	}
+	if m.params.D3 != nil || m.params.Named != nil {
+		t.Errorf("bad optional parameters after CreateContainer(): got %+v, expected nil", m.params)
+	}
+
+	m = &ParamsModule{}
+	CreateContainerWithOptions([]Module{&M1{}, &M4{}, m}, WithLazy()).InstanceByName("Server")
+	if m.params.D3 == nil {
+		t.Errorf("bad optional parameter after InstanceByName(): got nil, expected D3")
+	}
+
+	var p serverParams
+	built := NewModule("built").
+		Provide("Server", func(params serverParams) *paramsServer {
+			p = params
+			return &paramsServer{}
+		}).
+		Build()
+	CreateContainer(&M1{}, built)
+	if p.D1 == nil || p.D2 == nil {
+		t.Errorf("bad parameters of constructor after CreateContainer(): got %+v", p)
+	}
+	if unused := c.(Introspector).Unused(); len(unused) != 1 || unused[0] != "Server" {
+		t.Errorf("bad unused instances after CreateContainer(): got %v, expected [Server]", unused)
+	}
+}
+
+func TestParams_Invalid(t *testing.T) {
+	if err := Validate(&ParamsModule{}); err == nil {
+		t.Error("expected error for missing required parameters")
+	}
+	for _, m := range []Module{&invalidParamsModule1{}, &invalidParamsModule2{}} {
+		if err := Validate(m); err == nil {
+			t.Errorf("expected error for invalid parameters struct of %T", m)
+		}
+	}
+}
+
+// baseDeps are the dependencies shared by many constructors.
+type baseDeps struct {
+	D1 D1 `alice:""`
+	D2 D2 `alice:"D2"`
+}
+
+type embeddingParams struct {
+	Params
+	baseDeps
+	*optionalDeps
+	D3 D3 `alice:"D3"`
+}
+
+type optionalDeps struct {
+	Named D4 `alice:"Undefined,optional"`
+}
+
+func TestParams_Embedded(t *testing.T) {
+	var p embeddingParams
+	built := NewModule("built").
+		Provide("Server", func(params embeddingParams) *paramsServer {
+			p = params
+			return &paramsServer{}
+		}).
+		Build()
+	CreateContainer(&M1{}, &M4{}, built)
+
+	if p.D1 == nil || p.D2 == nil || p.D3 == nil {
+		t.Errorf("bad promoted parameters after CreateContainer(): got %+v and D3 %v", p.baseDeps, p.D3)
+	}
+	if p.optionalDeps == nil || p.Named != nil {
+		t.Errorf("bad parameters of embedded pointer after CreateContainer(): got %+v", p.optionalDeps)
+	}
+}
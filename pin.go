@@ -0,0 +1,82 @@
+package alice
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// InstanceRef refers to an instance by the module providing it and its name.
+type InstanceRef struct {
+	// Module is the name of the module providing the instance.
+	Module string
+	// Name is the instance name.
+	Name string
+}
+
+// String returns the reference in the form of "Module.Name".
+func (r InstanceRef) String() string {
+	return r.Module + "." + r.Name
+}
+
+func (c *container) Order() []InstanceRef {
+	var refs []InstanceRef
+	for _, rm := range c.reflected {
+		for _, instance := range rm.instances {
+			refs = append(refs, InstanceRef{Module: rm.name, Name: instance.name})
+		}
+	}
+	return refs
+}
+
+// WithPinnedPlan returns an option which makes the container creation verify the plan against a pinned one, in the
+// format written by WritePlan or WithPrintPlanAndExit. The container creation panics before any instance method is
+// called if the instances, their types, dependencies or instantiation order differ, listing the removed lines
+// prefixed by "-" and the added lines prefixed by "+". It guards against the wiring changing unexpectedly, e.g. by
+// checking the plan file into the repository and updating it on purpose.
+func WithPinnedPlan(pinned []byte) Option {
+	return func(o *options) {
+		o.pinnedPlan = pinned
+	}
+}
+
+// verifyPinnedPlan returns error if the plan of the modules differs from the pinned one.
+func (c *container) verifyPinnedPlan(rms []*reflectedModule) error {
+	var buf bytes.Buffer
+	WritePlan(&buf, c.plannedInstances(rms))
+	expected := planLines(c.options.pinnedPlan)
+	actual := planLines(buf.Bytes())
+
+	inActual := make(map[string]bool)
+	for _, line := range actual {
+		inActual[line] = true
+	}
+	inExpected := make(map[string]bool)
+	var diff []string
+	for _, line := range expected {
+		inExpected[line] = true
+		if !inActual[line] {
+			diff = append(diff, "- "+line)
+		}
+	}
+	for _, line := range actual {
+		if !inExpected[line] {
+			diff = append(diff, "+ "+line)
+		}
+	}
+	if len(diff) == 0 && len(expected) == len(actual) {
+		return nil
+	}
+	return fmt.Errorf("plan differs from the pinned plan:\n%s", strings.Join(diff, "\n"))
+}
+
+// planLines splits a plan into non-empty lines, ignoring surrounding spaces.
+func planLines(plan []byte) []string {
+	var lines []string
+	for _, line := range strings.Split(string(plan), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
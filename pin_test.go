@@ -0,0 +1,69 @@
+package alice
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContainerOrder(t *testing.T) {
+	c := CreateContainer(&M4{}, &M1{})
+	order := c.(Introspector).Order()
+	expected := []InstanceRef{{"M1", "D1"}, {"M1", "D2"}, {"M4", "D3"}, {"M4", "D4"}}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("bad order after Order(): got %v, expected %v", order, expected)
+	}
+	if order[2].String() != "M4.D3" {
+		t.Errorf("bad string after InstanceRef.String(): got %s, expected %s", order[2].String(), "M4.D3")
+	}
+}
+
+func TestContainerOrderDeterministic(t *testing.T) {
+	var modules []Module
+	for i := 0; i < 8; i++ {
+		modules = append(modules, NewModule(fmt.Sprintf("m%d", i)).
+			Provide(fmt.Sprintf("X%d", i), func(d D1) *D5Impl { return &D5Impl{} }).
+			Build())
+	}
+	modules = append(modules, &M1{})
+	first := fmt.Sprint(CreateContainer(modules...).(Introspector).Order())
+	for i := 0; i < 20; i++ {
+		if order := fmt.Sprint(CreateContainer(modules...).(Introspector).Order()); order != first {
+			t.Fatalf("bad order after Order(): got %s, expected %s", order, first)
+		}
+	}
+}
+
+func TestWithPinnedPlan(t *testing.T) {
+	planned, _ := Plan([]Module{&M4{}, &M1{}})
+	var pinned bytes.Buffer
+	if err := WritePlan(&pinned, planned); err != nil {
+		t.Fatalf("bad error after WritePlan(): got %v, expected nil", err)
+	}
+	if !strings.HasPrefix(pinned.String(), "1. M1.D1 alice.D1\n") {
+		t.Errorf("bad output after WritePlan(): got %s", pinned.String())
+	}
+
+	c := CreateContainerWithOptions([]Module{&M4{}, &M1{}}, WithPinnedPlan(pinned.Bytes()))
+	if c.InstanceByName("D3") == nil {
+		t.Error("bad instance after CreateContainer() with the pinned plan: got nil")
+	}
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok {
+			t.Fatalf("bad panic after CreateContainer() with a changed plan: got %v, expected error", r)
+		}
+		if !strings.Contains(err.Error(), "- 2. M1.D2 alice.D2") ||
+			!strings.Contains(err.Error(), "+ 2. M4.D3 alice.D3 <- D1") {
+			t.Errorf("bad error after CreateContainer() with a changed plan: got %v", err)
+		}
+	}()
+	changed := NewModule("M1").
+		Provide("D1", func() D1 { return &D1Impl{} }).
+		Build()
+	CreateContainerWithOptions([]Module{&M4{}, changed}, WithPinnedPlan(pinned.Bytes()))
+}
@@ -0,0 +1,111 @@
+package alice
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// PlannedInstance is an instance the container plans to construct.
+type PlannedInstance struct {
+	// Module is the name of the module providing the instance.
+	Module string
+	// Name is the instance name, which is also the method name unless it is changed by naming or namespaces.
+	Name string
+	// Type is the instance type declared by the instance method.
+	Type reflect.Type
+	// Dependencies are the names of instances the module depends on, sorted. They are constructed before the
+	// instance.
+	Dependencies []string
+	// Background indicates the instance is constructed on a background goroutine.
+	Background bool
+}
+
+// String returns the instance in the form of "Module.Name Type <- Dependency1, Dependency2".
+func (p PlannedInstance) String() string {
+	s := fmt.Sprintf("%s.%s %s", p.Module, p.Name, p.Type)
+	if p.Background {
+		s += " (background)"
+	}
+	if len(p.Dependencies) > 0 {
+		s += " <- " + strings.Join(p.Dependencies, ", ")
+	}
+	return s
+}
+
+// Plan returns the instances the modules would be constructed into, in instantiation order, without calling any
+// instance method. It returns error if the modules are invalid.
+func Plan(modules []Module, opts ...Option) ([]PlannedInstance, error) {
+	c := &container{
+		modules: modules,
+		options: newOptions(opts...),
+	}
+	rms, err := c.plan()
+	if err != nil {
+		return nil, err
+	}
+	return c.plannedInstances(rms), nil
+}
+
+func (c *container) Plan() []PlannedInstance {
+	return c.plannedInstances(c.reflected)
+}
+
+func (c *container) plannedInstances(rms []*reflectedModule) []PlannedInstance {
+	var planned []PlannedInstance
+	for _, rm := range rms {
+		var dependencies []string
+		for name := range c.graph.dependsOn[rm] {
+			dependencies = append(dependencies, name)
+		}
+		sort.Strings(dependencies)
+		for _, instance := range rm.instances {
+			planned = append(planned, PlannedInstance{
+				Module:       rm.name,
+				Name:         instance.name,
+				Type:         instance.tp,
+				Dependencies: dependencies,
+				Background:   instance.background,
+			})
+		}
+	}
+	return planned
+}
+
+// exit is os.Exit. It is replaced in tests.
+var exit = os.Exit
+
+// WithPrintPlanAndExit returns an option which makes the container creation print the plan to w, one instance per
+// line, and exit the process before any instance method is called. The exit code is 1 if the modules are invalid.
+// It lets operators audit what a new binary would construct before it touches any infrastructure, e.g. when a
+// command line flag is set.
+func WithPrintPlanAndExit(w io.Writer) Option {
+	return func(o *options) {
+		o.planOutput = w
+	}
+}
+
+// printPlanAndExit prints the plan of the modules and exits.
+func (c *container) printPlanAndExit(rms []*reflectedModule, err error) {
+	if err != nil {
+		fmt.Fprintf(c.options.planOutput, "invalid plan: %s\n", err.Error())
+		exit(1)
+		return
+	}
+	WritePlan(c.options.planOutput, c.plannedInstances(rms))
+	exit(0)
+}
+
+// WritePlan writes the plan to w, one numbered instance per line in the form of PlannedInstance.String. It is the
+// format printed by WithPrintPlanAndExit and verified by WithPinnedPlan.
+func WritePlan(w io.Writer, plan []PlannedInstance) error {
+	for i, p := range plan {
+		if _, err := fmt.Fprintf(w, "%d. %s\n", i+1, p.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package alice
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	planned, err := Plan([]Module{&M4{}, &M1{}})
+	if err != nil {
+		t.Fatalf("bad error after Plan(): got %v, expected nil", err)
+	}
+	expected := []PlannedInstance{
+		{Module: "M1", Name: "D1", Type: reflect.TypeOf((*D1)(nil)).Elem()},
+		{Module: "M1", Name: "D2", Type: reflect.TypeOf((*D2)(nil)).Elem()},
+		{Module: "M4", Name: "D3", Type: reflect.TypeOf((*D3)(nil)).Elem(), Dependencies: []string{"D1"}},
+		{Module: "M4", Name: "D4", Type: reflect.TypeOf((*D4)(nil)).Elem(), Dependencies: []string{"D1"}},
+	}
+	if !reflect.DeepEqual(planned, expected) {
+		t.Errorf("bad plan after Plan(): got %v, expected %v", planned, expected)
+	}
+
+	c := CreateContainer(&M4{}, &M1{})
+	if !reflect.DeepEqual(c.(Introspector).Plan(), expected) {
+		t.Errorf("bad plan after Container.Plan(): got %v, expected %v", c.(Introspector).Plan(), expected)
+	}
+
+	if _, err := Plan([]Module{&M4{}}); err == nil {
+		t.Error("expected error after Plan() with missing dependency")
+	}
+}
+
+func TestWithPrintPlanAndExit(t *testing.T) {
+	defer func(e func(int)) {
+		exit = e
+	}(exit)
+	code := -1
+	exit = func(c int) {
+		code = c
+		panic("exit")
+	}
+	create := func(modules ...Module) {
+		var buf bytes.Buffer
+		defer func() {
+			recover()
+		}()
+		CreateContainerWithOptions(modules, WithPrintPlanAndExit(&buf))
+	}
+
+	panicking := NewModule("panicking").
+		Provide("X", func() *D5Impl { panic("constructed") }).
+		Build()
+	var buf bytes.Buffer
+	func() {
+		defer func() {
+			if r := recover(); r != "exit" {
+				t.Errorf("bad panic after CreateContainer(): got %v, expected exit", r)
+			}
+		}()
+		CreateContainerWithOptions([]Module{&M4{}, panicking, &M1{}}, WithPrintPlanAndExit(&buf))
+	}()
+	if code != 0 || !strings.Contains(buf.String(), "4. M4.D3 alice.D3 <- D1") {
+		t.Errorf("bad output after CreateContainer(): got %d, %s", code, buf.String())
+	}
+
+	create(&M4{})
+	if code != 1 {
+		t.Errorf("bad exit code after CreateContainer() with invalid modules: got %d, expected %d", code, 1)
+	}
+}
@@ -0,0 +1,199 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Invocation is a call to a proxy.
+type Invocation struct {
+	// Method is the method name, or empty if the proxy is a function.
+	Method string
+	// Args are the arguments of the call. The variadic arguments, if any, are passed as a slice in the last one.
+	Args []interface{}
+	// Proceed calls the target with the arguments and returns its results.
+	Proceed func(args []interface{}) []interface{}
+}
+
+// Interceptor is called for each call to a proxy, instead of the target. It usually calls Proceed, maybe with
+// changed arguments, and returns the results, e.g. to record or time the calls.
+type Interceptor func(inv *Invocation) []interface{}
+
+// ProxyFactory creates proxies of instances. A proxy has the type of the instance, and forwards each call to the
+// target returned by target at the time of the call. So it could construct the target lazily, or switch between
+// targets, e.g. by a feature flag. If interceptor is not nil, each call goes through it.
+type ProxyFactory interface {
+	// Proxy returns a proxy of type t, or false if the factory can't create proxies of the type. It is also called
+	// when the container is created, to check the types of the instances to be proxied, and the proxy is dropped.
+	Proxy(t reflect.Type, target func() interface{}, interceptor Interceptor) (interface{}, bool)
+}
+
+// WithProxyFactory returns an option which sets the factory of the proxies created by the container, for synchronized
+// instances, gated instances and lazy dependencies. Without the option, ReflectProxyFactory is used.
+func WithProxyFactory(factory ProxyFactory) Option {
+	return func(o *options) {
+		o.proxyFactory = factory
+	}
+}
+
+// proxies returns the proxy factory of the container.
+func (o *options) proxies() ProxyFactory {
+	if o.proxyFactory == nil {
+		return ReflectProxyFactory{}
+	}
+	return o.proxyFactory
+}
+
+// canProxy reports whether the proxy factory of the container could create proxies of type t.
+func (o *options) canProxy(t reflect.Type) bool {
+	_, ok := o.proxies().Proxy(t, func() interface{} { return nil }, nil)
+	return ok
+}
+
+// checkProxies returns error if the proxy factory can't proxy the synchronized instances or the lazy dependencies, so
+// they are rejected when the container is created rather than when they are constructed or injected. The gated
+// instances it can't proxy are switched on resolution instead.
+func checkProxies(o *options, rms []*reflectedModule) error {
+	var errs []error
+	for _, rm := range rms {
+		for _, instance := range rm.instances {
+			var feature string
+			switch {
+			case instance.synchronized:
+				feature = "synchronized"
+			default:
+				continue
+			}
+			if !o.canProxy(instance.tp) {
+				errs = append(errs, fmt.Errorf("%s instance %s.%s has type %s, which the proxy factory can't proxy; "+
+					"register a proxy constructor of it to a GeneratedProxyFactory", feature, rm.name, instance.name,
+					instance.tp))
+			}
+		}
+		var lazy []reflect.Type
+		for _, dep := range rm.namedDepends {
+			if dep.lazy {
+				lazy = append(lazy, dep.field.Type())
+			}
+		}
+		for _, dep := range rm.typedDepends {
+			if dep.lazy {
+				lazy = append(lazy, dep.tp)
+			}
+		}
+		for _, t := range lazy {
+			if !o.canProxy(t) {
+				errs = append(errs, fmt.Errorf("lazy dependency of module %s has type %s, which the proxy factory "+
+					"can't proxy; register a proxy constructor of it to a GeneratedProxyFactory", rm.name, t))
+			}
+		}
+	}
+	return joinErrors(errs)
+}
+
+// lazyDependency returns a proxy of type t, which resolves the dependency on its first call, or again on the next
+// call if the resolution panics. The proxy factory could proxy the type, as checked by checkProxies.
+func (c *container) lazyDependency(t reflect.Type, resolve func() reflect.Value) reflect.Value {
+	var mu sync.Mutex
+	var resolved interface{}
+	done := false
+	proxy, ok := c.options.proxies().Proxy(t, func() interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		if !done {
+			resolved = resolve().Interface()
+			done = true
+		}
+		return resolved
+	}, nil)
+	if !ok {
+		panic(fmt.Errorf("lazy dependency of type %s could not be proxied by the proxy factory", t))
+	}
+	return reflect.ValueOf(proxy)
+}
+
+// ReflectProxyFactory creates proxies of function types by reflect.MakeFunc. Go reflection can't create types
+// implementing interfaces, so it can't proxy interface types.
+type ReflectProxyFactory struct{}
+
+// Proxy returns a proxy of type t if it is a function type.
+func (ReflectProxyFactory) Proxy(t reflect.Type, target func() interface{}, interceptor Interceptor) (interface{},
+	bool) {
+	if t.Kind() != reflect.Func {
+		return nil, false
+	}
+	call := func(args []reflect.Value) []reflect.Value {
+		f := instanceValue(target(), t)
+		if t.IsVariadic() {
+			return f.CallSlice(args)
+		}
+		return f.Call(args)
+	}
+	if interceptor == nil {
+		return reflect.MakeFunc(t, call).Interface(), true
+	}
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		results := interceptor(&Invocation{
+			Args: interfaces(args),
+			Proceed: func(args []interface{}) []interface{} {
+				return interfaces(call(values(args, t.In)))
+			},
+		})
+		return values(results, t.Out)
+	}).Interface(), true
+}
+
+// interfaces converts reflect values to interfaces.
+func interfaces(vs []reflect.Value) []interface{} {
+	is := make([]interface{}, len(vs))
+	for i, v := range vs {
+		is[i] = v.Interface()
+	}
+	return is
+}
+
+// values converts interfaces to reflect values of the types returned by tp, using zero values for nil.
+func values(is []interface{}, tp func(int) reflect.Type) []reflect.Value {
+	vs := make([]reflect.Value, len(is))
+	for i, v := range is {
+		vs[i] = instanceValue(v, tp(i))
+	}
+	return vs
+}
+
+// ProxyConstructor creates a proxy of an interface type, like ProxyFactory.Proxy. It is usually generated ahead of
+// time, as a type implementing the interface by calling target and interceptor in each method, so calls don't go
+// through reflection.
+type ProxyConstructor func(target func() interface{}, interceptor Interceptor) interface{}
+
+// GeneratedProxyFactory creates proxies by the registered constructors, and falls back to ReflectProxyFactory for
+// other types. It is safe for concurrent use.
+type GeneratedProxyFactory struct {
+	mu           sync.RWMutex
+	constructors map[reflect.Type]ProxyConstructor
+}
+
+// NewGeneratedProxyFactory creates a factory without any registered constructor.
+func NewGeneratedProxyFactory() *GeneratedProxyFactory {
+	return &GeneratedProxyFactory{constructors: make(map[reflect.Type]ProxyConstructor)}
+}
+
+// Register registers the constructor of proxies of type t, usually from an init function of generated code.
+func (f *GeneratedProxyFactory) Register(t reflect.Type, constructor ProxyConstructor) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.constructors[t] = constructor
+}
+
+// Proxy returns a proxy of type t by the registered constructor, or by ReflectProxyFactory if there is none.
+func (f *GeneratedProxyFactory) Proxy(t reflect.Type, target func() interface{}, interceptor Interceptor) (
+	interface{}, bool) {
+	f.mu.RLock()
+	constructor, ok := f.constructors[t]
+	f.mu.RUnlock()
+	if ok {
+		return constructor(target, interceptor), true
+	}
+	return ReflectProxyFactory{}.Proxy(t, target, interceptor)
+}
@@ -0,0 +1,182 @@
+package alice
+
+import (
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type Greeter interface {
+	Greet(name string) string
+}
+
+type greeterFunc func(name string) string
+
+func (f greeterFunc) Greet(name string) string {
+	return f(name)
+}
+
+// greeterProxy is what a code generator would write for Greeter.
+type greeterProxy struct {
+	target      func() interface{}
+	interceptor Interceptor
+}
+
+func (p greeterProxy) Greet(name string) string {
+	target := p.target().(Greeter)
+	if p.interceptor == nil {
+		return target.Greet(name)
+	}
+	results := p.interceptor(&Invocation{
+		Method: "Greet",
+		Args:   []interface{}{name},
+		Proceed: func(args []interface{}) []interface{} {
+			return []interface{}{target.Greet(args[0].(string))}
+		},
+	})
+	return results[0].(string)
+}
+
+var greeterType = reflect.TypeOf((*Greeter)(nil)).Elem()
+
+func newGreeterProxy(target func() interface{}, interceptor Interceptor) interface{} {
+	return greeterProxy{target: target, interceptor: interceptor}
+}
+
+type GatedGreeterModule struct {
+	BaseModule
+}
+
+func (m *GatedGreeterModule) Greeter() Greeter {
+	return greeterFunc(func(name string) string { return "hello " + name })
+}
+
+func (m *GatedGreeterModule) Gates() map[string]Gate {
+	return map[string]Gate{
+		"Greeter": {Flag: "greet", Alternative: func() Greeter {
+			return greeterFunc(func(string) string { return "" })
+		}},
+	}
+}
+
+func TestReflectProxyFactory(t *testing.T) {
+	var calls []string
+	interceptor := func(inv *Invocation) []interface{} {
+		calls = append(calls, inv.Args[0].(string))
+		return inv.Proceed([]interface{}{inv.Args[0].(string) + "!"})
+	}
+	greet := func(name string) string { return "hello " + name }
+	proxy, ok := ReflectProxyFactory{}.Proxy(reflect.TypeOf(greet), func() interface{} { return greet }, interceptor)
+	if !ok {
+		t.Fatal("bad result after Proxy() of function type: got false, expected true")
+	}
+	if s := proxy.(func(string) string)("alice"); s != "hello alice!" {
+		t.Errorf("bad result after calling the proxy: got %q, expected %q", s, "hello alice!")
+	}
+	if !reflect.DeepEqual(calls, []string{"alice"}) {
+		t.Errorf("bad calls after calling the proxy: got %v, expected %v", calls, []string{"alice"})
+	}
+
+	if _, ok := (ReflectProxyFactory{}).Proxy(greeterType, nil, nil); ok {
+		t.Error("bad result after Proxy() of interface type: got true, expected false")
+	}
+}
+
+func TestGeneratedProxyFactory(t *testing.T) {
+	factory := NewGeneratedProxyFactory()
+	factory.Register(greeterType, newGreeterProxy)
+
+	var greet atomic.Bool
+	flags := FeatureFlagsFunc(func(flag string) bool {
+		return greet.Load()
+	})
+	c := CreateContainerWithOptions([]Module{&GatedGreeterModule{}}, WithFeatureFlags(flags),
+		WithProxyFactory(factory))
+	greeter := c.Instance(greeterType).(Greeter)
+	if s := greeter.Greet("alice"); s != "" {
+		t.Errorf("bad greeting with disabled flag: got %q, expected %q", s, "")
+	}
+	greet.Store(true)
+	if s := greeter.Greet("alice"); s != "hello alice" {
+		t.Errorf("bad greeting after enabling flag: got %q, expected %q", s, "hello alice")
+	}
+
+	f := func() int { return 1 }
+	proxy, ok := factory.Proxy(reflect.TypeOf(f), func() interface{} { return f }, nil)
+	if !ok || proxy.(func() int)() != 1 {
+		t.Errorf("bad proxy after Proxy() of function type: got %v, %v", proxy, ok)
+	}
+}
+
+func TestGatedInterfaceWithoutProxyFactory(t *testing.T) {
+	var greet atomic.Bool
+	flags := FeatureFlagsFunc(func(string) bool { return greet.Load() })
+	c := CreateContainerWithOptions([]Module{&GatedGreeterModule{}}, WithFeatureFlags(flags))
+	if s := c.Instance(greeterType).(Greeter).Greet("alice"); s != "" {
+		t.Errorf("bad greeting with disabled flag: got %q, expected %q", s, "")
+	}
+	greet.Store(true)
+	if s := c.Instance(greeterType).(Greeter).Greet("alice"); s != "hello alice" {
+		t.Errorf("bad greeting after enabling flag: got %q, expected %q", s, "hello alice")
+	}
+	if s := c.InstanceByName("Greeter").(Greeter).Greet("alice"); s != "hello alice" {
+		t.Errorf("bad greeting by name after enabling flag: got %q, expected %q", s, "hello alice")
+	}
+}
+
+type GreetingModule struct {
+	BaseModule
+}
+
+func (m *GreetingModule) Greeter() Greeter {
+	return greeterFunc(func(name string) string { return "hello " + name })
+}
+
+func (m *GreetingModule) Greet() func(string) string {
+	return func(name string) string { return "hi " + name }
+}
+
+type LazyConsumerModule struct {
+	BaseModule
+	Greeter Greeter                  `alice:",lazy"`
+	Greet   func(name string) string `alice:"Greet,lazy"`
+}
+
+func (m *LazyConsumerModule) Welcome() func(name string) string {
+	return func(name string) string {
+		return m.Greeter.Greet(name) + ", " + m.Greet(name)
+	}
+}
+
+type invalidLazyModule struct {
+	BaseModule
+	Greeters []Greeter `alice:"names=Greeter,lazy"`
+}
+
+func TestLazyDependency(t *testing.T) {
+	factory := NewGeneratedProxyFactory()
+	factory.Register(greeterType, newGreeterProxy)
+	c := CreateContainerWithOptions([]Module{&GreetingModule{}, &LazyConsumerModule{}}, WithLazy(),
+		WithProxyFactory(factory))
+	welcome := c.InstanceByName("Welcome").(func(string) string)
+	if pending := c.(Introspector).Pending(); !reflect.DeepEqual(pending, []string{"Greet", "Greeter"}) {
+		t.Errorf("bad pending instances before calling lazy dependencies: got %v, expected %v", pending,
+			[]string{"Greet", "Greeter"})
+	}
+	if s := welcome("alice"); s != "hello alice, hi alice" {
+		t.Errorf("bad result of lazy dependencies: got %q, expected %q", s, "hello alice, hi alice")
+	}
+	if pending := c.(Introspector).Pending(); len(pending) != 0 {
+		t.Errorf("bad pending instances after calling lazy dependencies: got %v, expected none", pending)
+	}
+
+	_, err := Plan([]Module{&GreetingModule{}, &LazyConsumerModule{}})
+	if err == nil || !strings.Contains(err.Error(), "lazy dependency of module LazyConsumerModule has type") {
+		t.Errorf("bad error after Plan() without proxy factory: got %v", err)
+	}
+	err = Validate(&GreetingModule{}, &invalidLazyModule{})
+	if err == nil || !strings.Contains(err.Error(), "is not associated by name or type") {
+		t.Errorf("bad error after Validate() with lazy dependency of names: got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+// Package random provides the random Source instance, so code depending on randomness could be tested
+// deterministically. The module provides a source seeded by the runtime, and its test variant, used by alicetest
+// containers automatically, provides a source with a fixed seed.
+//
+//	func (m *TokenModule) Tokens() *Tokens {
+//		return &Tokens{rand: rand.New(m.Source)}
+//	}
+package random
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"github.com/magic003/alice"
+)
+
+// Seed is the seed of the source of the test module.
+const Seed uint64 = 1
+
+type runtimeSource struct{}
+
+func (runtimeSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// Runtime returns the source of the top-level functions of math/rand/v2, seeded randomly by the runtime. It is safe
+// for concurrent use.
+func Runtime() rand.Source {
+	return runtimeSource{}
+}
+
+// lockedSource is safe for concurrent use.
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+// Seeded returns a source producing the same sequence for the same seed. It is safe for concurrent use.
+func Seeded(seed uint64) rand.Source {
+	return &lockedSource{src: rand.NewPCG(seed, seed)}
+}
+
+// Module provides the Source instance seeded by the runtime.
+type Module struct {
+	alice.BaseModule
+}
+
+// NewModule creates the module.
+func NewModule() *Module {
+	return &Module{}
+}
+
+// Source returns the source seeded by the runtime.
+func (m *Module) Source() rand.Source {
+	return Runtime()
+}
+
+// TestVariant returns a test module with Seed.
+func (m *Module) TestVariant() alice.Module {
+	return NewTestModule(Seed)
+}
+
+// TestModule provides the Source instance with a fixed seed.
+type TestModule struct {
+	alice.BaseModule
+	seed uint64
+}
+
+// NewTestModule creates the test module with the seed.
+func NewTestModule(seed uint64) *TestModule {
+	return &TestModule{seed: seed}
+}
+
+// Source returns a source with the seed.
+func (m *TestModule) Source() rand.Source {
+	return Seeded(m.seed)
+}
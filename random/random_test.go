@@ -0,0 +1,31 @@
+package random
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/magic003/alice"
+)
+
+var sourceType = reflect.TypeOf((*rand.Source)(nil)).Elem()
+
+func TestRuntime(t *testing.T) {
+	c := alice.CreateContainer(NewModule())
+	if source := c.Instance(sourceType).(rand.Source); source != Runtime() {
+		t.Errorf("bad source after CreateContainer(): got %v, expected the runtime source", source)
+	}
+}
+
+func TestSeeded(t *testing.T) {
+	first := alice.CreateContainer(NewModule().TestVariant()).Instance(sourceType).(rand.Source)
+	second := Seeded(Seed)
+	for i := 0; i < 10; i++ {
+		if a, b := first.Uint64(), second.Uint64(); a != b {
+			t.Fatalf("bad value after Uint64(): got %d, expected %d", a, b)
+		}
+	}
+	if Seeded(1).Uint64() == Seeded(2).Uint64() {
+		t.Error("bad values after Uint64() with different seeds: got the same")
+	}
+}
@@ -0,0 +1,58 @@
+package alice
+
+func (c *container) DependentsOf(name string) ([]string, error) {
+	name, err := c.definedName(name)
+	if err != nil {
+		return nil, err
+	}
+	dependents, _ := c.dependentsOf([]string{name})
+	delete(dependents, name)
+	return c.inInstantiationOrder(dependents), nil
+}
+
+func (c *container) DependenciesOf(name string) ([]string, error) {
+	name, err := c.definedName(name)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := make(map[string]bool)
+	visited := make(map[*reflectedModule]bool)
+	queue := []*reflectedModule{c.providerOf(name)}
+	for len(queue) > 0 {
+		rm := queue[0]
+		queue = queue[1:]
+		if visited[rm] {
+			continue
+		}
+		visited[rm] = true
+		for dependency := range c.graph.dependsOn[rm] {
+			dependencies[dependency] = true
+			if provider := c.providerOf(dependency); provider != nil {
+				queue = append(queue, provider)
+			}
+		}
+	}
+	delete(dependencies, name)
+	return c.inInstantiationOrder(dependencies), nil
+}
+
+// providerOf returns the module providing the instance with the specified name, or nil if it is not defined.
+func (c *container) providerOf(name string) *reflectedModule {
+	for _, rm := range c.reflected {
+		if findInstanceMethod(rm.instances, name) != nil {
+			return rm
+		}
+	}
+	return nil
+}
+
+// inInstantiationOrder returns the names in the set in instantiation order.
+func (c *container) inInstantiationOrder(set map[string]bool) []string {
+	var names []string
+	for _, name := range c.instanceNames() {
+		if set[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
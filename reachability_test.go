@@ -0,0 +1,45 @@
+package alice
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDependentsOf(t *testing.T) {
+	var d3 D3
+	top := NewModule("top").
+		Require(&d3).
+		Provide("Top", func() *D5Impl { return &D5Impl{} }).
+		Build()
+	c := CreateContainer(top, &M4{}, &M1{}).(Introspector)
+
+	dependents, err := c.DependentsOf("D1")
+	if err != nil {
+		t.Fatalf("bad error after DependentsOf(): got %v, expected nil", err)
+	}
+	if expected := []string{"D3", "D4", "Top"}; !reflect.DeepEqual(dependents, expected) {
+		t.Errorf("bad dependents after DependentsOf(): got %v, expected %v", dependents, expected)
+	}
+	if dependents, _ := c.DependentsOf("D2"); len(dependents) != 0 {
+		t.Errorf("bad dependents after DependentsOf(): got %v, expected empty", dependents)
+	}
+
+	dependencies, err := c.DependenciesOf("Top")
+	if err != nil {
+		t.Fatalf("bad error after DependenciesOf(): got %v, expected nil", err)
+	}
+	if expected := []string{"D1", "D3"}; !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("bad dependencies after DependenciesOf(): got %v, expected %v", dependencies, expected)
+	}
+	if dependencies, _ := c.DependenciesOf("D1"); len(dependencies) != 0 {
+		t.Errorf("bad dependencies after DependenciesOf(): got %v, expected empty", dependencies)
+	}
+
+	if _, err := c.DependentsOf("Undefined"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("bad error after DependentsOf() with undefined name: got %v, expected ErrNotFound", err)
+	}
+	if _, err := c.DependenciesOf("Undefined"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("bad error after DependenciesOf() with undefined name: got %v, expected ErrNotFound", err)
+	}
+}
@@ -0,0 +1,126 @@
+package alice
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrNotAllowed is the cause of a LookupError when an instance is defined, but not allowed in a read-only view.
+var ErrNotAllowed = errors.New("is not allowed in the view")
+
+// readOnlyView is a container resolving only the allowed instances of its parent. It implements no extension
+// interface, so the code it is passed to could neither start, reset nor fork the parent.
+type readOnlyView struct {
+	parent Container
+	// types are the types declared by the instance methods of the allowed instances, keyed by names.
+	types map[string]reflect.Type
+	// order contains the allowed names in instantiation order.
+	order []string
+	// defined contains the names of all instances of the parent, so names not allowed are told from undefined ones.
+	defined map[string]bool
+	// options are the options of the container at the root of the forks.
+	options *options
+}
+
+func (c *container) ReadOnlyView(allowedNames ...string) Container {
+	return newReadOnlyView(c, c.Instances(), rootOptions(c), allowedNames)
+}
+
+func (f *fork) ReadOnlyView(allowedNames ...string) Container {
+	return newReadOnlyView(f, f.Instances(), rootOptions(f), allowedNames)
+}
+
+// rootOptions returns the options of the container at the root of the forks.
+func rootOptions(c extendedContainer) *options {
+	switch p := c.(type) {
+	case *container:
+		return &p.options
+	case *fork:
+		return rootOptions(p.extendedContainer)
+	}
+	return &options{}
+}
+
+// newReadOnlyView creates a read-only view of the parent container with the allowed instances.
+func newReadOnlyView(parent Container, infos []InstanceInfo, o *options, allowedNames []string) *readOnlyView {
+	allowed := make(map[string]bool)
+	for _, name := range allowedNames {
+		allowed[name] = true
+	}
+	v := &readOnlyView{
+		parent:  parent,
+		types:   make(map[string]reflect.Type),
+		defined: make(map[string]bool),
+		options: o,
+	}
+	for _, info := range infos {
+		v.defined[info.Name] = true
+		if allowed[info.Name] {
+			v.types[info.Name] = info.Type
+			v.order = append(v.order, info.Name)
+		}
+	}
+	return v
+}
+
+func (v *readOnlyView) Instance(t reflect.Type) interface{} {
+	return v.options.retrieve(t, func() interface{} {
+		return v.MustInstance(t)
+	})
+}
+
+func (v *readOnlyView) InstanceByName(name string) interface{} {
+	return v.options.retrieve(nil, func() interface{} {
+		return v.MustInstanceByName(name)
+	})
+}
+
+func (v *readOnlyView) Resolve(t reflect.Type) (interface{}, error) {
+	return resolveSafely(func() interface{} {
+		return v.MustInstance(t)
+	})
+}
+
+func (v *readOnlyView) ResolveByName(name string) (interface{}, error) {
+	return resolveSafely(func() interface{} {
+		return v.MustInstanceByName(name)
+	})
+}
+
+func (v *readOnlyView) MustInstance(t reflect.Type) interface{} {
+	return v.parent.MustInstanceByName(v.nameOf(t))
+}
+
+func (v *readOnlyView) MustInstanceByName(name string) interface{} {
+	if _, ok := v.types[name]; !ok {
+		if v.defined[name] {
+			panic(&LookupError{Name: name, Err: ErrNotAllowed})
+		}
+		panic(&LookupError{Name: name, Err: ErrNotFound})
+	}
+	return v.parent.MustInstanceByName(name)
+}
+
+// nameOf returns the name of the allowed instance of the type, matching the declared types like a container. It
+// panics if no or more than one allowed instances are found.
+func (v *readOnlyView) nameOf(t reflect.Type) string {
+	var exact, assignable []string
+	for _, name := range v.order {
+		if declared := v.types[name]; declared == t {
+			exact = append(exact, name)
+		} else if declared.AssignableTo(t) {
+			assignable = append(assignable, name)
+		}
+	}
+	names := exact
+	if len(names) == 0 && !v.options.strict {
+		names = assignable
+	}
+	if len(names) == 0 {
+		panic(&LookupError{Type: t, Err: ErrNotFound})
+	}
+	if len(names) > 1 {
+		panic(&LookupError{Type: t, Err: ErrAmbiguous})
+	}
+	return names[0]
+}
@@ -0,0 +1,56 @@
+package alice
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestReadOnlyView(t *testing.T) {
+	c := CreateContainer(&M1{})
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	d2Type := reflect.TypeOf((*D2)(nil)).Elem()
+	v := c.(Scoper).ReadOnlyView("D1")
+
+	if instance := v.InstanceByName("D1"); instance != c.InstanceByName("D1") {
+		t.Errorf("bad instance by name from ReadOnlyView(): got %v, expected %v", instance, c.InstanceByName("D1"))
+	}
+	if instance := v.Instance(d1Type); instance != c.Instance(d1Type) {
+		t.Errorf("bad instance by type from ReadOnlyView(): got %v, expected %v", instance, c.Instance(d1Type))
+	}
+
+	if _, err := v.ResolveByName("D2"); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("bad error from ResolveByName() of a name not allowed: got %v, expected %v", err, ErrNotAllowed)
+	}
+	if _, err := v.Resolve(d2Type); !errors.Is(err, ErrNotFound) {
+		t.Errorf("bad error from Resolve() of a type not allowed: got %v, expected %v", err, ErrNotFound)
+	}
+	if _, err := v.ResolveByName("Undefined"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("bad error from ResolveByName() of an undefined name: got %v, expected %v", err, ErrNotFound)
+	}
+	expected := "instance name D2 is not allowed in the view"
+	if _, err := v.ResolveByName("D2"); err == nil || err.Error() != expected {
+		t.Errorf("bad message from ResolveByName(): got %v, expected %s", err, expected)
+	}
+
+	if _, ok := v.(Rebuilder); ok {
+		t.Error("read-only view is not expected to implement Rebuilder")
+	}
+	if _, ok := v.(Lifecycle); ok {
+		t.Error("read-only view is not expected to implement Lifecycle")
+	}
+}
+
+func TestReadOnlyView_Fork(t *testing.T) {
+	c := CreateContainer(&M1{})
+	alternate := &countedD1{n: 1}
+	f := c.(Scoper).Fork(OverrideName("D1", alternate))
+	v := f.(Scoper).ReadOnlyView("D1")
+
+	if instance := v.InstanceByName("D1"); instance != alternate {
+		t.Errorf("bad instance by name from ReadOnlyView() of fork: got %v, expected %v", instance, alternate)
+	}
+	if instance := v.Instance(reflect.TypeOf((*D1)(nil)).Elem()); instance != alternate {
+		t.Errorf("bad instance by type from ReadOnlyView() of fork: got %v, expected %v", instance, alternate)
+	}
+}
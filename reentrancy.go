@@ -0,0 +1,134 @@
+package alice
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// constructions tracks the instances under construction by each goroutine, so a resolution which would wait for
+// itself, directly or through other goroutines, is detected instead of deadlocking. It happens if an instance method
+// calls back into the container for an instance currently under construction.
+type constructions struct {
+	mu sync.Mutex
+	// stacks contains the names under construction by each goroutine, the outermost first.
+	stacks map[uint64][]string
+	// owners maps the names under construction to the goroutines constructing them.
+	owners map[string]uint64
+	// waiting maps the goroutines to the names they are waiting for.
+	waiting map[uint64]string
+}
+
+func newConstructions() *constructions {
+	return &constructions{
+		stacks:  make(map[uint64][]string),
+		owners:  make(map[string]uint64),
+		waiting: make(map[uint64]string),
+	}
+}
+
+// begin records that the current goroutine starts constructing the specified name. end must be called with the
+// returned goroutine id after the construction.
+func (cs *constructions) begin(name string) uint64 {
+	gid := goroutineID()
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.stacks[gid] = append(cs.stacks[gid], name)
+	cs.owners[name] = gid
+	return gid
+}
+
+// construct calls f as the construction of the specified name by the current goroutine. A panic is converted to a
+// *ConstructionError carrying the construction stack, unless it is already one raised by a nested construction.
+func (cs *constructions) construct(name string, f func()) {
+	gid := cs.begin(name)
+	defer cs.end(gid, name)
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(*ConstructionError); !ok {
+				r = &ConstructionError{Stack: cs.stack(gid), Cause: r}
+			}
+			panic(r)
+		}
+	}()
+	f()
+}
+
+// stack returns a copy of the names under construction by the specified goroutine.
+func (cs *constructions) stack(gid uint64) []string {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return append([]string{}, cs.stacks[gid]...)
+}
+
+// end records that the construction of the specified name finishes.
+func (cs *constructions) end(gid uint64, name string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	stack := cs.stacks[gid]
+	if len(stack) <= 1 {
+		delete(cs.stacks, gid)
+	} else {
+		cs.stacks[gid] = stack[:len(stack)-1]
+	}
+	delete(cs.owners, name)
+}
+
+// await records that the current goroutine waits for the construction of the specified name. It returns error if the
+// wait would never finish. Otherwise, awaited must be called with the returned goroutine id after the wait.
+func (cs *constructions) await(name string) (uint64, error) {
+	gid := goroutineID()
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	path := append(append([]string{}, cs.stacks[gid]...), name)
+	target := name
+	for i := 0; i <= len(cs.owners); i++ {
+		owner, ok := cs.owners[target]
+		if !ok {
+			break
+		}
+		if owner == gid {
+			return 0, fmt.Errorf("re-entrant resolution of instance %s: %s", name, strings.Join(path, " -> "))
+		}
+		next, ok := cs.waiting[owner]
+		if !ok {
+			break
+		}
+		ownerStack := cs.stacks[owner]
+		for j, n := range ownerStack {
+			if n == target {
+				path = append(path, ownerStack[j+1:]...)
+				break
+			}
+		}
+		path = append(path, next)
+		target = next
+	}
+
+	cs.waiting[gid] = name
+	return gid, nil
+}
+
+// awaited records that the current goroutine finishes waiting.
+func (cs *constructions) awaited(gid uint64) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	delete(cs.waiting, gid)
+}
+
+// goroutineID returns the id of the current goroutine, parsed from the header of its stack trace, e.g.
+// "goroutine 18 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	b := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,89 @@
+package alice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ReentrantModule struct {
+	BaseModule
+	c *Container
+}
+
+func (m *ReentrantModule) D1() D1 {
+	(*m.c).InstanceByName("D2")
+	return &D1Impl{}
+}
+
+func (m *ReentrantModule) D2() D2 {
+	(*m.c).InstanceByName("D1")
+	return &D2Impl{}
+}
+
+type ConcurrentReentrantModule struct {
+	BaseModule
+	c *Container
+}
+
+func (m *ConcurrentReentrantModule) D1() D1 {
+	time.Sleep(10 * time.Millisecond)
+	(*m.c).InstanceByName("D2")
+	return &D1Impl{}
+}
+
+func (m *ConcurrentReentrantModule) D2() D2 {
+	time.Sleep(10 * time.Millisecond)
+	(*m.c).InstanceByName("D1")
+	return &D2Impl{}
+}
+
+func TestReentrantResolution(t *testing.T) {
+	var c Container
+	c = CreateContainerWithOptions([]Module{&ReentrantModule{c: &c}}, WithLazy())
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("expected panic for re-entrant resolution")
+		}
+		if !strings.Contains(fmt.Sprint(r), "re-entrant resolution of instance D1: D1 -> D2 -> D1") {
+			t.Errorf("bad panic after InstanceByName(): got %v", r)
+		}
+	}()
+	c.InstanceByName("D1")
+}
+
+func TestReentrantResolution_Concurrent(t *testing.T) {
+	var c Container
+	c = CreateContainerWithOptions([]Module{&ConcurrentReentrantModule{c: &c}}, WithLazy(), WithWarmParallelism(2))
+
+	done := make(chan error)
+	go func() {
+		done <- c.(Lifecycle).Warm(context.Background(), "D1", "D2")
+	}()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "re-entrant resolution") {
+			t.Errorf("bad error after Warm(): got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Warm() deadlocked")
+	}
+}
+
+func TestGoroutineID(t *testing.T) {
+	id := goroutineID()
+	if id == 0 {
+		t.Errorf("bad goroutine id: got %d", id)
+	}
+	done := make(chan uint64)
+	go func() {
+		done <- goroutineID()
+	}()
+	if other := <-done; other == id || other == 0 {
+		t.Errorf("bad goroutine id of another goroutine: got %d, current %d", other, id)
+	}
+}
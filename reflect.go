@@ -3,10 +3,67 @@ package alice
 import (
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
+	"unsafe"
 )
 
 const _Tag = "alice"
+const _NamesTagPrefix = "names="
+const _GroupTagPrefix = "group="
+const _ChainTagPrefix = "chain="
+const _MapTag = ",map"
+const _PrefixTagPrefix = "prefix="
+const _LazyTagSuffix = ",lazy"
 const _IsModuleMethodName = "IsModule"
+const _BackgroundInstancesMethodName = "BackgroundInstances"
+const _DescribeMethodName = "Describe"
+const _NamespaceMethodName = "Namespace"
+const _DeprecatedMethodName = "Deprecated"
+const _GroupsMethodName = "Groups"
+const _FallbacksMethodName = "Fallbacks"
+const _GatesMethodName = "Gates"
+const _ViewsMethodName = "Views"
+const _MigrateMethodName = "Migrate"
+const _SynchronizedMethodName = "Synchronized"
+const _TestVariantMethodName = "TestVariant"
+const _NonCriticalMethodName = "NonCritical"
+const _SandboxMethodName = "Sandbox"
+const _VerifyMethodName = "Verify"
+const _NilableMethodName = "Nilable"
+const _IsolatedMethodName = "Isolated"
+const _ConditionsMethodName = "Conditions"
+
+// _reservedMethods maps the names of the methods defined by the Module and optional module interfaces to the
+// interfaces. A method is not treated as an instance method if the module implements the interface defining it, so an
+// instance method with the same name but another signature, like Verify() *Verifier, is kept.
+var _reservedMethods = map[string]reflect.Type{
+	_IsModuleMethodName:            reflect.TypeOf((*Module)(nil)).Elem(),
+	_BackgroundInstancesMethodName: reflect.TypeOf((*BackgroundModule)(nil)).Elem(),
+	_DescribeMethodName:            reflect.TypeOf((*DescribedModule)(nil)).Elem(),
+	_NamespaceMethodName:           reflect.TypeOf((*NamespacedModule)(nil)).Elem(),
+	_DeprecatedMethodName:          reflect.TypeOf((*DeprecatedModule)(nil)).Elem(),
+	_GroupsMethodName:              reflect.TypeOf((*GroupedModule)(nil)).Elem(),
+	_FallbacksMethodName:           reflect.TypeOf((*FallbackModule)(nil)).Elem(),
+	_GatesMethodName:               reflect.TypeOf((*GatedModule)(nil)).Elem(),
+	_ViewsMethodName:               reflect.TypeOf((*ViewedModule)(nil)).Elem(),
+	_MigrateMethodName:             reflect.TypeOf((*MigratedModule)(nil)).Elem(),
+	_SynchronizedMethodName:        reflect.TypeOf((*SynchronizedModule)(nil)).Elem(),
+	_TestVariantMethodName:         reflect.TypeOf((*TestVariantModule)(nil)).Elem(),
+	_NonCriticalMethodName:         reflect.TypeOf((*DegradableModule)(nil)).Elem(),
+	_SandboxMethodName:             reflect.TypeOf((*SandboxedModule)(nil)).Elem(),
+	_VerifyMethodName:              reflect.TypeOf((*VerifiedModule)(nil)).Elem(),
+	_NilableMethodName:             reflect.TypeOf((*NilableModule)(nil)).Elem(),
+	_IsolatedMethodName:            reflect.TypeOf((*IsolatedModule)(nil)).Elem(),
+	_ConditionsMethodName:          reflect.TypeOf((*ConditionalModule)(nil)).Elem(),
+}
+
+// reservedMethod reports whether the method of a module pointer type is defined by an optional module interface the
+// module implements.
+func reservedMethod(ptrT reflect.Type, name string) bool {
+	iface, ok := _reservedMethods[name]
+	return ok && ptrT.Implements(iface)
+}
 
 // reflectedModule contains the instance and dependency information of a Module. The information is extracted
 // using reflection.
@@ -17,80 +74,655 @@ type reflectedModule struct {
 	instances    []*instanceMethod
 	namedDepends []*namedField
 	typedDepends []*typedField
+	listDepends  []*listField
+	// description is the human-readable description of the module.
+	description string
+	// deprecated indicates the whole module is deprecated. replacement is the hint of what to use instead.
+	deprecated  bool
+	replacement string
 }
 
 type instanceMethod struct {
-	name   string
-	tp     reflect.Type
-	method reflect.Value
+	name string
+	// shortName is the name without the namespace, if namespaces are enabled.
+	shortName string
+	tp        reflect.Type
+	method    reflect.Value
+	// params are the parameter types of the method. They are dependencies associated by type.
+	params []reflect.Type
+	// paramsStruct is the parameters struct if the method takes one instead of positional parameters.
+	paramsStruct *paramsStruct
+	// background indicates the instance is constructed on a background goroutine.
+	background bool
+	// description is the human-readable description of the instance.
+	description string
+	// deprecated indicates the instance is deprecated. replacement is the hint of what to use instead.
+	deprecated  bool
+	replacement string
+	// contributions are the groups the instance is contributed to.
+	contributions []Contribution
+	// fallback indicates the instance is removed if another instance has the same type or name.
+	fallback bool
+	// synchronized indicates the instance is wrapped in a proxy guarded by a mutex.
+	synchronized bool
+	// degradation is set if the instance is non-critical.
+	degradation *degradation
+	// nilable indicates the instance could be nil.
+	nilable bool
+	// isolation is set if the instance is constructed on a dedicated goroutine.
+	isolation *Isolation
+	// condition is set if the instance is restricted to platforms or capabilities.
+	condition *Condition
+	// sandbox is the sandbox of the module, applied while the instance method is called.
+	sandbox *Sandbox
+	// gate switches the instance to an alternative by a feature flag.
+	gate *instanceGate
 }
 
 type namedField struct {
 	name  string
 	field reflect.Value
+	// fieldName is the struct field name, or empty for a built module.
+	fieldName string
+	// lazy indicates the field is set to a proxy, which retrieves the instance on its first call.
+	lazy bool
 }
 
 type typedField struct {
 	tp    reflect.Type
 	field reflect.Value
+	// fieldName is the struct field name, or empty for a built module.
+	fieldName string
+	// lazy indicates the field is set to a proxy, which retrieves the instance on its first call.
+	lazy bool
+}
+
+// listField is a dependency of slice type, filled by the instances with the names in order. If group is not empty,
+// the names are the instances contributed to the group, figured out during graph construction. If keyed is true,
+// it is a dependency of map type keyed by instance names, and the names are the instances of other modules assignable
+// to the value type. If prefix is also not empty, only the names with the prefix are included, keyed without it. If
+// chain is true, it is a dependency of function type, filled by a function calling the
+// instances contributed to the group in order.
+type listField struct {
+	names  []string
+	group  string
+	keyed  bool
+	prefix string
+	chain  bool
+	field  reflect.Value
+	// fieldName is the struct field name, or empty for a built module.
+	fieldName string
+}
+
+// computed checks if the names are figured out during graph construction.
+func (f *listField) computed() bool {
+	return f.group != "" || f.keyed
+}
+
+// elemType returns the type each instance is assigned to.
+func (f *listField) elemType() reflect.Type {
+	if f.chain {
+		return f.field.Type()
+	}
+	return f.field.Type().Elem()
+}
+
+// moduleType contains the instance and dependency information of a module type. It doesn't depend on a specific
+// module value, so it is computed once per type and cached.
+type moduleType struct {
+	name string
+
+	instances    []instanceMethodType
+	namedDepends []namedFieldType
+	typedDepends []typedFieldType
+	listDepends  []listFieldType
+}
+
+type instanceMethodType struct {
+	name         string
+	tp           reflect.Type
+	params       []reflect.Type
+	paramsStruct *paramsStruct
+	index        int
+}
+
+type namedFieldType struct {
+	name  string
+	index []int
+	lazy  bool
+}
+
+type typedFieldType struct {
+	tp    reflect.Type
+	index []int
+	lazy  bool
+}
+
+type listFieldType struct {
+	names  []string
+	group  string
+	keyed  bool
+	prefix string
+	chain  bool
+	index  []int
+}
+
+// moduleTypeCache caches the moduleType or the error of reflecting it, keyed by the pointer type of the module.
+var moduleTypeCache sync.Map
+
+// moduleTypeResult is the value stored in moduleTypeCache.
+type moduleTypeResult struct {
+	mt  *moduleType
+	err error
 }
 
 // reflectModule creates a reflectedModule from a Module. It returns error if the Module is not properly defined.
 func reflectModule(m Module) (*reflectedModule, error) {
+	if f, ok := m.(ModuleFunc); ok {
+		m = f.build()
+	}
+	if fm, ok := m.(*funcModule); ok {
+		m = fm.build()
+	}
+	if bm, ok := m.(*builtModule); ok {
+		return reflectBuiltModule(bm)
+	}
+
+	m = addressableModule(m)
 	v := reflect.ValueOf(m)
 	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
-		return nil, fmt.Errorf("module %s is not a pointer of struct", v.String())
+		return nil, fmt.Errorf("module %s is not a struct or a pointer of struct", v.String())
+	}
+	allocateEmbedded(v.Elem())
+
+	mt, err := cachedModuleType(v.Type())
+	if err != nil {
+		return nil, err
 	}
 
-	// get instances
-	ptrT := v.Type()
 	var instances []*instanceMethod
+	for _, it := range mt.instances {
+		instances = append(instances, &instanceMethod{
+			name:         it.name,
+			tp:           it.tp,
+			method:       v.Method(it.index),
+			params:       it.params,
+			paramsStruct: it.paramsStruct.copy(),
+		})
+	}
+
+	if bm, ok := m.(BackgroundModule); ok {
+		if err := markBackgroundInstances(mt.name, instances, bm.BackgroundInstances()); err != nil {
+			return nil, err
+		}
+	}
+	if fm, ok := m.(FallbackModule); ok {
+		if err := markFallbackInstances(mt.name, instances, fm.Fallbacks()); err != nil {
+			return nil, err
+		}
+	}
+	if sm, ok := m.(SynchronizedModule); ok {
+		if err := markSynchronizedInstances(mt.name, instances, sm.Synchronized()); err != nil {
+			return nil, err
+		}
+	}
+	if dm, ok := m.(DegradableModule); ok {
+		if err := markNonCriticalInstances(mt.name, instances, dm.NonCritical()); err != nil {
+			return nil, err
+		}
+	}
+	if im, ok := m.(IsolatedModule); ok {
+		if err := markIsolatedInstances(mt.name, instances, im.Isolated()); err != nil {
+			return nil, err
+		}
+	}
+	if nm, ok := m.(NilableModule); ok {
+		if err := markNilableInstances(mt.name, instances, nm.Nilable()); err != nil {
+			return nil, err
+		}
+	}
+	if cm, ok := m.(ConditionalModule); ok {
+		if err := markConditionalInstances(mt.name, instances, cm.Conditions()); err != nil {
+			return nil, err
+		}
+	}
+	if sm, ok := m.(SandboxedModule); ok {
+		sandbox := sm.Sandbox()
+		for _, instance := range instances {
+			instance.sandbox = &sandbox
+		}
+	}
+	if vm, ok := m.(ViewedModule); ok {
+		if err := viewInstances(mt.name, instances, vm.Views()); err != nil {
+			return nil, err
+		}
+	}
+	if gm, ok := m.(GatedModule); ok {
+		if err := gateInstances(mt.name, instances, gm.Gates()); err != nil {
+			return nil, err
+		}
+	}
+	if gm, ok := m.(GroupedModule); ok {
+		if err := contributeInstances(mt.name, instances, gm.Groups()); err != nil {
+			return nil, err
+		}
+	}
+	var descriptions map[string]string
+	if dm, ok := m.(DescribedModule); ok {
+		descriptions = dm.Describe()
+	}
+	var deprecations map[string]string
+	if dm, ok := m.(DeprecatedModule); ok {
+		deprecations = dm.Deprecated()
+	}
+
+	var namedDepends []*namedField
+	for _, ft := range mt.namedDepends {
+		namedDepends = append(namedDepends, &namedField{
+			name:      ft.name,
+			field:     moduleField(v.Elem(), ft.index),
+			fieldName: v.Elem().Type().FieldByIndex(ft.index).Name,
+			lazy:      ft.lazy,
+		})
+	}
+	var typedDepends []*typedField
+	for _, ft := range mt.typedDepends {
+		typedDepends = append(typedDepends, &typedField{
+			tp:        ft.tp,
+			field:     moduleField(v.Elem(), ft.index),
+			fieldName: v.Elem().Type().FieldByIndex(ft.index).Name,
+			lazy:      ft.lazy,
+		})
+	}
+	var listDepends []*listField
+	for _, ft := range mt.listDepends {
+		listDepends = append(listDepends, &listField{
+			names:     append([]string{}, ft.names...), // names could be qualified per container
+			group:     ft.group,
+			keyed:     ft.keyed,
+			prefix:    ft.prefix,
+			chain:     ft.chain,
+			field:     moduleField(v.Elem(), ft.index),
+			fieldName: v.Elem().Type().FieldByIndex(ft.index).Name,
+		})
+	}
+
+	rm := &reflectedModule{
+		m:            m,
+		name:         mt.name,
+		instances:    instances,
+		namedDepends: namedDepends,
+		typedDepends: typedDepends,
+		listDepends:  listDepends,
+	}
+	if err := describe(rm, descriptions); err != nil {
+		return nil, err
+	}
+	if err := deprecate(rm, deprecations); err != nil {
+		return nil, err
+	}
+	return rm, nil
+}
+
+// cachedModuleType returns the moduleType of a pointer of struct type. Only the first call for a type does the
+// reflection work.
+func cachedModuleType(ptrT reflect.Type) (*moduleType, error) {
+	if result, ok := moduleTypeCache.Load(ptrT); ok {
+		r := result.(*moduleTypeResult)
+		return r.mt, r.err
+	}
+
+	mt, err := reflectModuleType(ptrT)
+	moduleTypeCache.Store(ptrT, &moduleTypeResult{mt: mt, err: err})
+	return mt, err
+}
+
+// reflectModuleType extracts the instance and dependency information from a pointer of struct type.
+func reflectModuleType(ptrT reflect.Type) (*moduleType, error) {
+	t := ptrT.Elem()
+
+	// get instances
+	var instances []instanceMethodType
 	for i := 0; i < ptrT.NumMethod(); i++ {
 		method := ptrT.Method(i)
-		if method.Name == _IsModuleMethodName {
+		if reservedMethod(ptrT, method.Name) {
 			continue
 		}
-		if method.Type.NumIn() != 1 || method.Type.NumOut() != 1 { // receiver is the first parameter
-			return nil, fmt.Errorf("method %s.%s doesn't have 0 parameter and 1 return value",
-				v.Elem().Type().Name(), method.Name)
+		if method.Type.NumOut() != 1 {
+			return nil, fmt.Errorf("method %s.%s doesn't have 1 return value", t.Name(), method.Name)
 		}
-		instances = append(instances, &instanceMethod{
-			name:   method.Name,
-			tp:     method.Type.Out(0),
-			method: v.MethodByName(method.Name),
+		params, ps, err := reflectParams(method.Type, 1) // receiver is the first parameter
+		if err != nil {
+			return nil, fmt.Errorf("method %s.%s: %s", t.Name(), method.Name, err.Error())
+		}
+		instances = append(instances, instanceMethodType{
+			name:         method.Name,
+			tp:           method.Type.Out(0),
+			params:       params,
+			paramsStruct: ps,
+			index:        i,
 		})
 	}
 
-	// get dependencies
-	t := v.Elem().Type()
-	var namedDepends []*namedField
-	var typedDepends []*typedField
+	// get dependencies, including the fields of embedded structs
+	deps := &moduleType{}
+	if err := reflectFields(t, t, nil, make(map[reflect.Type]bool), deps); err != nil {
+		return nil, err
+	}
+
+	name := shortTypeName(t)
+	if name == "" { // anonymous struct
+		name = t.String()
+	}
+
+	return &moduleType{
+		name:         name,
+		instances:    instances,
+		namedDepends: deps.namedDepends,
+		typedDepends: deps.typedDepends,
+		listDepends:  deps.listDepends,
+	}, nil
+}
+
+// reflectFields adds the dependency fields of struct type t to mt. t is the module type itself or a struct embedded in
+// it, and index is the index sequence of t in the module type. Embedded structs, by value or by pointer, are walked
+// recursively.
+func reflectFields(moduleT reflect.Type, t reflect.Type, index []int, visited map[reflect.Type]bool,
+	mt *moduleType) error {
+	if visited[t] {
+		return nil
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
 		if field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := reflectFields(moduleT, embedded, fieldIndex, visited, mt); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		dependName, exists := field.Tag.Lookup(_Tag)
+		if !exists {
 			continue
 		}
+		lazy := strings.HasSuffix(dependName, _LazyTagSuffix)
+		if lazy {
+			dependName = strings.TrimSuffix(dependName, _LazyTagSuffix)
+			if strings.ContainsAny(dependName, "=,") {
+				return fmt.Errorf("field %s.%s of lazy dependency is not associated by name or type", moduleT.Name(),
+					field.Name)
+			}
+			if field.Type.Kind() != reflect.Func && field.Type.Kind() != reflect.Interface {
+				return fmt.Errorf("field %s.%s of lazy dependency is not a function or an interface", moduleT.Name(),
+					field.Name)
+			}
+		}
+		if strings.HasPrefix(dependName, _NamesTagPrefix) {
+			names, err := parseNames(moduleT.Name(), field, strings.TrimPrefix(dependName, _NamesTagPrefix))
+			if err != nil {
+				return err
+			}
+			mt.listDepends = append(mt.listDepends, listFieldType{
+				names: names,
+				index: fieldIndex,
+			})
+		} else if strings.HasPrefix(dependName, _GroupTagPrefix) {
+			group := strings.TrimPrefix(dependName, _GroupTagPrefix)
+			if field.Type.Kind() != reflect.Slice || group == "" {
+				return fmt.Errorf("field %s.%s of group is not a slice or has an empty group", moduleT.Name(),
+					field.Name)
+			}
+			mt.listDepends = append(mt.listDepends, listFieldType{
+				group: group,
+				index: fieldIndex,
+			})
+		} else if strings.HasPrefix(dependName, _ChainTagPrefix) {
+			group := strings.TrimPrefix(dependName, _ChainTagPrefix)
+			if field.Type.Kind() != reflect.Func || group == "" {
+				return fmt.Errorf("field %s.%s of chain is not a function or has an empty group", moduleT.Name(),
+					field.Name)
+			}
+			mt.listDepends = append(mt.listDepends, listFieldType{
+				group: group,
+				chain: true,
+				index: fieldIndex,
+			})
+		} else if dependName == _MapTag {
+			if field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String {
+				return fmt.Errorf("field %s.%s of map is not keyed by string", moduleT.Name(), field.Name)
+			}
+			mt.listDepends = append(mt.listDepends, listFieldType{
+				keyed: true,
+				index: fieldIndex,
+			})
+		} else if strings.HasPrefix(dependName, _PrefixTagPrefix) {
+			prefix := strings.TrimPrefix(dependName, _PrefixTagPrefix)
+			if field.Type.Kind() != reflect.Map || field.Type.Key().Kind() != reflect.String || prefix == "" {
+				return fmt.Errorf("field %s.%s of prefix is not a map keyed by string or has an empty prefix",
+					moduleT.Name(), field.Name)
+			}
+			mt.listDepends = append(mt.listDepends, listFieldType{
+				keyed:  true,
+				prefix: prefix,
+				index:  fieldIndex,
+			})
+		} else if dependName != "" {
+			mt.namedDepends = append(mt.namedDepends, namedFieldType{
+				name:  dependName,
+				index: fieldIndex,
+				lazy:  lazy,
+			})
+		} else {
+			mt.typedDepends = append(mt.typedDepends, typedFieldType{
+				tp:    field.Type,
+				index: fieldIndex,
+				lazy:  lazy,
+			})
+		}
+	}
+	return nil
+}
+
+// addressableModule returns a pointer to a copy of a module passed by value, so its dependency fields could be set and
+// the methods with pointer receivers could be called. Other modules are returned as they are.
+func addressableModule(m Module) Module {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Struct {
+		return m
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	if pm, ok := p.Interface().(Module); ok {
+		return pm
+	}
+	return m
+}
+
+// modulePtrType returns the pointer type of a module, whether it is passed by value or by pointer.
+func modulePtrType(m Module) reflect.Type {
+	t := reflect.TypeOf(m)
+	if t.Kind() == reflect.Struct {
+		return reflect.PtrTo(t)
+	}
+	return t
+}
 
-		if dependName, exists := field.Tag.Lookup(_Tag); exists {
-			if dependName != "" {
-				namedDepends = append(namedDepends, &namedField{
-					name:  dependName,
-					field: v.Elem().FieldByName(field.Name),
-				})
-			} else {
-				typedDepends = append(typedDepends, &typedField{
-					tp:    field.Type,
-					field: v.Elem().FieldByName(field.Name),
-				})
+// moduleField returns the field of a module struct value by the index sequence, allocating the nil pointers of
+// embedded structs along the way.
+func moduleField(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				settable(v).Set(reflect.New(v.Type().Elem()))
 			}
+			v = v.Elem()
 		}
+		v = v.Field(x)
 	}
+	return v
+}
 
-	return &reflectedModule{
-		m:            m,
-		name:         t.Name(),
-		instances:    instances,
-		namedDepends: namedDepends,
-		typedDepends: typedDepends,
-	}, nil
+// allocateEmbedded allocates the nil pointers of embedded structs in a module struct value, so the promoted methods
+// could be called.
+func allocateEmbedded(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		if !v.Type().Field(i).Anonymous {
+			continue
+		}
+		field := v.Field(i)
+		if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct {
+			if field.IsNil() {
+				settable(field).Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
+		}
+		if field.Kind() == reflect.Struct {
+			allocateEmbedded(field)
+		}
+	}
+}
+
+// parseNames parses the comma separated instance names of a dependency field of slice type.
+func parseNames(moduleName string, field reflect.StructField, value string) ([]string, error) {
+	if field.Type.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("field %s.%s with instance names is not a slice", moduleName, field.Name)
+	}
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("field %s.%s has an empty instance name", moduleName, field.Name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// markBackgroundInstances marks the instances with the specified names as background instances. It returns error if
+// any name is not an instance of the module.
+func markBackgroundInstances(moduleName string, instances []*instanceMethod, names []string) error {
+	for _, name := range names {
+		instance := findInstanceMethod(instances, name)
+		if instance == nil {
+			return fmt.Errorf("background instance %s.%s is not defined", moduleName, name)
+		}
+		instance.background = true
+	}
+	return nil
+}
+
+// markFallbackInstances marks the instances with the specified names as fallbacks. It returns error if any name is not
+// an instance of the module.
+func markFallbackInstances(moduleName string, instances []*instanceMethod, names []string) error {
+	for _, name := range names {
+		instance := findInstanceMethod(instances, name)
+		if instance == nil {
+			return fmt.Errorf("fallback instance %s.%s is not defined", moduleName, name)
+		}
+		instance.fallback = true
+	}
+	return nil
+}
+
+// viewInstances replaces the types of instances with their views. It returns error if any name is not an instance of
+// the module, or the instance type is not assignable to its view.
+func viewInstances(moduleName string, instances []*instanceMethod, views map[string]reflect.Type) error {
+	for name, view := range views {
+		instance := findInstanceMethod(instances, name)
+		if instance == nil {
+			return fmt.Errorf("viewed instance %s.%s is not defined", moduleName, name)
+		}
+		if view == nil || !instance.tp.AssignableTo(view) {
+			return fmt.Errorf("instance %s.%s of type %s is not assignable to view %v", moduleName, name, instance.tp,
+				view)
+		}
+		instance.tp = view
+	}
+	return nil
+}
+
+// describe sets the descriptions of the module, keyed by the empty name, and its instances. It returns error if any
+// other name is not an instance of the module.
+func describe(rm *reflectedModule, descriptions map[string]string) error {
+	for name, description := range descriptions {
+		if name == "" {
+			rm.description = description
+			continue
+		}
+		instance := findInstanceMethod(rm.instances, name)
+		if instance == nil {
+			return fmt.Errorf("described instance %s.%s is not defined", rm.name, name)
+		}
+		instance.description = description
+	}
+	return nil
+}
+
+// deprecate marks the module or its instances deprecated. It returns error if any name is not an instance of the
+// module.
+func deprecate(rm *reflectedModule, deprecations map[string]string) error {
+	for name, replacement := range deprecations {
+		if name == "" {
+			rm.deprecated = true
+			rm.replacement = replacement
+			continue
+		}
+		instance := findInstanceMethod(rm.instances, name)
+		if instance == nil {
+			return fmt.Errorf("deprecated instance %s.%s is not defined", rm.name, name)
+		}
+		instance.deprecated = true
+		instance.replacement = replacement
+	}
+	return nil
+}
+
+// contributeInstances adds the instances to groups. It returns error if any name is not an instance of the module.
+func contributeInstances(moduleName string, instances []*instanceMethod, groups map[string]Contribution) error {
+	for name, contribution := range groups {
+		instance := findInstanceMethod(instances, name)
+		if instance == nil {
+			return fmt.Errorf("contributed instance %s.%s is not defined", moduleName, name)
+		}
+		instance.contributions = append(instance.contributions, contribution)
+	}
+	return nil
+}
+
+// findInstanceMethod returns the instance method with the specified name, or nil if it is not found.
+func findInstanceMethod(instances []*instanceMethod, name string) *instanceMethod {
+	for _, instance := range instances {
+		if instance.name == name {
+			return instance
+		}
+	}
+	return nil
+}
+
+// settable returns a settable value of an addressable field. Unexported fields are allowed, so modules created by
+// factory functions could keep their dependencies private.
+func settable(field reflect.Value) reflect.Value {
+	if field.CanSet() {
+		return field
+	}
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+}
+
+// methodParams returns the parameter types of a function type, starting from the specified index.
+func methodParams(t reflect.Type, from int) []reflect.Type {
+	var params []reflect.Type
+	for i := from; i < t.NumIn(); i++ {
+		params = append(params, t.In(i))
+	}
+	return params
 }
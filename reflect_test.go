@@ -32,11 +32,11 @@ func (m *nonStructModule) IsModule() bool {
 	return true
 }
 
-type invalidMethodModule1 struct {
+type paramMethodModule struct {
 	BaseModule
 }
 
-func (m *invalidMethodModule1) Dep1(str string) D1 {
+func (m *paramMethodModule) Dep1(d2 D2, str string) D1 {
 	return &D1Impl{}
 }
 
@@ -83,8 +83,9 @@ func TestReflectModule(t *testing.T) {
 
 	expectedNamedDepends := []*namedField{
 		{
-			name:  "Dep2",
-			field: reflect.ValueOf(m).Elem().FieldByName("dep2"),
+			name:      "Dep2",
+			field:     reflect.ValueOf(m).Elem().FieldByName("dep2"),
+			fieldName: "dep2",
 		},
 	}
 	if !reflect.DeepEqual(rmodule.namedDepends, expectedNamedDepends) {
@@ -94,8 +95,9 @@ func TestReflectModule(t *testing.T) {
 
 	expectedTypedDpends := []*typedField{
 		{
-			tp:    reflect.TypeOf((*D1)(nil)).Elem(),
-			field: reflect.ValueOf(m).Elem().FieldByName("dep1"),
+			tp:        reflect.TypeOf((*D1)(nil)).Elem(),
+			field:     reflect.ValueOf(m).Elem().FieldByName("dep1"),
+			fieldName: "dep1",
 		},
 	}
 	if !reflect.DeepEqual(rmodule.typedDepends, expectedTypedDpends) {
@@ -104,36 +106,231 @@ func TestReflectModule(t *testing.T) {
 	}
 }
 
-func TestReflectModule_InvalidModuleType(t *testing.T) {
-	nonPtrModule := nonPointerModule{}
+type invalidBackgroundModule struct {
+	BaseModule
+}
+
+func (m *invalidBackgroundModule) Dep1() D1 {
+	return &D1Impl{}
+}
+
+func (m *invalidBackgroundModule) BackgroundInstances() []string {
+	return []string{"Dep2"}
+}
+
+func TestReflectModule_BackgroundInstances(t *testing.T) {
+	rmodule, err := reflectModule(&BackgroundModule1{})
+	if err != nil {
+		t.Errorf("unexpected error after reflectModule(): %s", err.Error())
+	}
+	if len(rmodule.instances) != 2 {
+		t.Fatalf("bad number of instances in reflectedModule: got %d, expected 2", len(rmodule.instances))
+	}
+	for _, instance := range rmodule.instances {
+		expected := instance.name == "D1"
+		if instance.background != expected {
+			t.Errorf("bad background of instance %s: got %v, expected %v", instance.name, instance.background, expected)
+		}
+	}
 
-	_, err := reflectModule(nonPtrModule)
+	_, err = reflectModule(&invalidBackgroundModule{})
 	if err == nil {
-		t.Error("expect error after reflectModule() on non-pointer module")
+		t.Error("expect error after reflectModule() on undefined background instance")
 	}
 	t.Log(err.Error())
+}
 
+func TestReflectModule_InvalidModuleType(t *testing.T) {
 	nonStructModule := nonStructModule("module")
-	_, err = reflectModule(&nonStructModule)
+	_, err := reflectModule(&nonStructModule)
 	if err == nil {
 		t.Error("expect error after reflectModule() on non-struct module")
 	}
 	t.Log(err.Error())
 }
 
-func TestReflectModule_InvalidMethod(t *testing.T) {
-	m1 := &invalidMethodModule1{}
-
-	_, err := reflectModule(m1)
-	if err == nil {
-		t.Error("expect error after reflectModule() on module with 1 parameter method")
+func TestReflectModule_MethodParams(t *testing.T) {
+	rmodule, err := reflectModule(&paramMethodModule{})
+	if err != nil {
+		t.Errorf("unexpected error after reflectModule(): %s", err.Error())
 	}
-	t.Log(err.Error())
+	expectedParams := []reflect.Type{reflect.TypeOf((*D2)(nil)).Elem(), reflect.TypeOf("")}
+	if !reflect.DeepEqual(rmodule.instances[0].params, expectedParams) {
+		t.Errorf("bad params in reflectedModule: got %v, expected %v", rmodule.instances[0].params, expectedParams)
+	}
+}
 
+func TestReflectModule_InvalidMethod(t *testing.T) {
 	m2 := &invalidMethodModule2{}
-	_, err = reflectModule(m2)
+	_, err := reflectModule(m2)
 	if err == nil {
 		t.Error("expect error after reflectModule() on module with 2 return values method")
 	}
 	t.Log(err.Error())
 }
+
+type Verifier struct{}
+
+// reservedNamesModule defines instance methods named like the methods of optional module interfaces, with other
+// signatures.
+type reservedNamesModule struct {
+	BaseModule
+}
+
+func (m *reservedNamesModule) Verify() *Verifier {
+	return &Verifier{}
+}
+
+func (m *reservedNamesModule) Describe() string {
+	return "description"
+}
+
+func (m *reservedNamesModule) Migrate() D1 {
+	return &D1Impl{}
+}
+
+func TestReflectModule_ReservedMethodNames(t *testing.T) {
+	rm, err := reflectModule(&reservedNamesModule{})
+	if err != nil {
+		t.Fatalf("bad error after reflectModule(): got %v, expected nil", err)
+	}
+	for _, name := range []string{"Verify", "Describe", "Migrate"} {
+		if findInstanceMethod(rm.instances, name) == nil {
+			t.Errorf("bad instances after reflectModule(): %s is missing", name)
+		}
+	}
+
+	c := CreateContainer(&reservedNamesModule{})
+	if _, ok := c.InstanceByName("Verify").(*Verifier); !ok {
+		t.Error("expected the Verify instance")
+	}
+	// Verify(Container) error implements VerifiedModule, so it is not an instance method
+	if rm, _ := reflectModule(&verifiedModule{}); findInstanceMethod(rm.instances, "Verify") != nil {
+		t.Error("expected Verify of VerifiedModule not to be an instance method")
+	}
+}
+
+func TestReflectModule_CachedModuleType(t *testing.T) {
+	m1 := &reflectTestModule{}
+	m2 := &reflectTestModule{}
+
+	rm1, err := reflectModule(m1)
+	if err != nil {
+		t.Errorf("unexpected error after reflectModule(): %s", err.Error())
+	}
+	if _, ok := moduleTypeCache.Load(reflect.TypeOf(m1)); !ok {
+		t.Error("module type is expected to be cached after reflectModule()")
+	}
+	rm2, err := reflectModule(m2)
+	if err != nil {
+		t.Errorf("unexpected error after reflectModule(): %s", err.Error())
+	}
+
+	// the cached metadata must be bound to the specific module value
+	if rm2.m != m2 {
+		t.Errorf("bad m in reflectedModule: got %v, expected %v", rm2.m, m2)
+	}
+	if rm1.namedDepends[0].field.Addr().Pointer() == rm2.namedDepends[0].field.Addr().Pointer() {
+		t.Error("fields of different modules are expected to be different")
+	}
+	expectedField := reflect.ValueOf(m2).Elem().FieldByName("dep2")
+	if !reflect.DeepEqual(rm2.namedDepends[0].field, expectedField) {
+		t.Errorf("bad field in reflectedModule: got %v, expected %v", rm2.namedDepends[0].field, expectedField)
+	}
+
+	// errors are cached as well
+	if _, err := reflectModule(&invalidMethodModule2{}); err == nil {
+		t.Error("expect error after reflectModule() on module with 2 return values method")
+	}
+	if _, err := reflectModule(&invalidMethodModule2{}); err == nil {
+		t.Error("expect cached error after reflectModule() on module with 2 return values method")
+	}
+}
+
+func TestReflectModule_AnonymousModule(t *testing.T) {
+	m := &struct {
+		BaseModule
+		D1 D1 `alice:""`
+	}{}
+
+	rmodule, err := reflectModule(m)
+	if err != nil {
+		t.Errorf("unexpected error after reflectModule(): %s", err.Error())
+	}
+	if rmodule.name == "" {
+		t.Error("anonymous module is expected to have a name")
+	}
+	t.Log(rmodule.name)
+}
+
+type listModule struct {
+	BaseModule
+	Ds []interface{} `alice:"names=D2, D1"`
+}
+
+type invalidListModule1 struct {
+	BaseModule
+	D D1 `alice:"names=D1"`
+}
+
+type invalidListModule2 struct {
+	BaseModule
+	Ds []D1 `alice:"names=D1,,D2"`
+}
+
+func TestReflectModule_ListDepends(t *testing.T) {
+	rm, err := reflectModule(&listModule{})
+	if err != nil {
+		t.Fatalf("bad error after reflectModule(): got %v, expected nil", err)
+	}
+	if len(rm.listDepends) != 1 {
+		t.Fatalf("bad number of list dependencies: got %d, expected %d", len(rm.listDepends), 1)
+	}
+	if expected := []string{"D2", "D1"}; !reflect.DeepEqual(rm.listDepends[0].names, expected) {
+		t.Errorf("bad names of list dependency: got %v, expected %v", rm.listDepends[0].names, expected)
+	}
+
+	for _, m := range []Module{&invalidListModule1{}, &invalidListModule2{}} {
+		if _, err := reflectModule(m); err == nil {
+			t.Errorf("expected error after reflectModule() on %T", m)
+		}
+	}
+}
+
+type embeddedDepends struct {
+	D1 D1 `alice:""`
+}
+
+type embeddedBase struct {
+	*BaseModule
+	*embeddedDepends
+}
+
+type nestedModule struct {
+	embeddedBase
+	D2 D2 `alice:"D2"`
+}
+
+func (m nestedModule) D5() *D5Impl {
+	return &D5Impl{}
+}
+
+func TestReflectModule_ByValueAndEmbedded(t *testing.T) {
+	rm, err := reflectModule(nonPointerModule{})
+	if err != nil {
+		t.Errorf("bad error after reflectModule() on non-pointer module: got %v, expected nil", err)
+	} else if _, ok := rm.m.(*nonPointerModule); !ok {
+		t.Errorf("bad m after reflectModule() on non-pointer module: got %T, expected *nonPointerModule", rm.m)
+	}
+
+	c := CreateContainer(&M1{}, nestedModule{})
+	if c.InstanceByName("D5") == nil {
+		t.Error("expected instance of module passed by value")
+	}
+
+	m := &nestedModule{}
+	CreateContainer(&M1{}, m)
+	if m.D1 == nil || m.D2 == nil {
+		t.Errorf("bad dependencies of nested embedded structs: got %v and %v, expected non-nil", m.D1, m.D2)
+	}
+}
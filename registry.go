@@ -0,0 +1,202 @@
+package alice
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Spec is the declarative wiring of a container, listing the modules by the names of registered factories. It could
+// be decoded from a manifest file, see the manifest package, or built from stored configuration, e.g. per customer.
+type Spec struct {
+	// Modules are the modules of the container, in order.
+	Modules []ModuleSpec `json:"modules" yaml:"modules"`
+	// Bindings are plain values provided as named instances, like Values.
+	Bindings map[string]interface{} `json:"bindings,omitempty" yaml:"bindings,omitempty"`
+}
+
+// ModuleSpec declares a module created by a registered factory.
+type ModuleSpec struct {
+	// Factory is the name of the registered factory.
+	Factory string `json:"factory" yaml:"factory"`
+	// Params are decoded into the parameter of the factory, if it takes one.
+	Params map[string]interface{} `json:"params,omitempty" yaml:"params,omitempty"`
+	// Profiles are the profiles the module belongs to. The module is included if any of them is active, or always if
+	// it is empty.
+	Profiles []string `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+}
+
+var moduleInterfaceType = reflect.TypeOf((*Module)(nil)).Elem()
+
+// Registry contains the module factories specs refer to. It is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]reflect.Value
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]reflect.Value)}
+}
+
+// Register registers a factory by name. A factory is a function returning a Module, taking no parameter or a
+// parameter the params of module specs are decoded into, usually a configuration struct. It panics with an error if
+// the factory is invalid or the name is registered already.
+func (r *Registry) Register(name string, factory interface{}) {
+	v := reflect.ValueOf(factory)
+	if v.Kind() != reflect.Func || v.Type().NumIn() > 1 || v.Type().NumOut() != 1 ||
+		!v.Type().Out(0).AssignableTo(moduleInterfaceType) {
+		panic(fmt.Errorf("factory %s of type %T is not a function returning a module with at most one parameter",
+			name, factory))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.factories[name]; ok {
+		panic(fmt.Errorf("factory %s is registered already", name))
+	}
+	r.factories[name] = v
+}
+
+// Modules creates the modules of the spec with the active profiles. All module specs are validated against the
+// registered factories, including those of inactive profiles, so a typo is caught by any deployment. The factories
+// of inactive profiles are not called. It returns error if a factory is not registered, or the params could not be
+// decoded into its parameter.
+func (r *Registry) Modules(spec *Spec, profiles ...string) ([]Module, error) {
+	active := make(map[string]bool)
+	for _, p := range profiles {
+		active[p] = true
+	}
+	var modules []Module
+	var errs []error
+	for i, ms := range spec.Modules {
+		include := included(ms, active)
+		module, err := r.create(ms, include)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("module %d (%s): %w", i, ms.Factory, err))
+			continue
+		}
+		if include {
+			modules = append(modules, module)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, Errors(errs)
+	}
+	if len(spec.Bindings) > 0 {
+		modules = append(modules, Values("bindings", spec.Bindings))
+	}
+	return modules, nil
+}
+
+// CreateContainer creates a container from the modules of the spec with the active profiles. It returns error
+// instead of panicking if the spec or the wiring is invalid.
+func (r *Registry) CreateContainer(spec *Spec, profiles []string, opts ...Option) (Container, error) {
+	modules, err := r.Modules(spec, profiles...)
+	if err != nil {
+		return nil, err
+	}
+	return createContainerSafely(modules, opts)
+}
+
+// Factories returns the names of the registered factories, sorted.
+func (r *Registry) Factories() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var names []string
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// create calls the factory of a module spec. If call is false, only the factory and the params are validated, and
+// nil is returned.
+func (r *Registry) create(spec ModuleSpec, call bool) (Module, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[spec.Factory]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("factory %q is not registered", spec.Factory)
+	}
+	var args []reflect.Value
+	if factory.Type().NumIn() == 1 {
+		param, err := decodeParams(spec.Params, factory.Type().In(0))
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, param)
+	} else if len(spec.Params) > 0 {
+		return nil, fmt.Errorf("factory %q takes no params", spec.Factory)
+	}
+	if !call {
+		return nil, nil
+	}
+	module, _ := factory.Call(args)[0].Interface().(Module)
+	if module == nil {
+		return nil, fmt.Errorf("factory %q returned nil", spec.Factory)
+	}
+	return module, nil
+}
+
+// decodeParams decodes the params into a value of type t through JSON, rejecting unknown fields.
+func decodeParams(params map[string]interface{}, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t)
+	if params == nil {
+		return v.Elem(), nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("invalid params: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("invalid params for %s: %w", t, err)
+	}
+	return v.Elem(), nil
+}
+
+// included checks if a module spec is included with the active profiles.
+func included(spec ModuleSpec, active map[string]bool) bool {
+	if len(spec.Profiles) == 0 {
+		return true
+	}
+	for _, p := range spec.Profiles {
+		if active[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultRegistry is the registry of RegisterFactory.
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry returns the process-wide registry which RegisterFactory registers to, e.g. for tooling to list its
+// factories.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// RegisterFactory registers a factory by name to the process-wide registry, like Registry.Register. It is usually
+// called by the init functions of the packages providing modules, so tooling and manifests could refer to them by
+// identifiers:
+//
+//	func init() {
+//		alice.RegisterFactory("redis", func(config RedisConfig) alice.Module {
+//			return &RedisModule{Config: config}
+//		})
+//	}
+func RegisterFactory(name string, factory interface{}) {
+	defaultRegistry.Register(name, factory)
+}
+
+// CreateContainerFromSpec creates a container from the spec with the active profiles by the factories registered by
+// RegisterFactory, like Registry.CreateContainer.
+func CreateContainerFromSpec(spec *Spec, profiles []string, opts ...Option) (Container, error) {
+	return defaultRegistry.CreateContainer(spec, profiles, opts...)
+}
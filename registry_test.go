@@ -0,0 +1,81 @@
+package alice
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type SpecConfig struct {
+	Greeting string `json:"greeting"`
+}
+
+type SpecGreetingModule struct {
+	BaseModule
+	config SpecConfig
+}
+
+func (m *SpecGreetingModule) Greeting() string {
+	return m.config.Greeting
+}
+
+type SpecUserModule struct {
+	BaseModule
+	Greeting string `alice:"Greeting"`
+	Name     string `alice:"Name"`
+}
+
+func (m *SpecUserModule) Welcome() string {
+	return m.Greeting + ", " + m.Name
+}
+
+func TestCreateContainerFromSpec(t *testing.T) {
+	// the process-wide registry is replaced, so the test registers the factories again when it is repeated
+	registry := defaultRegistry
+	defaultRegistry = NewRegistry()
+	t.Cleanup(func() {
+		defaultRegistry = registry
+	})
+
+	RegisterFactory("spec-greeting", func(config SpecConfig) Module { return &SpecGreetingModule{config: config} })
+	RegisterFactory("spec-users", func() *SpecUserModule { return &SpecUserModule{} })
+	if names := DefaultRegistry().Factories(); !reflect.DeepEqual(names, []string{"spec-greeting", "spec-users"}) {
+		t.Errorf("bad factories after RegisterFactory(): got %v, expected %v", names,
+			[]string{"spec-greeting", "spec-users"})
+	}
+
+	spec := &Spec{
+		Modules: []ModuleSpec{
+			{Factory: "spec-greeting", Params: map[string]interface{}{"greeting": "hello"}},
+			{Factory: "spec-users"},
+		},
+		Bindings: map[string]interface{}{"Name": "alice"},
+	}
+	c, err := CreateContainerFromSpec(spec, nil)
+	if err != nil {
+		t.Fatalf("bad error after CreateContainerFromSpec(): got %v, expected nil", err)
+	}
+	if welcome := c.InstanceByName("Welcome"); welcome != "hello, alice" {
+		t.Errorf("bad instance after CreateContainerFromSpec(): got %v, expected %q", welcome, "hello, alice")
+	}
+
+	spec.Modules = append(spec.Modules, ModuleSpec{Factory: "redis"})
+	if _, err := CreateContainerFromSpec(spec, nil); err == nil || !strings.Contains(err.Error(), "is not registered") {
+		t.Errorf("bad error after CreateContainerFromSpec() with unknown factory: got %v", err)
+	}
+}
+
+func TestRegister_InvalidError(t *testing.T) {
+	r := NewRegistry()
+	r.Register("users", func() *SpecUserModule { return &SpecUserModule{} })
+	for _, factory := range []interface{}{func() int { return 0 }, func() Module { return nil }} {
+		func() {
+			defer func() {
+				if err, ok := recover().(error); !ok || !strings.Contains(err.Error(), "factory") {
+					t.Errorf("bad panic after Register() with %T: got %v, expected an error", factory, err)
+				}
+			}()
+			r.Register("users", factory)
+		}()
+	}
+}
@@ -0,0 +1,81 @@
+package alice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Handle is a reference to the current container of a long-running process. The container could be replaced by
+// Reload without downtime. Code serving requests should retrieve instances through the handle rather than keeping
+// the container.
+type Handle struct {
+	opts []Option
+
+	// mu serializes reloads.
+	mu      sync.Mutex
+	current atomic.Value // a containerRef
+}
+
+// containerRef wraps a container, so atomic.Value always stores the same concrete type.
+type containerRef struct {
+	c Container
+}
+
+// NewHandle creates a handle of a container. opts are the options the container is created with, which are used to
+// create the containers on reload.
+func NewHandle(c Container, opts ...Option) *Handle {
+	h := &Handle{opts: opts}
+	h.current.Store(containerRef{c: c})
+	return h
+}
+
+// Container returns the current container.
+func (h *Handle) Container() Container {
+	return h.current.Load().(containerRef).c
+}
+
+// Reload replaces the container of the handle with a new one created from the modules, without downtime. The new
+// container is created, warmed and started while the old one keeps serving. Then it is swapped in atomically, and the
+// old one is stopped. If the new container fails to be created or started, it is stopped and the old one is kept.
+// The returned error includes the failure of stopping the old container, if any, though the new one is in use then.
+func Reload(ctx context.Context, h *Handle, modules ...Module) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := ValidateWithOptions(modules, h.opts...); err != nil {
+		return err
+	}
+	next, err := createContainerSafely(modules, h.opts)
+	if err != nil {
+		return err
+	}
+	if err := next.Warm(ctx); err != nil {
+		next.Stop(ctx)
+		return err
+	}
+	if err := next.Start(ctx); err != nil {
+		next.Stop(ctx)
+		return err
+	}
+
+	old := h.Container()
+	h.current.Store(containerRef{c: next})
+	if lc, ok := old.(Lifecycle); ok {
+		if err := lc.Stop(ctx); err != nil {
+			return fmt.Errorf("failed to stop the old container: %w", err)
+		}
+	}
+	return nil
+}
+
+// createContainerSafely creates a container, converting the panic to an error.
+func createContainerSafely(modules []Module, opts []Option) (c extendedContainer, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(r)
+		}
+	}()
+	return CreateContainerWithOptions(modules, opts...).(extendedContainer), nil
+}
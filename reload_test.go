@@ -0,0 +1,51 @@
+package alice
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestReload(t *testing.T) {
+	oldLog := &lifecycleLog{}
+	old := CreateContainer(lifecycleModules(oldLog, nil)...)
+	if err := old.(Lifecycle).Start(context.Background()); err != nil {
+		t.Fatalf("bad error after Start(): got %v, expected nil", err)
+	}
+	h := NewHandle(old)
+
+	newLog := &lifecycleLog{}
+	if err := Reload(context.Background(), h, lifecycleModules(newLog, nil)...); err != nil {
+		t.Fatalf("bad error after Reload(): got %v, expected nil", err)
+	}
+	if h.Container() == old {
+		t.Error("bad container after Reload(): got the old one")
+	}
+	if expected := []string{"start DB", "start Server", "stop Server", "stop DB"}; !reflect.DeepEqual(
+		oldLog.get(), expected) {
+		t.Errorf("bad events of the old container after Reload(): got %v, expected %v", oldLog.get(), expected)
+	}
+	if expected := []string{"start DB", "start Server"}; !reflect.DeepEqual(newLog.get(), expected) {
+		t.Errorf("bad events of the new container after Reload(): got %v, expected %v", newLog.get(), expected)
+	}
+}
+
+func TestReload_Failure(t *testing.T) {
+	old := CreateContainer(&M1{})
+	h := NewHandle(old)
+
+	if err := Reload(context.Background(), h, &M2{}); err == nil {
+		t.Error("expected error after Reload() with invalid modules")
+	}
+
+	log := &lifecycleLog{}
+	if err := Reload(context.Background(), h, lifecycleModules(log, map[string]string{"Server": "start"})...); err == nil {
+		t.Error("expected error after Reload() with failing instance")
+	}
+	if h.Container() != old {
+		t.Error("bad container after failed Reload(): expected the old one")
+	}
+	if expected := []string{"start DB", "stop Server", "stop DB"}; !reflect.DeepEqual(log.get(), expected) {
+		t.Errorf("bad events of the new container after failed Reload(): got %v, expected %v", log.get(), expected)
+	}
+}
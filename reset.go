@@ -0,0 +1,121 @@
+package alice
+
+func (c *container) Reset(names ...string) {
+	var qualified []string
+	for _, name := range names {
+		q, err := c.definedName(name)
+		if err != nil {
+			panic(err)
+		}
+		qualified = append(qualified, q)
+	}
+
+	resetNames, resetModules := c.dependentsOf(qualified)
+	// counted once the instances are reconstructed, so accessors retrieve them again
+	defer c.resets.Add(1)
+
+	c.mu.Lock()
+	for name := range resetNames {
+		delete(c.instanceByName, name)
+	}
+	c.interfaces.remove(resetNames)
+	c.rebuildInstanceByType()
+	c.resetAdaptations(resetNames)
+	if c.lazyByName != nil {
+		for name := range resetNames {
+			li := c.lazyByName[name]
+			li.done = nil
+			li.recovered = nil
+			if resetModules[li.module.rm] {
+				li.module.done = nil
+				li.module.recovered = nil
+			}
+		}
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+
+	// rebuild in instantiation order, so dependencies are rebuilt before dependents
+	for _, rm := range c.reflected {
+		if resetModules[rm] {
+			c.injectModule(rm)
+		}
+		for _, instance := range rm.instances {
+			if resetNames[instance.name] {
+				c.addInstance(instance.name, instance.tp, c.constructInstance(instance))
+			}
+		}
+	}
+}
+
+// definedName returns the fully qualified name of an instance. It returns error if the instance is not defined.
+func (c *container) definedName(name string) (string, error) {
+	if c.shortNames != nil {
+		q, err := c.shortNames.qualify(name)
+		if err != nil {
+			return "", err
+		}
+		name = q
+	}
+	if findInstanceMethodInModules(c.reflected, name) == nil {
+		return "", &LookupError{Name: name, Err: ErrNotFound}
+	}
+	return name, nil
+}
+
+// dependentsOf returns the names of the specified instances and all instances depending on them transitively, and
+// the modules whose dependencies need to be injected again. As dependencies are injected into module fields, all
+// instances of a depending module are dependents.
+func (c *container) dependentsOf(names []string) (map[string]bool, map[*reflectedModule]bool) {
+	resetNames := make(map[string]bool)
+	for _, name := range names {
+		resetNames[name] = true
+	}
+	resetModules := make(map[*reflectedModule]bool)
+
+	for changed := true; changed; {
+		changed = false
+		for _, rm := range c.reflected {
+			if resetModules[rm] {
+				continue
+			}
+			for name := range c.graph.dependsOn[rm] {
+				if resetNames[name] {
+					resetModules[rm] = true
+					for _, instance := range rm.instances {
+						resetNames[instance.name] = true
+					}
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return resetNames, resetModules
+}
+
+// rebuildInstanceByType rebuilds instanceByType from the constructed instances. The caller must hold the lock.
+func (c *container) rebuildInstanceByType() {
+	for t := range c.instanceByType {
+		delete(c.instanceByType, t)
+	}
+	for _, rm := range c.reflected {
+		for _, instance := range rm.instances {
+			if value, ok := c.instanceByName[instance.name]; ok {
+				c.instanceByType[instance.tp] = append(c.instanceByType[instance.tp], value)
+			}
+		}
+	}
+}
+
+// findInstanceMethodInModules returns the instance method with the specified name in the modules, or nil if it is
+// not found.
+func findInstanceMethodInModules(rms []*reflectedModule, name string) *instanceMethod {
+	for _, rm := range rms {
+		if instance := findInstanceMethod(rm.instances, name); instance != nil {
+			return instance
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package alice
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type ResetConsumerModule struct {
+	BaseModule
+	D1 D1 `alice:"D1"`
+}
+
+func (m *ResetConsumerModule) Consumer() *D1Consumer {
+	return &D1Consumer{D1: m.D1}
+}
+
+type D1Consumer struct {
+	D1 D1
+}
+
+type countedD1 struct {
+	n int
+}
+
+func (d *countedD1) D1() {}
+
+type freshModule struct {
+	BaseModule
+	count int
+}
+
+func (m *freshModule) D1() D1 {
+	m.count++
+	return &countedD1{n: m.count}
+}
+
+func (m *freshModule) D2() D2 {
+	m.count++
+	return &D2Impl{}
+}
+
+func testReset(t *testing.T, opts ...Option) {
+	m := &freshModule{}
+	c := CreateContainerWithOptions([]Module{m, &ResetConsumerModule{}}, opts...)
+	if err := c.(Lifecycle).Warm(context.Background()); err != nil {
+		t.Fatalf("unexpected error after Warm(): %s", err.Error())
+	}
+	d1 := c.InstanceByName("D1")
+	consumer := c.InstanceByName("Consumer").(*D1Consumer)
+	d2 := c.InstanceByName("D2")
+
+	c.(Rebuilder).Reset("D1")
+
+	newD1 := c.InstanceByName("D1")
+	if newD1 == d1 {
+		t.Error("D1 is expected to be constructed again after Reset()")
+	}
+	newConsumer := c.InstanceByName("Consumer").(*D1Consumer)
+	if newConsumer == consumer || newConsumer.D1 != newD1 {
+		t.Errorf("bad dependent after Reset(): got %v, expected a new one depending on %v", newConsumer, newD1)
+	}
+	if c.InstanceByName("D2") != d2 {
+		t.Error("D2 is not expected to be constructed again after Reset()")
+	}
+	if m.count != 3 {
+		t.Errorf("bad count of constructions: got %d, expected %d", m.count, 3)
+	}
+	if instance := c.Instance(reflect.TypeOf((*D1)(nil)).Elem()); instance != newD1 {
+		t.Errorf("bad instance from Instance() after Reset(): got %v, expected %v", instance, newD1)
+	}
+}
+
+func TestReset(t *testing.T) {
+	testReset(t)
+}
+
+func TestReset_Lazy(t *testing.T) {
+	testReset(t, WithLazy())
+}
+
+func TestReset_PanicOnNameNotFound(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected panic for Reset() on name not found")
+		} else {
+			t.Log(r)
+		}
+	}()
+	c := CreateContainer(&M1{})
+	c.(Rebuilder).Reset("D3")
+}
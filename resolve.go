@@ -0,0 +1,71 @@
+package alice
+
+import "reflect"
+
+func (c *container) MustInstance(t reflect.Type) interface{} {
+	if c.options.errorFormatter != nil {
+		defer c.formatPanic()
+	}
+	c.mu.Lock()
+	c.retrievedByType[t] = true
+	c.mu.Unlock()
+	return c.findInstanceByType(t)
+}
+
+func (c *container) MustInstanceByName(name string) interface{} {
+	if c.options.errorFormatter != nil {
+		defer c.formatPanic()
+	}
+	c.mu.Lock()
+	c.retrievedByName[name] = true
+	c.mu.Unlock()
+	return c.findInstanceByName(name)
+}
+
+func (c *container) Resolve(t reflect.Type) (interface{}, error) {
+	return resolveSafely(func() interface{} {
+		return c.MustInstance(t)
+	})
+}
+
+func (c *container) ResolveByName(name string) (interface{}, error) {
+	return resolveSafely(func() interface{} {
+		return c.MustInstanceByName(name)
+	})
+}
+
+func (f *fork) MustInstance(t reflect.Type) interface{} {
+	if instance, ok := f.byType[t]; ok {
+		return instance
+	}
+	return f.extendedContainer.MustInstance(t)
+}
+
+func (f *fork) MustInstanceByName(name string) interface{} {
+	if instance, ok := f.byName[name]; ok {
+		return instance
+	}
+	return f.extendedContainer.MustInstanceByName(name)
+}
+
+func (f *fork) Resolve(t reflect.Type) (interface{}, error) {
+	return resolveSafely(func() interface{} {
+		return f.MustInstance(t)
+	})
+}
+
+func (f *fork) ResolveByName(name string) (interface{}, error) {
+	return resolveSafely(func() interface{} {
+		return f.MustInstanceByName(name)
+	})
+}
+
+// resolveSafely calls the retrieval function, converting the panic to an error.
+func resolveSafely(retrieve func() interface{}) (instance interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(r)
+		}
+	}()
+	return retrieve(), nil
+}
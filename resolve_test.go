@@ -0,0 +1,50 @@
+package alice
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	c := CreateContainer(&M1{})
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	if instance, err := c.Resolve(d1Type); err != nil || instance != c.Instance(d1Type) {
+		t.Errorf("bad result from Resolve(): got %v, %v, expected %v", instance, err, c.Instance(d1Type))
+	}
+	if instance, err := c.ResolveByName("D2"); err != nil || instance != c.InstanceByName("D2") {
+		t.Errorf("bad result from ResolveByName(): got %v, %v, expected %v", instance, err, c.InstanceByName("D2"))
+	}
+
+	d3Type := reflect.TypeOf((*D3)(nil)).Elem()
+	_, err := c.Resolve(d3Type)
+	var le *LookupError
+	if !errors.As(err, &le) || le.Type != d3Type || !errors.Is(err, ErrNotFound) {
+		t.Errorf("bad error from Resolve() on type not found: got %v, expected LookupError", err)
+	}
+	_, err = c.ResolveByName("D3")
+	if !errors.As(err, &le) || le.Name != "D3" || !errors.Is(err, ErrNotFound) {
+		t.Errorf("bad error from ResolveByName() on name not found: got %v, expected LookupError", err)
+	}
+
+	anyType := reflect.TypeOf((*interface{})(nil)).Elem()
+	if _, err := c.Resolve(anyType); !errors.Is(err, ErrAmbiguous) {
+		t.Errorf("bad error from Resolve() on multiple matched type: got %v, expected %v", err, ErrAmbiguous)
+	}
+}
+
+func TestMustInstance(t *testing.T) {
+	c := CreateContainer(&M1{})
+	if instance := c.MustInstanceByName("D1"); instance != c.InstanceByName("D1") {
+		t.Errorf("bad result from MustInstanceByName(): got %v, expected %v", instance, c.InstanceByName("D1"))
+	}
+
+	defer func() {
+		err, ok := recover().(error)
+		var le *LookupError
+		if !ok || !errors.As(err, &le) {
+			t.Errorf("bad panic from MustInstance() on type not found: got %v, expected LookupError", err)
+		}
+	}()
+	c.MustInstance(reflect.TypeOf((*D3)(nil)).Elem())
+}
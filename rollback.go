@@ -0,0 +1,56 @@
+package alice
+
+import (
+	"context"
+	"fmt"
+)
+
+// StartError is the error returned by Container.Start when an instance fails to start, or the context is done. The
+// instances started before the failure, and the failed one which could have started partially, are stopped in reverse
+// order before it is returned, so a failed startup doesn't leak listeners or goroutines. Container.Stop doesn't stop
+// the rolled back instances again.
+type StartError struct {
+	// Instance is the name of the instance failed to start, or the next one to start if the context is done.
+	Instance string
+	// Err is the error of the instance, or of the context.
+	Err error
+	// RolledBack contains the names of the instances stopped by the rollback, in the order they were stopped.
+	RolledBack []string
+	// RollbackErr is the error of stopping the started instances, or nil.
+	RollbackErr error
+}
+
+// Error returns the message in the form of "failed to start instance X: cause".
+func (e *StartError) Error() string {
+	if e.RollbackErr != nil {
+		return fmt.Sprintf("failed to start instance %s: %v, and failed to roll back: %v", e.Instance, e.Err,
+			e.RollbackErr)
+	}
+	return fmt.Sprintf("failed to start instance %s: %v", e.Instance, e.Err)
+}
+
+// Unwrap returns the cause.
+func (e *StartError) Unwrap() error {
+	return e.Err
+}
+
+// rollbackStart stops the started instances in reverse order, and records them so Stop skips them. The rollback isn't
+// cancelled by ctx, which could be the cause of the failure.
+func (c *container) rollbackStart(ctx context.Context, e *StartError, started []string) *StartError {
+	c.unsubscribeChanges()
+	var stoppers []namedStopper
+	for i := len(started) - 1; i >= 0; i-- {
+		if stopper, ok := c.constructedInstance(started[i]).(Stopper); ok {
+			stoppers = append(stoppers, namedStopper{name: started[i], stopper: stopper})
+		}
+	}
+	e.RollbackErr = joinErrors(c.stopAll(context.WithoutCancel(ctx), stoppers))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range stoppers {
+		e.RolledBack = append(e.RolledBack, s.name)
+		c.rolledBack[s.name] = true
+	}
+	return e
+}
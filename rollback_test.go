@@ -0,0 +1,81 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStart_Rollback(t *testing.T) {
+	log := &lifecycleLog{}
+	c := CreateContainer(lifecycleModules(log, map[string]string{"Server": "start"})...).(Lifecycle)
+	err := c.Start(context.Background())
+	var startErr *StartError
+	if !errors.As(err, &startErr) {
+		t.Fatalf("bad error after Start(): got %v, expected *StartError", err)
+	}
+	if startErr.Instance != "Server" || startErr.RollbackErr != nil {
+		t.Errorf("bad error after Start(): got %+v, expected Server failed without rollback error", startErr)
+	}
+	if expected := []string{"Server", "DB"}; !reflect.DeepEqual(startErr.RolledBack, expected) {
+		t.Errorf("bad rolled back instances after Start(): got %v, expected %v", startErr.RolledBack, expected)
+	}
+
+	if err := c.Stop(context.Background()); err != nil {
+		t.Errorf("bad error after Stop(): got %v, expected nil", err)
+	}
+	if expected := []string{"start DB", "stop Server", "stop DB"}; !reflect.DeepEqual(log.get(), expected) {
+		t.Errorf("bad events after Start() and Stop(): got %v, expected %v", log.get(), expected)
+	}
+}
+
+func TestStart_RollbackError(t *testing.T) {
+	log := &lifecycleLog{}
+	c := CreateContainer(lifecycleModules(log, map[string]string{"DB": "stop", "Server": "start"})...).(Lifecycle)
+	var startErr *StartError
+	if err := c.Start(context.Background()); !errors.As(err, &startErr) || startErr.RollbackErr == nil {
+		t.Errorf("bad error after Start(): got %v, expected the rollback error", err)
+	}
+}
+
+func TestStart_Cancelled(t *testing.T) {
+	log := &lifecycleLog{}
+	c := CreateContainer(lifecycleModules(log, nil)...).(Lifecycle)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := c.Start(ctx)
+	var startErr *StartError
+	if !errors.Is(err, context.Canceled) || !errors.As(err, &startErr) || len(startErr.RolledBack) != 0 {
+		t.Errorf("bad error after Start() with cancelled context: got %v, expected %v", err, context.Canceled)
+	}
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("bad error after Start() again: got %v, expected nil", err)
+	}
+	c.Stop(context.Background())
+	if expected := []string{"start DB", "start Server", "stop Server", "stop DB"}; !reflect.DeepEqual(log.get(),
+		expected) {
+		t.Errorf("bad events after Start() and Stop(): got %v, expected %v", log.get(), expected)
+	}
+}
+
+func TestStart_RollbackLazyFailure(t *testing.T) {
+	log := &lifecycleLog{}
+	var server Starter
+	broken := NewModule("broken").
+		RequireNamed("Server", &server).
+		Provide("Broken", func() *lifecycleService {
+			panic(errors.New("broken"))
+		}).
+		Build()
+	c := CreateContainerWithOptions(append(lifecycleModules(log, nil), broken), WithLazy()).(Lifecycle)
+	err := c.Start(context.Background())
+	var startErr *StartError
+	if !errors.As(err, &startErr) || startErr.Instance != "Broken" {
+		t.Fatalf("bad error after Start() with failing lazy instance: got %v, expected *StartError of Broken", err)
+	}
+	if expected := []string{"Server", "DB"}; !reflect.DeepEqual(startErr.RolledBack, expected) {
+		t.Errorf("bad rolled back instances after Start(): got %v, expected %v", startErr.RolledBack, expected)
+	}
+}
@@ -0,0 +1,107 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Rule restricts the dependencies between modules, so architectural boundaries are enforced during graph
+// construction. Modules are identified by their names, which are the struct type names or the names of built
+// modules.
+type Rule interface {
+	// Check returns error if module from is not allowed to depend on instances provided by module to.
+	Check(from string, to string) error
+}
+
+// RuleFunc is an adapter to allow the use of ordinary functions as rules.
+type RuleFunc func(from string, to string) error
+
+// Check calls f(from, to).
+func (f RuleFunc) Check(from string, to string) error {
+	return f(from, to)
+}
+
+// WithRules returns an option which checks every dependency between modules against the rules. Creating the
+// container fails with the offending dependencies if any rule is violated.
+func WithRules(rules ...Rule) Option {
+	return func(o *options) {
+		o.rules = append(o.rules, rules...)
+	}
+}
+
+// Forbid returns a rule which forbids module from depending on instances provided by module to.
+func Forbid(from Module, to Module) Rule {
+	fromName, toName := moduleName(from), moduleName(to)
+	return RuleFunc(func(from string, to string) error {
+		if from == fromName && to == toName {
+			return fmt.Errorf("module %s must not depend on module %s", from, to)
+		}
+		return nil
+	})
+}
+
+// Layers returns a rule which declares layers of modules, from the top to the bottom. A module could only depend on
+// modules in the same layer or the layer right below it. Modules not in any layer are not restricted.
+//
+//	alice.WithRules(alice.Layers(
+//		[]alice.Module{&APIModule{}},
+//		[]alice.Module{&ServiceModule{}},
+//		[]alice.Module{&PersistenceModule{}},
+//	))
+func Layers(layers ...[]Module) Rule {
+	layerOf := make(map[string]int)
+	for i, layer := range layers {
+		for _, m := range layer {
+			layerOf[moduleName(m)] = i
+		}
+	}
+	return RuleFunc(func(from string, to string) error {
+		fromLayer, ok1 := layerOf[from]
+		toLayer, ok2 := layerOf[to]
+		if !ok1 || !ok2 || toLayer == fromLayer || toLayer == fromLayer+1 {
+			return nil
+		}
+		return fmt.Errorf("module %s in layer %d must not depend on module %s in layer %d",
+			from, fromLayer, to, toLayer)
+	})
+}
+
+// moduleName returns the name of a module, which is the name of a built module or the struct type name.
+func moduleName(m Module) string {
+	if bm, ok := m.(*builtModule); ok {
+		return bm.name
+	}
+	if fm, ok := m.(*funcModule); ok {
+		return fm.name
+	}
+	if f, ok := m.(ModuleFunc); ok {
+		return funcModuleName(f)
+	}
+	t := reflect.TypeOf(m)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return t.String()
+	}
+	return t.Name()
+}
+
+// checkRules checks the dependency edges of the graph against the rules.
+func (g *graph) checkRules() error {
+	var errs []error
+	for _, dependant := range g.modules {
+		for _, provider := range g.modules {
+			if !g.g[provider][dependant] && !g.lazyEdges[provider][dependant] {
+				continue
+			}
+			for _, rule := range g.options.rules {
+				if err := rule.Check(dependant.name, provider.name); err != nil {
+					errs = append(errs, fmt.Errorf("dependency of module %s on module %s violates rule: %s",
+						dependant.name, provider.name, err.Error()))
+				}
+			}
+		}
+	}
+	return joinErrors(errs)
+}
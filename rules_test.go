@@ -0,0 +1,55 @@
+package alice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestForbid(t *testing.T) {
+	modules := []Module{&M1{}, &M2{}, &M3{}, &M4{}}
+	if err := ValidateWithOptions(modules, WithRules(Forbid(&M1{}, &M2{}))); err != nil {
+		t.Errorf("bad error after ValidateWithOptions(): got %v, expected nil", err)
+	}
+
+	err := ValidateWithOptions(modules, WithRules(Forbid(&M2{}, &M1{})))
+	if err == nil || !strings.Contains(err.Error(), "dependency of module M2 on module M1") {
+		t.Errorf("bad error after ValidateWithOptions(): got %v", err)
+	}
+}
+
+func TestLayers(t *testing.T) {
+	modules := []Module{&M1{}, &M2{}, &M3{}, &M4{}}
+	rule := Layers([]Module{&M3{}}, []Module{&M2{}}, []Module{&M4{}}, []Module{&M1{}})
+	err := ValidateWithOptions(modules, WithRules(rule))
+	if err == nil {
+		t.Fatal("expected error for violated layers")
+	}
+	if strings.Contains(err.Error(), "M4 on module M1") || !strings.Contains(err.Error(), "M2 on module M1") {
+		t.Errorf("bad error after ValidateWithOptions(): got %v", err)
+	}
+
+	rule = Layers([]Module{&M3{}}, []Module{&M2{}}, []Module{&M4{}, &M1{}})
+	if err := ValidateWithOptions(modules, WithRules(rule)); err != nil {
+		t.Errorf("bad error after ValidateWithOptions(): got %v, expected nil", err)
+	}
+}
+
+func TestRuleFunc(t *testing.T) {
+	built := NewModule("built").Require(new(D1)).Build()
+	rule := RuleFunc(func(from string, to string) error {
+		if from == "built" {
+			return errors.New("built modules must not have dependencies")
+		}
+		return nil
+	})
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Error("expected panic for violated rule")
+		}
+		t.Log(r)
+	}()
+	CreateContainerWithOptions([]Module{&M1{}, built}, WithRules(rule))
+}
@@ -0,0 +1,98 @@
+package alice
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Sandbox is the environment applied while the instance methods of a module are called, and restored afterward.
+// The environment and working directory are global to the process, so sandboxed instance methods are serialized,
+// even if they are constructed in parallel by background instances or Container.Warm. Instance methods without a
+// sandbox are not serialized, and could observe a sandbox applied by another goroutine meanwhile.
+type Sandbox struct {
+	// Env are the environment variables to set, keyed by names.
+	Env map[string]string
+	// Unset are the names of the environment variables to unset.
+	Unset []string
+	// Dir is the working directory, or empty to keep the current one.
+	Dir string
+}
+
+// sandboxes serializes the sandboxes. It is reentrant, so a sandboxed instance method could construct another
+// sandboxed instance on the same goroutine, e.g. in lazy mode.
+var sandboxes = newSandboxLock()
+
+type sandboxLock struct {
+	mu    sync.Mutex
+	free  *sync.Cond
+	owner uint64
+	depth int
+}
+
+func newSandboxLock() *sandboxLock {
+	l := &sandboxLock{}
+	l.free = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *sandboxLock) lock() {
+	gid := goroutineID()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.depth > 0 && l.owner != gid {
+		l.free.Wait()
+	}
+	l.owner = gid
+	l.depth++
+}
+
+func (l *sandboxLock) unlock() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.depth--
+	if l.depth == 0 {
+		l.owner = 0
+		l.free.Broadcast()
+	}
+}
+
+// enter applies the sandbox and returns the function restoring the previous environment. It panics if the working
+// directory could not be changed.
+func (s *Sandbox) enter() (restore func()) {
+	sandboxes.lock()
+	var restores []func()
+	restore = func() {
+		for i := len(restores) - 1; i >= 0; i-- {
+			restores[i]()
+		}
+		sandboxes.unlock()
+	}
+	save := func(name string) {
+		if previous, ok := os.LookupEnv(name); ok {
+			restores = append(restores, func() { os.Setenv(name, previous) })
+		} else {
+			restores = append(restores, func() { os.Unsetenv(name) })
+		}
+	}
+	for name, value := range s.Env {
+		save(name)
+		os.Setenv(name, value)
+	}
+	for _, name := range s.Unset {
+		save(name)
+		os.Unsetenv(name)
+	}
+	if s.Dir != "" {
+		previous, err := os.Getwd()
+		if err == nil {
+			err = os.Chdir(s.Dir)
+		}
+		if err != nil {
+			restore()
+			panic(fmt.Errorf("failed to enter the sandbox directory %s: %w", s.Dir, err))
+		}
+		restores = append(restores, func() { os.Chdir(previous) })
+	}
+	return restore
+}
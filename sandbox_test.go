@@ -0,0 +1,107 @@
+package alice
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type SandboxedModule1 struct {
+	BaseModule
+	dir string
+}
+
+func (m *SandboxedModule1) Legacy() map[string]string {
+	wd, _ := os.Getwd()
+	return map[string]string{"env": os.Getenv("ALICE_SANDBOX"), "unset": os.Getenv("ALICE_UNSET"), "wd": wd}
+}
+
+func (m *SandboxedModule1) Sandbox() Sandbox {
+	return Sandbox{
+		Env:   map[string]string{"ALICE_SANDBOX": "sandboxed"},
+		Unset: []string{"ALICE_UNSET"},
+		Dir:   m.dir,
+	}
+}
+
+func TestSandbox(t *testing.T) {
+	dir, _ := filepath.EvalSymlinks(t.TempDir())
+	t.Setenv("ALICE_UNSET", "ambient")
+	wd, _ := os.Getwd()
+
+	c := CreateContainer(&SandboxedModule1{dir: dir})
+	legacy := c.InstanceByName("Legacy").(map[string]string)
+	if legacy["env"] != "sandboxed" || legacy["unset"] != "" || legacy["wd"] != dir {
+		t.Errorf("bad environment in the sandbox: got %v", legacy)
+	}
+	if _, ok := os.LookupEnv("ALICE_SANDBOX"); ok {
+		t.Error("bad environment after CreateContainer(): ALICE_SANDBOX is still set")
+	}
+	if v := os.Getenv("ALICE_UNSET"); v != "ambient" {
+		t.Errorf("bad environment after CreateContainer(): got ALICE_UNSET %q, expected %q", v, "ambient")
+	}
+	if current, _ := os.Getwd(); current != wd {
+		t.Errorf("bad working directory after CreateContainer(): got %s, expected %s", current, wd)
+	}
+}
+
+func TestSandbox_Serialized(t *testing.T) {
+	m := NewModule("built").
+		Sandbox(Sandbox{Env: map[string]string{"ALICE_SANDBOX": "a"}}).
+		Provide("A", func() string { return os.Getenv("ALICE_SANDBOX") }).
+		Build()
+	other := NewModule("other").
+		Sandbox(Sandbox{Env: map[string]string{"ALICE_SANDBOX": "b"}}).
+		Provide("B", func() []string { return []string{os.Getenv("ALICE_SANDBOX")} }).
+		Build()
+	c := CreateContainerWithOptions([]Module{m, other}, WithLazy(), WithWarmParallelism(2))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.(Lifecycle).Warm(context.Background())
+		}()
+	}
+	wg.Wait()
+	if a, b := c.InstanceByName("A").(string), c.InstanceByName("B").([]string)[0]; a != "a" || b != "b" {
+		t.Errorf("bad environment in the sandboxes: got %q and %q, expected %q and %q", a, b, "a", "b")
+	}
+}
+
+func TestSandbox_IsolatedDependency(t *testing.T) {
+	m := NewModule("a").
+		Sandbox(Sandbox{Env: map[string]string{"ALICE_SANDBOX": "a"}}).
+		Provide("Y", func(x *D5Impl) string { return os.Getenv("ALICE_SANDBOX") }).
+		Build()
+	other := NewModule("b").
+		Sandbox(Sandbox{Env: map[string]string{"ALICE_SANDBOX": "b"}}).
+		Provide("X", func() *D5Impl { return &D5Impl{} }).
+		Isolate("X", Isolation{}).
+		Build()
+	c := CreateContainerWithOptions([]Module{m, other}, WithLazy())
+	done := make(chan interface{})
+	go func() {
+		done <- c.InstanceByName("Y")
+	}()
+	select {
+	case y := <-done:
+		if y != "a" {
+			t.Errorf("bad environment in the sandbox: got %q, expected %q", y, "a")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("InstanceByName() is blocked by the sandbox of the isolated dependency")
+	}
+}
+
+func TestSandbox_InvalidDir(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for missing sandbox directory")
+		}
+	}()
+	CreateContainer(&SandboxedModule1{dir: filepath.Join(t.TempDir(), "missing")})
+}
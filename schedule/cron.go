@@ -0,0 +1,154 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when a job runs.
+type Schedule interface {
+	// Next returns the next time the job runs after the specified time, or the zero time if it never runs again.
+	Next(after time.Time) time.Time
+}
+
+// Every returns a schedule running at a fixed interval.
+func Every(interval time.Duration) Schedule {
+	return every(interval)
+}
+
+type every time.Duration
+
+func (e every) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(e))
+}
+
+// cronField is the set of values a field of a cron expression matches.
+type cronField struct {
+	values   map[int]bool
+	wildcard bool
+}
+
+var _CronFieldRanges = [5][2]int{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week
+}
+
+// cron is a schedule parsed from a cron expression.
+type cron struct {
+	minute, hour, dom, month, dow cronField
+	location                      *time.Location
+}
+
+// Cron parses a cron expression with five fields: minute, hour, day of month, month and day of week. A field is "*",
+// a value, a range like "1-5", a list like "1,15", or any of them with a step like "*/10". Like cron, a day matches
+// either field if both day of month and day of week are restricted. The times are in the local time zone.
+func Cron(expr string) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(_CronFieldRanges) {
+		return nil, fmt.Errorf("cron expression %q should have %d fields", expr, len(_CronFieldRanges))
+	}
+	var parsed [5]cronField
+	for i, field := range fields {
+		f, err := parseCronField(field, _CronFieldRanges[i][0], _CronFieldRanges[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("bad cron expression %q: %w", expr, err)
+		}
+		parsed[i] = f
+	}
+	return &cron{
+		minute:   parsed[0],
+		hour:     parsed[1],
+		dom:      parsed[2],
+		month:    parsed[3],
+		dow:      parsed[4],
+		location: time.Local,
+	}, nil
+}
+
+// MustCron parses a cron expression like Cron. It panics if the expression is invalid, so it could be used in
+// Schedule methods of jobs.
+func MustCron(expr string) Schedule {
+	s, err := Cron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func parseCronField(field string, min, max int) (cronField, error) {
+	f := cronField{values: make(map[int]bool)}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return f, fmt.Errorf("bad step in %q", part)
+			}
+			step = n
+			part = part[:i]
+		}
+		lo, hi := min, max
+		switch {
+		case part == "*":
+			f.wildcard = f.wildcard || step == 1
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return f, fmt.Errorf("bad range %q", part)
+			}
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return f, fmt.Errorf("bad value %q", part)
+			}
+			lo, hi = n, n
+		}
+		if lo < min || hi > max || lo > hi {
+			return f, fmt.Errorf("%q is out of range [%d, %d]", part, min, max)
+		}
+		for n := lo; n <= hi; n += step {
+			f.values[n] = true
+		}
+	}
+	return f, nil
+}
+
+// _CronSearchYears bounds the search of the next time, for expressions like "0 0 31 2 *" never matching.
+const _CronSearchYears = 5
+
+func (c *cron) Next(after time.Time) time.Time {
+	t := after.In(c.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + _CronSearchYears
+	for t.Year() <= limit {
+		switch {
+		case !c.month.values[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.location)
+		case !c.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.location)
+		case !c.hour.values[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.location)
+		case !c.minute.values[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cron) matchDay(t time.Time) bool {
+	dom := c.dom.values[t.Day()]
+	dow := c.dow.values[int(t.Weekday())]
+	if c.dom.wildcard || c.dow.wildcard {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,53 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCron_Next(t *testing.T) {
+	after := time.Date(2024, time.January, 31, 10, 30, 15, 0, time.Local) // Wednesday
+	cases := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 31, 10, 31, 0, 0, time.Local)},
+		{"*/15 * * * *", time.Date(2024, time.January, 31, 10, 45, 0, 0, time.Local)},
+		{"0 6 * * *", time.Date(2024, time.February, 1, 6, 0, 0, 0, time.Local)},
+		{"0 9-17 * * 1-5", time.Date(2024, time.January, 31, 11, 0, 0, 0, time.Local)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.Local)},
+		{"0 0 1,15 * 0", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.Local)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+	for _, c := range cases {
+		next := MustCron(c.expr).Next(after)
+		if !next.Equal(c.expected) {
+			t.Errorf("bad result from Next() of %q: got %v, expected %v", c.expr, next, c.expected)
+		}
+	}
+}
+
+func TestCron_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"* * * *":     `cron expression "* * * *" should have 5 fields`,
+		"60 * * * *":  `bad cron expression "60 * * * *": "60" is out of range [0, 59]`,
+		"* * * * 1-x": `bad cron expression "* * * * 1-x": bad range "1-x"`,
+		"*/0 * * * *": `bad cron expression "*/0 * * * *": bad step in "*/0"`,
+		"a * * * *":   `bad cron expression "a * * * *": bad value "a"`,
+		"* 5-1 * * *": `bad cron expression "* 5-1 * * *": "5-1" is out of range [0, 23]`,
+	}
+	for expr, expected := range cases {
+		_, err := Cron(expr)
+		if err == nil || err.Error() != expected {
+			t.Errorf("bad error from Cron(%q): got %v, expected %s", expr, err, expected)
+		}
+	}
+}
+
+func TestEvery_Next(t *testing.T) {
+	after := time.Date(2024, time.January, 31, 10, 30, 15, 0, time.UTC)
+	next := Every(time.Minute).Next(after)
+	if expected := after.Add(time.Minute); !next.Equal(expected) {
+		t.Errorf("bad result from Next(): got %v, expected %v", next, expected)
+	}
+}
@@ -0,0 +1,161 @@
+// Package schedule runs scheduled jobs wired by alice containers. Feature modules define job instances, and the
+// scheduler module schedules them when the container starts and cancels them when it stops.
+//
+//	func (m *ReportModule) DailyReport() schedule.Job {
+//		return &dailyReport{schedule: schedule.MustCron("0 6 * * *"), store: m.Store}
+//	}
+//
+//	c := alice.CreateContainer(schedule.NewModule(), &ReportModule{})
+//	err := c.(alice.Lifecycle).Start(ctx)
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/magic003/alice"
+)
+
+// Job is a task run on a schedule. Instances of other modules implementing Job are collected by the scheduler
+// module.
+type Job interface {
+	// Schedule returns when the job runs. It is called once when the scheduler starts.
+	Schedule() Schedule
+	// Run runs the job. The context is cancelled when the scheduler stops.
+	Run(ctx context.Context) error
+}
+
+// Observer observes the runs of jobs, e.g. to record metrics. It must be safe for concurrent use.
+type Observer interface {
+	// ObserveJob is called after a job runs. The error is the one returned by the job, or the recovered panic.
+	ObserveJob(name string, elapsed time.Duration, err error)
+}
+
+// Module provides the "Scheduler" instance running the jobs defined in other modules.
+type Module struct {
+	alice.BaseModule
+	Jobs map[string]Job `alice:",map"`
+}
+
+// NewModule creates the scheduler module.
+func NewModule() *Module {
+	return &Module{}
+}
+
+// SchedulerParams are the optional dependencies of the scheduler. The failed runs are logged by the logger, which
+// is slog.Default() if not provided.
+type SchedulerParams struct {
+	alice.Params
+	Logger   *slog.Logger `alice:",optional"`
+	Observer Observer     `alice:",optional"`
+}
+
+// Scheduler returns the scheduler of the jobs.
+func (m *Module) Scheduler(p SchedulerParams) *Scheduler {
+	logger := p.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Scheduler{
+		jobs:     m.Jobs,
+		logger:   logger,
+		observer: p.Observer,
+		now:      time.Now,
+	}
+}
+
+// Scheduler schedules the jobs on Start and cancels them on Stop. It implements alice.Starter and alice.Stopper. The
+// runs of a job never overlap: a run due while the previous one is running is skipped.
+type Scheduler struct {
+	jobs     map[string]Job
+	logger   *slog.Logger
+	observer Observer
+	now      func() time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Start schedules all jobs, in the order of the job names.
+func (s *Scheduler) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	var names []string
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		job := s.jobs[name]
+		schedule := job.Schedule()
+		if schedule == nil {
+			cancel()
+			return fmt.Errorf("job %s has no schedule", name)
+		}
+		s.wg.Add(1)
+		go s.loop(runCtx, name, job, schedule)
+	}
+	return nil
+}
+
+// Stop cancels the jobs and waits until the running ones return, or ctx is done.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// loop runs the job at the times of the schedule until ctx is cancelled or the schedule ends.
+func (s *Scheduler) loop(ctx context.Context, name string, job Job, schedule Schedule) {
+	defer s.wg.Done()
+	next := schedule.Next(s.now())
+	for !next.IsZero() {
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.run(ctx, name, job)
+		next = schedule.Next(s.now())
+	}
+}
+
+// run runs the job once. A panic is recovered and reported like an error.
+func (s *Scheduler) run(ctx context.Context, name string, job Job) {
+	start := s.now()
+	err := func() (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("job %s panicked: %v", name, p)
+			}
+		}()
+		return job.Run(ctx)
+	}()
+	if s.observer != nil {
+		s.observer.ObserveJob(name, s.now().Sub(start), err)
+	}
+	if err != nil && ctx.Err() == nil {
+		s.logger.Error("schedule: job failed", "job", name, "error", err)
+	}
+}
@@ -0,0 +1,150 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/magic003/alice"
+)
+
+type countingJob struct {
+	mu    sync.Mutex
+	runs  int
+	fail  error
+	panic bool
+}
+
+func (j *countingJob) Schedule() Schedule {
+	return Every(time.Millisecond)
+}
+
+func (j *countingJob) Run(ctx context.Context) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.runs++
+	if j.panic {
+		panic("bad job")
+	}
+	return j.fail
+}
+
+func (j *countingJob) count() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.runs
+}
+
+type recordingObserver struct {
+	mu   sync.Mutex
+	errs map[string]error
+}
+
+func (o *recordingObserver) ObserveJob(name string, elapsed time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.errs[name] = err
+}
+
+func (o *recordingObserver) err(name string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.errs[name]
+}
+
+type jobsModule struct {
+	alice.BaseModule
+	ok, failing, panicking *countingJob
+	observer               *recordingObserver
+}
+
+func (m *jobsModule) CleanupJob() Job {
+	return m.ok
+}
+
+func (m *jobsModule) FailingJob() Job {
+	return m.failing
+}
+
+func (m *jobsModule) PanickingJob() Job {
+	return m.panicking
+}
+
+func (m *jobsModule) JobObserver() Observer {
+	return m.observer
+}
+
+func (m *jobsModule) Logger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestScheduler(t *testing.T) {
+	m := &jobsModule{
+		ok:        &countingJob{},
+		failing:   &countingJob{fail: errors.New("bad job")},
+		panicking: &countingJob{panic: true},
+		observer:  &recordingObserver{errs: make(map[string]error)},
+	}
+	c := alice.CreateContainer(NewModule(), m)
+	if err := c.(alice.Lifecycle).Start(context.Background()); err != nil {
+		t.Fatalf("bad result from Start(): got %v, expected nil", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for (m.ok.count() < 2 || m.panicking.count() < 2 || m.failing.count() < 1) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := c.(alice.Lifecycle).Stop(context.Background()); err != nil {
+		t.Errorf("bad result from Stop(): got %v, expected nil", err)
+	}
+
+	if m.ok.count() < 2 || m.panicking.count() < 2 {
+		t.Errorf("bad runs after Start(): got %d and %d, expected at least 2", m.ok.count(), m.panicking.count())
+	}
+	if err := m.observer.err("FailingJob"); err == nil || err.Error() != "bad job" {
+		t.Errorf("bad error observed for FailingJob: got %v, expected bad job", err)
+	}
+	expected := "job PanickingJob panicked: bad job"
+	if err := m.observer.err("PanickingJob"); err == nil || err.Error() != expected {
+		t.Errorf("bad error observed for PanickingJob: got %v, expected %s", err, expected)
+	}
+
+	runs := m.ok.count()
+	time.Sleep(10 * time.Millisecond)
+	if m.ok.count() != runs {
+		t.Errorf("bad runs after Stop(): got %d, expected %d", m.ok.count(), runs)
+	}
+}
+
+type blockingJob struct {
+	started chan struct{}
+	once    sync.Once
+}
+
+func (j *blockingJob) Schedule() Schedule {
+	return Every(time.Millisecond)
+}
+
+func (j *blockingJob) Run(ctx context.Context) error {
+	j.once.Do(func() { close(j.started) })
+	time.Sleep(time.Second)
+	return nil
+}
+
+func TestScheduler_StopTimeout(t *testing.T) {
+	job := &blockingJob{started: make(chan struct{})}
+	s := &Scheduler{jobs: map[string]Job{"SlowJob": job}, logger: slog.Default(), now: time.Now}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("bad result from Start(): got %v, expected nil", err)
+	}
+	<-job.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("bad result from Stop(): got %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,164 @@
+package alice
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// Scope is a child of a container, usually created per request. It resolves instances like a fork, and must be
+// closed when the request finishes. Scopes which are never closed are reported by the logger when they are garbage
+// collected, and by Container.Stop.
+type Scope interface {
+	Container
+	// Close closes the scope. It returns error if the scope is closed already.
+	Close() error
+}
+
+// ScopeStats contains the usage statistics of the scopes created from a container.
+type ScopeStats struct {
+	// Created is the number of scopes created.
+	Created int
+	// Closed is the number of scopes closed.
+	Closed int
+	// Open is the number of scopes not closed yet.
+	Open int
+}
+
+// ErrScopeClosed is returned when a scope is closed more than once.
+var ErrScopeClosed = errors.New("scope is closed already")
+
+// WithScopeTracing returns an option which records the stack traces of scope creation, so unclosed scopes could be
+// traced to where they are created. It is intended for debugging, as capturing stacks is expensive.
+func WithScopeTracing() Option {
+	return func(o *options) {
+		o.scopeTracing = true
+	}
+}
+
+// scopeTracker tracks the open scopes of a container. The zero value is ready to use.
+type scopeTracker struct {
+	mu      sync.Mutex
+	nextID  int
+	created int
+	closed  int
+	// open contains the creation stacks of the open scopes keyed by ids. A stack is empty without tracing.
+	open map[int]string
+}
+
+// scope is an implementation of Scope.
+type scope struct {
+	*fork
+	id      int
+	tracker *scopeTracker
+	o       *options
+}
+
+func (c *container) NewScope(overrides ...Override) Scope {
+	return newScope(newFork(c, overrides), &c.scopes, &c.options)
+}
+
+func (c *container) ScopeStats() ScopeStats {
+	return c.scopes.stats()
+}
+
+func (f *fork) NewScope(overrides ...Override) Scope {
+	return f.extendedContainer.NewScope(flattenOverrides(f, overrides)...)
+}
+
+// flattenOverrides returns the overrides of a fork followed by the specified ones, which take precedence.
+func flattenOverrides(f *fork, overrides []Override) []Override {
+	var flattened []Override
+	for name, instance := range f.byName {
+		flattened = append(flattened, OverrideName(name, instance))
+	}
+	for t, instance := range f.byType {
+		flattened = append(flattened, OverrideType(t, instance))
+	}
+	return append(flattened, overrides...)
+}
+
+// newScope creates a scope of the fork, registered in the tracker.
+func newScope(f *fork, tracker *scopeTracker, o *options) *scope {
+	var stack string
+	if o.scopeTracing {
+		stack = string(debug.Stack())
+	}
+	s := &scope{
+		fork:    f,
+		id:      tracker.add(stack),
+		tracker: tracker,
+		o:       o,
+	}
+	runtime.SetFinalizer(s, func(s *scope) {
+		if stack, open := tracker.remove(s.id); open {
+			s.o.logger.Warn("alice: scope is garbage collected without being closed", "stack", stack)
+		}
+	})
+	return s
+}
+
+func (s *scope) Close() error {
+	if _, open := s.tracker.remove(s.id); !open {
+		return ErrScopeClosed
+	}
+	s.tracker.mu.Lock()
+	s.tracker.closed++
+	s.tracker.mu.Unlock()
+	runtime.SetFinalizer(s, nil)
+	return nil
+}
+
+// add registers an open scope and returns its id.
+func (t *scopeTracker) add(stack string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.open == nil {
+		t.open = make(map[int]string)
+	}
+	t.nextID++
+	t.created++
+	t.open[t.nextID] = stack
+	return t.nextID
+}
+
+// remove unregisters a scope. It returns the creation stack and whether the scope was open.
+func (t *scopeTracker) remove(id int) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stack, open := t.open[id]
+	delete(t.open, id)
+	return stack, open
+}
+
+func (t *scopeTracker) stats() ScopeStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return ScopeStats{
+		Created: t.created,
+		Closed:  t.closed,
+		Open:    len(t.open),
+	}
+}
+
+// leakError returns error if any scope is open, including the creation stacks if traced.
+func (t *scopeTracker) leakError() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.open) == 0 {
+		return nil
+	}
+	var stacks []string
+	for _, stack := range t.open {
+		if stack != "" {
+			stacks = append(stacks, stack)
+		}
+	}
+	if len(stacks) == 0 {
+		return fmt.Errorf("%d scopes are not closed", len(t.open))
+	}
+	return fmt.Errorf("%d scopes are not closed, created at:\n%s", len(t.open), strings.Join(stacks, "\n"))
+}
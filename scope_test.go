@@ -0,0 +1,86 @@
+package alice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScope(t *testing.T) {
+	c := CreateContainerWithOptions([]Module{&M1{}}, WithScopeTracing())
+	alternate := &countedD1{n: 1}
+	s := c.(Scoper).NewScope(OverrideName("D1", alternate))
+	if instance := s.InstanceByName("D1"); instance != alternate {
+		t.Errorf("bad instance from scope: got %v, expected %v", instance, alternate)
+	}
+	if stats := c.(Scoper).ScopeStats(); stats != (ScopeStats{Created: 1, Open: 1}) {
+		t.Errorf("bad result from ScopeStats(): got %+v, expected %+v", stats, ScopeStats{Created: 1, Open: 1})
+	}
+
+	err := c.(Lifecycle).Stop(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1 scopes are not closed") ||
+		!strings.Contains(err.Error(), "TestScope") {
+		t.Errorf("bad error from Stop() with open scope: got %v, expected creation stack", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("bad error from Close(): got %v, expected nil", err)
+	}
+	if err := s.Close(); !errors.Is(err, ErrScopeClosed) {
+		t.Errorf("bad error from second Close(): got %v, expected %v", err, ErrScopeClosed)
+	}
+	if stats := c.(Scoper).ScopeStats(); stats != (ScopeStats{Created: 1, Closed: 1}) {
+		t.Errorf("bad result from ScopeStats() after Close(): got %+v, expected %+v", stats,
+			ScopeStats{Created: 1, Closed: 1})
+	}
+	if err := c.(Lifecycle).Stop(context.Background()); err != nil {
+		t.Errorf("bad error from Stop() after Close(): got %v, expected nil", err)
+	}
+
+	nested := c.(Scoper).Fork(OverrideName("D1", alternate)).(Scoper).NewScope()
+	defer nested.Close()
+	if instance := nested.InstanceByName("D1"); instance != alternate {
+		t.Errorf("bad instance from scope of fork: got %v, expected %v", instance, alternate)
+	}
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestScope_Leak(t *testing.T) {
+	var buf syncBuffer
+	c := CreateContainerWithOptions([]Module{&M1{}}, WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+	c.(Scoper).NewScope()
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "scope is garbage collected without being closed") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected warning for leaked scope, got %q", buf.String())
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if stats := c.(Scoper).ScopeStats(); stats.Open != 0 {
+		t.Errorf("bad open scopes after garbage collection: got %d, expected %d", stats.Open, 0)
+	}
+}
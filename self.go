@@ -0,0 +1,69 @@
+package alice
+
+import "reflect"
+
+// ModuleInfo describes a module. A module could depend on its own ModuleInfo by type, like any other dependency:
+//
+//	type RegistryModule struct {
+//		alice.BaseModule
+//		Info      alice.ModuleInfo `alice:""`
+//		Container alice.Container  `alice:""`
+//	}
+type ModuleInfo struct {
+	// Name is the module name.
+	Name string
+	// Instances are the names of the instances provided by the module.
+	Instances []string
+	// Description is the human-readable description of the module, if any.
+	Description string
+}
+
+// _ContainerType and _ModuleInfoType are the types of dependencies satisfied by the container itself, instead of any
+// module. A dependency on Container is set to the container creating the module, for registry-style instances which
+// enumerate or resolve instances dynamically. As the container is injected before all instances are constructed, it
+// should only be used to retrieve instances after the container is created, unless in lazy mode.
+var (
+	_ContainerType  = reflect.TypeOf((*Container)(nil)).Elem()
+	_ModuleInfoType = reflect.TypeOf(ModuleInfo{})
+)
+
+// isContainerProvided checks if a dependency type is satisfied by the container itself.
+func isContainerProvided(t reflect.Type) bool {
+	return t == _ContainerType || t == _ModuleInfoType
+}
+
+// findDependencyByType finds the instance of a dependency associated by type. consumer returns the module depending
+// on it, and is only called for a dependency on ModuleInfo or of a contextual type.
+func (c *container) findDependencyByType(t reflect.Type, consumer func() *reflectedModule) interface{} {
+	switch t {
+	case _ContainerType:
+		return c
+	case _ModuleInfoType:
+		return newModuleInfo(consumer())
+	}
+	if a, ok := c.graph.adapted[t]; ok {
+		return c.contextualize(t, c.adaptedInstance(t, a), consumer)
+	}
+	return c.contextualize(t, c.findInstanceByType(t), consumer)
+}
+
+// moduleOf returns the module providing an instance method.
+func (c *container) moduleOf(im *instanceMethod) *reflectedModule {
+	for _, rm := range c.reflected {
+		for _, instance := range rm.instances {
+			if instance == im {
+				return rm
+			}
+		}
+	}
+	return nil
+}
+
+// newModuleInfo creates the ModuleInfo of a module.
+func newModuleInfo(rm *reflectedModule) ModuleInfo {
+	info := ModuleInfo{Name: rm.name, Description: rm.description}
+	for _, instance := range rm.instances {
+		info.Instances = append(info.Instances, instance.name)
+	}
+	return info
+}
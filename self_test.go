@@ -0,0 +1,39 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+type RegistryModule struct {
+	BaseModule
+	Info      ModuleInfo `alice:""`
+	Container Container  `alice:""`
+}
+
+func (m *RegistryModule) Registry(c Container) func(name string) interface{} {
+	return c.InstanceByName
+}
+
+func (m *RegistryModule) Names(info ModuleInfo) []string {
+	return info.Instances
+}
+
+func TestSelfInjection(t *testing.T) {
+	m := &RegistryModule{}
+	c := CreateContainerWithOptions([]Module{m, &M1{}}, WithStrict())
+	if m.Container != c {
+		t.Errorf("bad injected Container: got %v, expected %v", m.Container, c)
+	}
+	expected := ModuleInfo{Name: "RegistryModule", Instances: []string{"Names", "Registry"}}
+	if !reflect.DeepEqual(m.Info, expected) {
+		t.Errorf("bad injected ModuleInfo: got %v, expected %v", m.Info, expected)
+	}
+	if names := c.InstanceByName("Names").([]string); !reflect.DeepEqual(names, expected.Instances) {
+		t.Errorf("bad ModuleInfo parameter: got %v, expected %v", names, expected.Instances)
+	}
+	registry := c.InstanceByName("Registry").(func(name string) interface{})
+	if _, ok := registry("D1").(D1); !ok {
+		t.Errorf("bad instance from injected Container: got %v, expected D1", registry("D1"))
+	}
+}
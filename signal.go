@@ -0,0 +1,114 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ErrReloadVetoed is the cause of the error reported when a reload on signal is vetoed.
+var ErrReloadVetoed = errors.New("reload is vetoed")
+
+// ReloadVeto is called before a reload on signal. It returns error to skip the reload, e.g. while critical work, like
+// a migration or a batch job, is in flight.
+type ReloadVeto func(ctx context.Context) error
+
+// ReloadOption customizes ReloadOnSignal.
+type ReloadOption func(*reloadOptions)
+
+type reloadOptions struct {
+	signals  []os.Signal
+	vetoes   []ReloadVeto
+	callback func(err error)
+}
+
+// ReloadSignals returns an option which sets the signals triggering reloads. The default is SIGHUP.
+func ReloadSignals(signals ...os.Signal) ReloadOption {
+	return func(o *reloadOptions) {
+		o.signals = signals
+	}
+}
+
+// ReloadVetoes returns an option which calls the vetoes, in order, before each reload.
+func ReloadVetoes(vetoes ...ReloadVeto) ReloadOption {
+	return func(o *reloadOptions) {
+		o.vetoes = append(o.vetoes, vetoes...)
+	}
+}
+
+// ReloadCallback returns an option which calls the callback after each reload triggered by a signal, with the error of
+// the reload or nil, e.g. to report it as a metric.
+func ReloadCallback(callback func(err error)) ReloadOption {
+	return func(o *reloadOptions) {
+		o.callback = callback
+	}
+}
+
+// ReloadOnSignal reloads the container of the handle by Reload when the process receives SIGHUP, until ctx is done or
+// the returned function is called. modules is called for each reload, so the modules are created from the current
+// factories and refreshed configuration:
+//
+//	stop := alice.ReloadOnSignal(ctx, h, func() ([]alice.Module, error) {
+//		config, err := LoadConfig(path)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return []alice.Module{alice.Values("config", config), &ServerModule{}}, nil
+//	})
+//	defer stop()
+//
+// Signals received during a reload are coalesced. Failed and vetoed reloads keep the current container, and are
+// logged by the logger of the handle options.
+func ReloadOnSignal(ctx context.Context, h *Handle, modules func() ([]Module, error),
+	opts ...ReloadOption) (stop func()) {
+	o := reloadOptions{signals: []os.Signal{syscall.SIGHUP}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	logger := newOptions(h.opts...).logger
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, o.signals...)
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+			}
+			err := reloadOnSignal(ctx, h, modules, o.vetoes)
+			if err != nil {
+				logger.Error("alice: failed to reload container on signal", "error", err)
+			}
+			if o.callback != nil {
+				o.callback(err)
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		cancel()
+		<-done
+	}
+}
+
+// reloadOnSignal calls the vetoes, and reloads the container if none of them vetoes.
+func reloadOnSignal(ctx context.Context, h *Handle, modules func() ([]Module, error), vetoes []ReloadVeto) error {
+	for _, veto := range vetoes {
+		if err := veto(ctx); err != nil {
+			return fmt.Errorf("%w: %w", ErrReloadVetoed, err)
+		}
+	}
+	ms, err := modules()
+	if err != nil {
+		return fmt.Errorf("failed to create modules: %w", err)
+	}
+	return Reload(ctx, h, ms...)
+}
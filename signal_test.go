@@ -0,0 +1,84 @@
+//go:build unix
+
+package alice
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// signalReload sends the signal to the process and waits for the reload it triggers.
+func signalReload(t *testing.T, results chan error) error {
+	t.Helper()
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("failed to send signal: %v", err)
+	}
+	select {
+	case err := <-results:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the reload")
+		return nil
+	}
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	old := CreateContainer(&M1{})
+	h := NewHandle(old)
+	results := make(chan error, 1)
+	var inFlight atomic.Bool
+	veto := func(ctx context.Context) error {
+		if inFlight.Load() {
+			return errors.New("batch job in flight")
+		}
+		return nil
+	}
+	stop := ReloadOnSignal(context.Background(), h, func() ([]Module, error) {
+		return []Module{&M1{}, &M4{}}, nil
+	}, ReloadSignals(syscall.SIGUSR1), ReloadVetoes(veto), ReloadCallback(func(err error) {
+		results <- err
+	}))
+	defer stop()
+
+	inFlight.Store(true)
+	if err := signalReload(t, results); !errors.Is(err, ErrReloadVetoed) {
+		t.Errorf("bad error after vetoed reload: got %v, expected %v", err, ErrReloadVetoed)
+	}
+	if h.Container() != old {
+		t.Error("bad container after vetoed reload: expected the old one")
+	}
+
+	inFlight.Store(false)
+	if err := signalReload(t, results); err != nil {
+		t.Errorf("bad error after reload: got %v, expected nil", err)
+	}
+	if h.Container() == old {
+		t.Error("bad container after reload: got the old one")
+	}
+	if _, err := h.Container().ResolveByName("D3"); err != nil {
+		t.Errorf("bad error after ResolveByName() on the reloaded container: got %v, expected nil", err)
+	}
+}
+
+func TestReloadOnSignal_ModulesFailure(t *testing.T) {
+	old := CreateContainer(&M1{})
+	h := NewHandle(old)
+	results := make(chan error, 1)
+	stop := ReloadOnSignal(context.Background(), h, func() ([]Module, error) {
+		return nil, errors.New("invalid config")
+	}, ReloadSignals(syscall.SIGUSR1), ReloadCallback(func(err error) {
+		results <- err
+	}))
+	defer stop()
+
+	if err := signalReload(t, results); err == nil {
+		t.Error("expected error after reload with failing modules")
+	}
+	if h.Container() != old {
+		t.Error("bad container after failed reload: expected the old one")
+	}
+}
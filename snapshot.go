@@ -0,0 +1,101 @@
+package alice
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// WithSeed returns an option which seeds value instances from a snapshot created by Container.Export. An instance
+// whose name is in the snapshot and whose type is a value type is decoded from the snapshot instead of being
+// constructed by its instance method. It is useful for forked worker processes, or for reproducing exactly what
+// values a production container was wired with.
+func WithSeed(snapshot []byte) Option {
+	return func(o *options) {
+		o.seed = snapshot
+	}
+}
+
+func (c *container) Export() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	values := make(map[string]interface{})
+	for _, rm := range c.reflected {
+		for _, instance := range rm.instances {
+			value, ok := c.instanceByName[instance.name]
+			if !ok || !isValueType(instance.tp) {
+				continue
+			}
+			values[instance.name] = value
+		}
+	}
+	return json.MarshalIndent(values, "", "  ")
+}
+
+// applySeed replaces the instance methods of seeded value instances with the values decoded from the snapshot.
+func applySeed(snapshot []byte, rms []*reflectedModule) error {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(snapshot, &values); err != nil {
+		return fmt.Errorf("invalid seed snapshot: %s", err.Error())
+	}
+
+	for _, rm := range rms {
+		for _, instance := range rm.instances {
+			raw, ok := values[instance.name]
+			if !ok || !isValueType(instance.tp) {
+				continue
+			}
+			value := reflect.New(instance.tp)
+			if err := json.Unmarshal(raw, value.Interface()); err != nil {
+				return fmt.Errorf("invalid seed value %s.%s: %s", rm.name, instance.name, err.Error())
+			}
+			instance.params = nil
+			instance.paramsStruct = nil
+			instance.method = reflect.MakeFunc(
+				reflect.FuncOf(nil, []reflect.Type{instance.tp}, false),
+				func([]reflect.Value) []reflect.Value {
+					return []reflect.Value{value.Elem()}
+				})
+		}
+	}
+	return nil
+}
+
+// isValueType checks if a type only holds plain values, which could be exported and seeded. Pointers, interfaces,
+// functions and channels usually refer to live resources, so types containing them are excluded.
+func isValueType(t reflect.Type) bool {
+	return isValueTypeVisited(t, make(map[reflect.Type]bool))
+}
+
+func isValueTypeVisited(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] {
+		return true
+	}
+	visited[t] = true
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice, reflect.Array:
+		return isValueTypeVisited(t.Elem(), visited)
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && isValueTypeVisited(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" { // unexported fields are not encoded
+				return false
+			}
+			if !isValueTypeVisited(field.Type, visited) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Limits struct {
+	Max     int
+	Targets []string
+}
+
+type ConfigValueModule struct {
+	BaseModule
+}
+
+func (m *ConfigValueModule) Table() string {
+	return "table"
+}
+
+func (m *ConfigValueModule) Limits() Limits {
+	return Limits{Max: 3, Targets: []string{"a", "b"}}
+}
+
+func (m *ConfigValueModule) D1() D1 {
+	return &D1Impl{}
+}
+
+func TestExport(t *testing.T) {
+	c := CreateContainer(&ConfigValueModule{})
+
+	snapshot, err := c.(Introspector).Export()
+	if err != nil {
+		t.Fatalf("unexpected error after Export(): %s", err.Error())
+	}
+	expected := `{
+  "Limits": {
+    "Max": 3,
+    "Targets": [
+      "a",
+      "b"
+    ]
+  },
+  "Table": "table"
+}`
+	if string(snapshot) != expected {
+		t.Errorf("bad snapshot from Export(): got %s, expected %s", snapshot, expected)
+	}
+}
+
+func TestWithSeed(t *testing.T) {
+	snapshot := []byte(`{"Table": "seeded", "Limits": {"Max": 5}, "D1": {}}`)
+	c := CreateContainerWithOptions([]Module{&ConfigValueModule{}}, WithSeed(snapshot))
+
+	if table := c.InstanceByName("Table"); table != "seeded" {
+		t.Errorf("bad instance from InstanceByName(): got %v, expected %s", table, "seeded")
+	}
+	expectedLimits := Limits{Max: 5}
+	if limits := c.InstanceByName("Limits"); !reflect.DeepEqual(limits, expectedLimits) {
+		t.Errorf("bad instance from InstanceByName(): got %v, expected %v", limits, expectedLimits)
+	}
+	// non-value instances are always constructed
+	if d1 := c.InstanceByName("D1"); !reflect.DeepEqual(d1, &D1Impl{}) {
+		t.Errorf("bad instance from InstanceByName(): got %v, expected %v", d1, &D1Impl{})
+	}
+}
+
+func TestWithSeed_PanicOnInvalidValue(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected panic for CreateContainerWithOptions() on invalid seed value")
+		} else {
+			t.Log(r)
+		}
+	}()
+
+	CreateContainerWithOptions([]Module{&ConfigValueModule{}}, WithSeed([]byte(`{"Table": 1}`)))
+}
+
+func TestIsValueType(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected bool
+	}{
+		{"string", true},
+		{3, true},
+		{Limits{}, true},
+		{map[string][]int{}, true},
+		{map[int]string{}, false},
+		{&D1Impl{}, false},
+		{struct{ D D1 }{}, false},
+		{struct{ f func() }{}, false},
+	}
+	for _, test := range tests {
+		if actual := isValueType(reflect.TypeOf(test.value)); actual != test.expected {
+			t.Errorf("bad result of isValueType(%T): got %v, expected %v", test.value, actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package alice
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StopTimeouts bounds how long Container.Stop waits for each instance, so one hung Stopper doesn't eat the whole
+// shutdown window.
+type StopTimeouts struct {
+	// Default is the timeout of an instance without a specific one. Zero means the instance is only bounded by its
+	// share of the deadline.
+	Default time.Duration
+	// Instances are the timeouts keyed by instance names.
+	Instances map[string]time.Duration
+}
+
+// WithStopTimeouts returns an option which bounds each instance stopped by Container.Stop. If the context of Stop
+// has a deadline, the remaining time is sliced evenly among the instances still to be stopped, so the time left by an
+// instance stopping early goes to the later ones. An instance gets a context done at the earlier of its slice and its
+// timeout. If it doesn't return by then, Stop moves on to the next instance without waiting for it, and reports it
+// in a StopTimeoutError.
+func WithStopTimeouts(timeouts StopTimeouts) Option {
+	return func(o *options) {
+		o.stopTimeouts = &timeouts
+	}
+}
+
+// StopTimeoutError is one of the errors returned by Container.Stop, if some instances don't stop in time with
+// WithStopTimeouts.
+type StopTimeoutError struct {
+	// Instances are the names of the instances exceeding their timeouts, in stop order.
+	Instances []string
+}
+
+// Error returns the message listing the instances.
+func (e *StopTimeoutError) Error() string {
+	return fmt.Sprintf("instances exceeded their stop timeouts: %v", e.Instances)
+}
+
+// namedStopper is an instance to be stopped.
+type namedStopper struct {
+	name    string
+	stopper Stopper
+}
+
+// stopWithTimeouts stops the instances in order, bounding each of them by its timeout and its share of the deadline.
+func (c *container) stopWithTimeouts(ctx context.Context, stoppers []namedStopper) []error {
+	var errs []error
+	var exceeded []string
+	for i, s := range stoppers {
+		stopCtx, cancel := c.stopContext(ctx, s.name, len(stoppers)-i)
+		done, err := stopWithin(stopCtx, s.stopper)
+		cancel()
+		if !done {
+			exceeded = append(exceeded, s.name)
+			c.options.logger.Warn("alice: instance exceeded its stop timeout", "instance", s.name)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop instance %s: %w", s.name, err))
+		}
+	}
+	if len(exceeded) > 0 {
+		errs = append(errs, &StopTimeoutError{Instances: exceeded})
+	}
+	return errs
+}
+
+// stopContext returns the context to stop an instance, with remaining being the number of instances to be stopped,
+// including this one.
+func (c *container) stopContext(
+	ctx context.Context, name string, remaining int) (context.Context, context.CancelFunc) {
+	timeout, ok := c.options.stopTimeouts.Instances[name]
+	if !ok {
+		timeout = c.options.stopTimeouts.Default
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		share := time.Until(deadline) / time.Duration(remaining)
+		if timeout <= 0 || share < timeout {
+			return context.WithTimeout(ctx, share)
+		}
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// stopWithin stops an instance on another goroutine, and waits until it returns or the context is done. done is
+// false if the context is done first.
+func stopWithin(ctx context.Context, stopper Stopper) (done bool, err error) {
+	result := make(chan error, 1)
+	go func() {
+		result <- stopper.Stop(ctx)
+	}()
+	select {
+	case err := <-result:
+		return true, err
+	case <-ctx.Done():
+		select {
+		case err := <-result:
+			return true, err
+		default:
+			return false, nil
+		}
+	}
+}
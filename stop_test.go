@@ -0,0 +1,100 @@
+package alice
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type hungStopper struct {
+	release chan struct{}
+}
+
+func (s *hungStopper) Stop(ctx context.Context) error {
+	<-s.release
+	return nil
+}
+
+type deadlineStopper struct {
+	called    bool
+	remaining time.Duration
+	deadline  bool
+}
+
+func (s *deadlineStopper) Stop(ctx context.Context) error {
+	s.called = true
+	var deadline time.Time
+	deadline, s.deadline = ctx.Deadline()
+	s.remaining = time.Until(deadline)
+	return nil
+}
+
+func stopModules(hung *hungStopper, recorder *deadlineStopper) []Module {
+	return []Module{
+		NewModule("first").Provide("Recorder", func() *deadlineStopper { return recorder }).Build(),
+		NewModule("second").Provide("Hung", func() *hungStopper { return hung }).Build(),
+	}
+}
+
+func TestStop_InstanceTimeout(t *testing.T) {
+	hung := &hungStopper{release: make(chan struct{})}
+	defer close(hung.release)
+	recorder := &deadlineStopper{}
+	var buf bytes.Buffer
+	c := CreateContainerWithOptions(stopModules(hung, recorder), WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+		WithStopTimeouts(StopTimeouts{Instances: map[string]time.Duration{"Hung": 20 * time.Millisecond}}))
+
+	err := c.(Lifecycle).Stop(context.Background())
+	var timeoutErr *StopTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("bad error after Stop(): got %v, expected StopTimeoutError", err)
+	}
+	if expected := []string{"Hung"}; !reflect.DeepEqual(timeoutErr.Instances, expected) {
+		t.Errorf("bad instances after Stop(): got %v, expected %v", timeoutErr.Instances, expected)
+	}
+	if !recorder.called || recorder.deadline {
+		t.Errorf("bad stop after Stop(): got called %v with deadline %v, expected called without deadline",
+			recorder.called, recorder.deadline)
+	}
+	if !strings.Contains(buf.String(), `instance exceeded its stop timeout" instance=Hung`) {
+		t.Errorf("bad log after Stop(): got %s", buf.String())
+	}
+}
+
+func TestStop_DeadlineSliced(t *testing.T) {
+	hung := &hungStopper{release: make(chan struct{})}
+	defer close(hung.release)
+	recorder := &deadlineStopper{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := CreateContainerWithOptions(stopModules(hung, recorder), WithLogger(logger), WithStopTimeouts(StopTimeouts{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+	err := c.(Lifecycle).Stop(ctx)
+	var timeoutErr *StopTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("bad error after Stop(): got %v, expected StopTimeoutError", err)
+	}
+	if !recorder.called || !recorder.deadline || recorder.remaining < 100*time.Millisecond {
+		t.Errorf("bad stop after Stop(): got called %v with remaining %s, expected at least %s", recorder.called,
+			recorder.remaining, 100*time.Millisecond)
+	}
+}
+
+func TestStop_WithoutTimeouts(t *testing.T) {
+	recorder := &deadlineStopper{}
+	c := CreateContainer(NewModule("first").Provide("Recorder", func() *deadlineStopper { return recorder }).Build())
+	if err := c.(Lifecycle).Stop(context.Background()); err != nil {
+		t.Errorf("bad error after Stop(): got %v, expected nil", err)
+	}
+	if !recorder.called || recorder.deadline {
+		t.Errorf("bad stop after Stop(): got called %v with deadline %v, expected called without deadline",
+			recorder.called, recorder.deadline)
+	}
+}
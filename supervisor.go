@@ -0,0 +1,102 @@
+package alice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Runner is an optional interface an instance could implement to run in the failure domain of a Supervisor, e.g. a
+// server serving until the context is done.
+type Runner interface {
+	// Run runs the instance until the context is done or it fails.
+	Run(ctx context.Context) error
+}
+
+// ErrGroup is the interface shared by Supervisor and golang.org/x/sync/errgroup.Group, so code joining goroutines to
+// a group doesn't depend on which one it gets.
+type ErrGroup interface {
+	// Go calls the function in a new goroutine.
+	Go(f func() error)
+	// Wait blocks until all goroutines return, and returns the first error.
+	Wait() error
+}
+
+// Supervisor runs the Runner instances of a container and any goroutines joined by Go in one failure domain, like
+// errgroup.WithContext: the first error anywhere cancels the context of all of them. Wait then stops the container,
+// so the shutdown is coordinated.
+type Supervisor struct {
+	c      Container
+	parent context.Context
+	cancel context.CancelCauseFunc
+
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// NewSupervisor creates a supervisor of the container and runs the Runner instances in instantiation order,
+// constructing them if needed. The returned context is canceled when any goroutine returns an error or panics, or
+// when Wait returns. The container is expected to be started already.
+func NewSupervisor(ctx context.Context, c Container) (*Supervisor, context.Context) {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	s := &Supervisor{
+		c:      c,
+		parent: ctx,
+		cancel: cancel,
+	}
+	if introspector, ok := c.(Introspector); ok {
+		for _, ref := range introspector.Order() {
+			runner, ok := c.MustInstanceByName(ref.Name).(Runner)
+			if !ok {
+				continue
+			}
+			name := ref.Name
+			s.Go(func() error {
+				if err := runner.Run(runCtx); err != nil {
+					return fmt.Errorf("failed to run instance %s: %w", name, err)
+				}
+				return nil
+			})
+		}
+	}
+	return s, runCtx
+}
+
+// Go calls the function in a new goroutine. A panic is recovered as the error of the function.
+func (s *Supervisor) Go(f func() error) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := callRecovered(f); err != nil {
+			s.errOnce.Do(func() {
+				s.err = err
+				s.cancel(err)
+			})
+		}
+	}()
+}
+
+// Wait blocks until all goroutines return, then stops the container if it implements Lifecycle. The context of Stop
+// keeps the values of the one passed to NewSupervisor but not its cancellation, e.g. by a signal, so WithStopTimeouts
+// should bound the shutdown. It returns the first error of the goroutines, joined with the error of stopping the
+// container.
+func (s *Supervisor) Wait() error {
+	s.wg.Wait()
+	s.cancel(nil)
+	errs := []error{s.err}
+	if lc, ok := s.c.(Lifecycle); ok {
+		errs = append(errs, lc.Stop(context.WithoutCancel(s.parent)))
+	}
+	return joinErrors(errs)
+}
+
+// callRecovered calls the function, converting a panic to an error.
+func callRecovered(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("goroutine panicked: %w", recoveredError(r))
+		}
+	}()
+	return f()
+}
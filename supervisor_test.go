@@ -0,0 +1,93 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type supervisedServer struct {
+	log  *lifecycleLog
+	fail bool
+}
+
+func (s *supervisedServer) Run(ctx context.Context) error {
+	s.log.add("run")
+	if s.fail {
+		return errors.New("serve failed")
+	}
+	<-ctx.Done()
+	s.log.add("done")
+	return nil
+}
+
+func (s *supervisedServer) Stop(ctx context.Context) error {
+	s.log.add("stop")
+	return nil
+}
+
+func supervisedContainer(log *lifecycleLog, fail bool) Container {
+	return CreateContainer(NewModule("server").
+		Provide("Server", func() *supervisedServer { return &supervisedServer{log: log, fail: fail} }).
+		Build())
+}
+
+func TestSupervisor(t *testing.T) {
+	var _ ErrGroup = (*Supervisor)(nil)
+
+	log := &lifecycleLog{}
+	s, ctx := NewSupervisor(context.Background(), supervisedContainer(log, false))
+	s.Go(func() error {
+		return errors.New("worker failed")
+	})
+	<-ctx.Done()
+	err := s.Wait()
+	if err == nil || err.Error() != "worker failed" {
+		t.Errorf("bad error after Wait(): got %v, expected worker failed", err)
+	}
+	events := log.get()
+	if len(events) != 3 || events[0] != "run" || events[1] != "done" || events[2] != "stop" {
+		t.Errorf("bad events after Wait(): got %v, expected [run done stop]", events)
+	}
+}
+
+func TestSupervisor_RunnerFails(t *testing.T) {
+	log := &lifecycleLog{}
+	s, ctx := NewSupervisor(context.Background(), supervisedContainer(log, true))
+	s.Go(func() error {
+		<-ctx.Done()
+		return nil
+	})
+	err := s.Wait()
+	if err == nil || err.Error() != "failed to run instance Server: serve failed" {
+		t.Errorf("bad error after Wait(): got %v, expected failed to run instance Server: serve failed", err)
+	}
+	if cause := context.Cause(ctx); cause == nil || !strings.Contains(cause.Error(), "serve failed") {
+		t.Errorf("bad cause after Wait(): got %v, expected serve failed", cause)
+	}
+}
+
+func TestSupervisor_Panic(t *testing.T) {
+	s, _ := NewSupervisor(context.Background(), CreateContainer(&M1{}))
+	s.Go(func() error {
+		panic("boom")
+	})
+	if err := s.Wait(); err == nil || err.Error() != "goroutine panicked: boom" {
+		t.Errorf("bad error after Wait(): got %v, expected goroutine panicked: boom", err)
+	}
+}
+
+func TestSupervisor_Canceled(t *testing.T) {
+	log := &lifecycleLog{}
+	parent, cancel := context.WithCancel(context.Background())
+	s, _ := NewSupervisor(parent, supervisedContainer(log, false))
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := s.Wait(); err != nil {
+		t.Errorf("bad error after Wait(): got %v, expected nil", err)
+	}
+	if events := log.get(); len(events) != 3 || events[2] != "stop" {
+		t.Errorf("bad events after Wait(): got %v, expected [run done stop]", events)
+	}
+}
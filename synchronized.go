@@ -0,0 +1,53 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// markSynchronizedInstances marks the instances with the specified names synchronized. It returns error if any name
+// is not an instance of the module, or is not of function or interface type.
+func markSynchronizedInstances(moduleName string, instances []*instanceMethod, names []string) error {
+	for _, name := range names {
+		instance := findInstanceMethod(instances, name)
+		if instance == nil {
+			return fmt.Errorf("synchronized instance %s.%s is not defined", moduleName, name)
+		}
+		if err := checkSynchronizable(moduleName, name, instance.tp); err != nil {
+			return err
+		}
+		instance.synchronized = true
+	}
+	return nil
+}
+
+// checkSynchronizable returns error if an instance of type t could not be synchronized.
+func checkSynchronizable(moduleName string, name string, t reflect.Type) error {
+	if t.Kind() != reflect.Func && t.Kind() != reflect.Interface {
+		return fmt.Errorf("synchronized instance %s.%s has type %s, which is not a function or an interface",
+			moduleName, name, t)
+	}
+	return nil
+}
+
+// synchronizedInstance wraps an instance in a proxy calling it with a mutex held. The proxy factory could proxy the
+// instance type, as checked by checkProxies.
+func (c *container) synchronizedInstance(im *instanceMethod, instance interface{}) interface{} {
+	if instance == nil {
+		return nil
+	}
+	var mu sync.Mutex
+	proxy, ok := c.options.proxies().Proxy(im.tp, func() interface{} {
+		return instance
+	}, func(inv *Invocation) []interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return inv.Proceed(inv.Args)
+	})
+	if !ok {
+		panic(fmt.Errorf("synchronized instance %s of type %s could not be proxied by the proxy factory", im.name,
+			im.tp))
+	}
+	return proxy
+}
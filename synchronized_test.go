@@ -0,0 +1,118 @@
+package alice
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// unsafeCounter is not safe for concurrent use.
+type unsafeCounter struct {
+	n int
+}
+
+func (c *unsafeCounter) Incr() int {
+	n := c.n
+	for i := 0; i < 100; i++ {
+		_ = i * n
+	}
+	c.n = n + 1
+	return c.n
+}
+
+type SynchronizedModule1 struct {
+	BaseModule
+	counter *unsafeCounter
+}
+
+func (m *SynchronizedModule1) Incr() func() int {
+	return m.counter.Incr
+}
+
+func (m *SynchronizedModule1) Synchronized() []string {
+	return []string{"Incr"}
+}
+
+type invalidSynchronizedModule struct {
+	BaseModule
+}
+
+func (m *invalidSynchronizedModule) Counter() *unsafeCounter {
+	return &unsafeCounter{}
+}
+
+func (m *invalidSynchronizedModule) Synchronized() []string {
+	return []string{"Counter"}
+}
+
+func TestSynchronized(t *testing.T) {
+	counter := &unsafeCounter{}
+	c := CreateContainer(&SynchronizedModule1{counter: counter})
+	incr := c.InstanceByName("Incr").(func() int)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				incr()
+			}
+		}()
+	}
+	wg.Wait()
+	if counter.n != 1000 {
+		t.Errorf("bad count after concurrent calls: got %d, expected %d", counter.n, 1000)
+	}
+
+	infos := c.(Introspector).Instances()
+	if len(infos) != 1 || !infos[0].Synchronized {
+		t.Errorf("bad instances after CreateContainer(): got %v, expected a synchronized instance", infos)
+	}
+}
+
+func TestSynchronized_Interface(t *testing.T) {
+	factory := NewGeneratedProxyFactory()
+	factory.Register(greeterType, newGreeterProxy)
+	m := NewModule("greeter").
+		Provide("Greeter", func() Greeter { return greeterFunc(func(name string) string { return "hi " + name }) }).
+		Synchronize("Greeter").
+		Build()
+	c := CreateContainerWithOptions([]Module{m}, WithProxyFactory(factory))
+	greeter := c.Instance(greeterType).(Greeter)
+	if _, ok := greeter.(greeterProxy); !ok {
+		t.Errorf("bad instance after CreateContainer(): got %T, expected greeterProxy", greeter)
+	}
+	if s := greeter.Greet("alice"); s != "hi alice" {
+		t.Errorf("bad greeting after Greet(): got %q, expected %q", s, "hi alice")
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic when the interface could not be proxied")
+		}
+	}()
+	CreateContainer(m)
+}
+
+func TestSynchronized_Invalid(t *testing.T) {
+	if err := Validate(&invalidSynchronizedModule{}); err == nil {
+		t.Error("expected error for synchronized instance of concrete type")
+	}
+	built := NewModule("built").
+		Provide("Counter", func() *unsafeCounter { return &unsafeCounter{} }).
+		Synchronize("Counter").
+		Build()
+	if err := Validate(built); err == nil {
+		t.Error("expected error for synchronized instance of concrete type of built module")
+	}
+	if err := Validate(NewModule("built").Synchronize("Undefined").Build()); err == nil {
+		t.Error("expected error for undefined synchronized instance")
+	}
+	greeter := NewModule("greeter").
+		Provide("Greeter", func() Greeter { return greeterFunc(func(name string) string { return "hi " + name }) }).
+		Synchronize("Greeter").
+		Build()
+	if err := Validate(greeter); err == nil || !strings.Contains(err.Error(), "synchronized instance greeter.Greeter") {
+		t.Errorf("bad error after Validate() of synchronized interface without proxy factory: got %v", err)
+	}
+}
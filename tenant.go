@@ -0,0 +1,174 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// TenantScopes manages the child containers of a multi-tenant process, one per tenant. The tenant-scoped instances
+// are provided by the modules returned for each tenant, and could depend on every instance of the parent container,
+// which is shared by all tenants:
+//
+//	tenants := alice.NewTenantScopes(c, func(tenantID string) []alice.Module {
+//		return []alice.Module{&TenantModule{TenantID: tenantID}}
+//	}, alice.MemoIdleTTL(time.Hour))
+//	tc, err := tenants.Scope(ctx, "acme")
+//
+// The tenant containers are created on first use, and stopped when they are closed or evicted by the options. The
+// tenant modules must not define the names of the parent instances. It is safe for concurrent use.
+type TenantScopes struct {
+	modules func(tenantID string) []Module
+	// imported imports all instances of the parent container.
+	imported Module
+	memo     *Memo[string, *tenant]
+
+	mu sync.Mutex
+	// open contains the tenants whose containers are created or being created.
+	open map[string]*tenant
+}
+
+// tenant is the child container of a tenant.
+type tenant struct {
+	id       string
+	built    sync.Once
+	c        Container
+	err      error
+	closed   sync.Once
+	closeErr error
+}
+
+// ErrTenantNotFound is returned when a tenant to close has no container.
+var ErrTenantNotFound = errors.New("tenant is not found")
+
+// NewTenantScopes creates a manager of the tenant containers of the parent container. modules returns the modules of
+// a tenant. opts bound the tenant containers kept, like for a Memo, and the evicted ones are stopped. It panics if
+// the parent container doesn't implement Introspector, as the names of its instances are needed to import them.
+func NewTenantScopes(parent Container, modules func(tenantID string) []Module, opts ...MemoOption) *TenantScopes {
+	in, ok := parent.(Introspector)
+	if !ok {
+		panic(fmt.Errorf("container of type %T doesn't implement Introspector", parent))
+	}
+	var names []string
+	for _, info := range in.Instances() {
+		names = append(names, info.Name)
+	}
+
+	s := &TenantScopes{
+		modules:  modules,
+		imported: Import(parent, names...),
+		open:     make(map[string]*tenant),
+	}
+	s.memo = NewMemo(s.newTenant, func(_ string, t *tenant) {
+		s.closeTenant(context.Background(), t)
+	}, opts...)
+	return s
+}
+
+// Scope returns the container of a tenant, creating and starting it on first use. If it fails to be created or
+// started, it is stopped and the error is returned, and the next call tries again.
+func (s *TenantScopes) Scope(ctx context.Context, tenantID string) (Container, error) {
+	t := s.memo.Get(tenantID)
+	t.built.Do(func() {
+		t.c, t.err = s.build(ctx, tenantID)
+	})
+	if t.err != nil {
+		s.memo.Evict(tenantID)
+		return nil, t.err
+	}
+	return t.c, nil
+}
+
+// Close stops the container of a tenant, so the next Scope creates a new one. It returns ErrTenantNotFound if the
+// tenant has no container.
+func (s *TenantScopes) Close(ctx context.Context, tenantID string) error {
+	s.mu.Lock()
+	t, ok := s.open[tenantID]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("failed to close tenant %s: %w", tenantID, ErrTenantNotFound)
+	}
+	err := s.closeTenant(ctx, t)
+	s.memo.Evict(tenantID)
+	return err
+}
+
+// CloseAll stops the containers of all tenants.
+func (s *TenantScopes) CloseAll(ctx context.Context) error {
+	s.mu.Lock()
+	var tenants []*tenant
+	for _, t := range s.open {
+		tenants = append(tenants, t)
+	}
+	s.mu.Unlock()
+
+	var errs []error
+	for _, t := range tenants {
+		if err := s.closeTenant(ctx, t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	s.memo.Clear()
+	return joinErrors(errs)
+}
+
+// Tenants returns the sorted IDs of the tenants having containers.
+func (s *TenantScopes) Tenants() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	for id := range s.open {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// newTenant registers a tenant whose container is not created yet.
+func (s *TenantScopes) newTenant(tenantID string) *tenant {
+	t := &tenant{id: tenantID}
+	s.mu.Lock()
+	s.open[tenantID] = t
+	s.mu.Unlock()
+	return t
+}
+
+// build creates and starts the container of a tenant.
+func (s *TenantScopes) build(ctx context.Context, tenantID string) (Container, error) {
+	modules := append([]Module{s.imported}, s.modules(tenantID)...)
+	c, err := createContainerSafely(modules, nil)
+	if err == nil {
+		if err = c.Start(ctx); err != nil {
+			c.Stop(ctx)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container of tenant %s: %w", tenantID, err)
+	}
+	return c, nil
+}
+
+// closeTenant unregisters a tenant and stops its container once. It returns the error of stopping the container.
+func (s *TenantScopes) closeTenant(ctx context.Context, t *tenant) error {
+	s.mu.Lock()
+	if s.open[t.id] == t {
+		delete(s.open, t.id)
+	}
+	s.mu.Unlock()
+
+	t.closed.Do(func() {
+		// wait for the container being created, so it is not left running
+		t.built.Do(func() {
+			t.err = ErrScopeClosed
+		})
+		if t.c == nil {
+			return
+		}
+		if err := t.c.(Lifecycle).Stop(ctx); err != nil {
+			t.closeErr = fmt.Errorf("failed to stop container of tenant %s: %w", t.id, err)
+		}
+	})
+	return t.closeErr
+}
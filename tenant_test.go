@@ -0,0 +1,140 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type tenantModule struct {
+	BaseModule
+	TenantID string
+	DB       *lifecycleService `alice:"DB"`
+	log      *lifecycleLog
+}
+
+func (m *tenantModule) Service() *tenantService {
+	return &tenantService{lifecycleService{name: "Service " + m.TenantID, log: m.log}, m.DB}
+}
+
+type tenantService struct {
+	lifecycleService
+	db *lifecycleService
+}
+
+func newTenantScopes(log *lifecycleLog, opts ...MemoOption) *TenantScopes {
+	parent := CreateContainer(NewModule("db").
+		Provide("DB", func() *lifecycleService { return &lifecycleService{name: "DB", log: log} }).
+		Build())
+	return NewTenantScopes(parent, func(tenantID string) []Module {
+		return []Module{&tenantModule{TenantID: tenantID, log: log}}
+	}, opts...)
+}
+
+func TestTenantScopes(t *testing.T) {
+	ctx := context.Background()
+	log := &lifecycleLog{}
+	tenants := newTenantScopes(log)
+
+	acme, err := tenants.Scope(ctx, "acme")
+	if err != nil {
+		t.Fatalf("bad error after Scope(): got %v, expected nil", err)
+	}
+	again, _ := tenants.Scope(ctx, "acme")
+	if again != acme {
+		t.Errorf("bad container after Scope() again: got %v, expected %v", again, acme)
+	}
+	other, _ := tenants.Scope(ctx, "other")
+	if other == acme {
+		t.Error("expected a separate container for another tenant")
+	}
+
+	service := acme.InstanceByName("Service").(*tenantService)
+	if service.name != "Service acme" {
+		t.Errorf("bad tenant instance after InstanceByName(): got %v, expected %v", service.name, "Service acme")
+	}
+	shared := other.Instance(reflect.TypeOf(&tenantService{})).(*tenantService).db
+	if service.db != shared || acme.InstanceByName("DB") != shared {
+		t.Error("expected the parent instance shared by the tenants")
+	}
+	if ids := tenants.Tenants(); !reflect.DeepEqual(ids, []string{"acme", "other"}) {
+		t.Errorf("bad tenants after Scope(): got %v, expected %v", ids, []string{"acme", "other"})
+	}
+
+	if err := tenants.Close(ctx, "acme"); err != nil {
+		t.Errorf("bad error after Close(): got %v, expected nil", err)
+	}
+	if err := tenants.Close(ctx, "acme"); !errors.Is(err, ErrTenantNotFound) {
+		t.Errorf("bad error after Close() again: got %v, expected %v", err, ErrTenantNotFound)
+	}
+	if recreated, _ := tenants.Scope(ctx, "acme"); recreated == acme {
+		t.Error("expected a new container after Close()")
+	}
+	if err := tenants.CloseAll(ctx); err != nil {
+		t.Errorf("bad error after CloseAll(): got %v, expected nil", err)
+	}
+	if ids := tenants.Tenants(); len(ids) != 0 {
+		t.Errorf("bad tenants after CloseAll(): got %v, expected none", ids)
+	}
+
+	// the parent instance is started and stopped by the parent container only
+	expected := []string{
+		"start Service acme", "start Service other", "stop Service acme", "start Service acme",
+		"stop Service acme", "stop Service other",
+	}
+	events := log.get()
+	if len(events) != len(expected) {
+		t.Fatalf("bad events after CloseAll(): got %v, expected %v", events, expected)
+	}
+	// the tenants are closed by CloseAll in no particular order
+	if !reflect.DeepEqual(events[:4], expected[:4]) {
+		t.Errorf("bad events after CloseAll(): got %v, expected %v", events, expected)
+	}
+}
+
+func TestTenantScopes_Eviction(t *testing.T) {
+	ctx := context.Background()
+	log := &lifecycleLog{}
+	tenants := newTenantScopes(log, MemoMaxEntries(1))
+
+	tenants.Scope(ctx, "acme")
+	tenants.Scope(ctx, "other")
+	if ids := tenants.Tenants(); !reflect.DeepEqual(ids, []string{"other"}) {
+		t.Errorf("bad tenants after eviction: got %v, expected %v", ids, []string{"other"})
+	}
+	expected := []string{"start Service acme", "stop Service acme", "start Service other"}
+	if events := log.get(); !reflect.DeepEqual(events, expected) {
+		t.Errorf("bad events after eviction: got %v, expected %v", events, expected)
+	}
+}
+
+func TestTenantScopes_Error(t *testing.T) {
+	parent := CreateContainer(&M1{})
+	calls := 0
+	tenants := NewTenantScopes(parent, func(tenantID string) []Module {
+		calls++
+		// D1 is defined by the parent already
+		return []Module{NewModule("tenant").Provide("D1", func() D1 { return &D1Impl{} }).Build()}
+	})
+
+	if _, err := tenants.Scope(context.Background(), "acme"); err == nil {
+		t.Error("expected error for duplicated name")
+	}
+	if ids := tenants.Tenants(); len(ids) != 0 {
+		t.Errorf("bad tenants after Scope() failure: got %v, expected none", ids)
+	}
+	tenants.Scope(context.Background(), "acme")
+	if calls != 2 {
+		t.Errorf("bad calls after Scope() again: got %v, expected %v", calls, 2)
+	}
+}
+
+func TestTenantScopes_NilableParentInstance(t *testing.T) {
+	tenants := NewTenantScopes(CreateContainer(&nilableModule{}), func(tenantID string) []Module {
+		return []Module{NewModule("tenant").Provide("Tenant", func() string { return tenantID }).Build()}
+	})
+	if _, err := tenants.Scope(context.Background(), "acme"); err != nil {
+		t.Errorf("bad error after Scope(): got %v, expected nil", err)
+	}
+}
@@ -0,0 +1,88 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Provider is an instance provider whose constructor types are checked at compile time. It is created by Singleton
+// and its variants, and registered by TypedModule.Provide. Reflection is only used for wiring.
+type Provider struct {
+	name        string
+	constructor interface{}
+}
+
+// Named returns a copy of the provider with the specified instance name.
+func (p Provider) Named(name string) Provider {
+	p.name = name
+	return p
+}
+
+// Singleton returns a provider of the instance constructed by a constructor without dependencies. The instance name
+// is the name of type T, or its element type if T is a pointer, unless it is changed by Provider.Named. The type
+// arguments of a generic type are named without package paths, e.g. "Cache[User]".
+func Singleton[T any](constructor func() T) Provider {
+	return newProvider[T](constructor)
+}
+
+// Singleton1 returns a provider like Singleton, whose constructor depends on an instance of type A.
+func Singleton1[A, T any](constructor func(A) T) Provider {
+	return newProvider[T](constructor)
+}
+
+// Singleton2 returns a provider like Singleton, whose constructor depends on instances of types A and B.
+func Singleton2[A, B, T any](constructor func(A, B) T) Provider {
+	return newProvider[T](constructor)
+}
+
+// Singleton3 returns a provider like Singleton, whose constructor depends on instances of types A, B and C.
+func Singleton3[A, B, C, T any](constructor func(A, B, C) T) Provider {
+	return newProvider[T](constructor)
+}
+
+// newProvider creates a provider named after type T.
+func newProvider[T any](constructor interface{}) Provider {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return Provider{
+		name:        shortTypeName(t),
+		constructor: constructor,
+	}
+}
+
+// TypedModule builds a module from providers whose types are checked at compile time.
+//
+//	m := alice.NewTypedModule("users").
+//		Provide(
+//			alice.Singleton1(NewUserService), // func NewUserService(store *UserStore) *UserService
+//			alice.Singleton(NewCache).Named("UserCache"),
+//		).
+//		Build()
+type TypedModule struct {
+	b *ModuleBuilder
+}
+
+// NewTypedModule creates a typed module builder with the specified name.
+func NewTypedModule(name string) *TypedModule {
+	return &TypedModule{b: NewModule(name)}
+}
+
+// Provide registers the providers. The parameters of their constructors are dependencies associated by type.
+func (m *TypedModule) Provide(providers ...Provider) *TypedModule {
+	for _, p := range providers {
+		if p.name == "" {
+			m.b.setError(fmt.Errorf("provider %T of module %s has no instance name", p.constructor, m.b.m.name))
+			continue
+		}
+		m.b.Provide(p.name, p.constructor)
+	}
+	return m
+}
+
+// Build returns the module. If any provider is invalid, e.g. it has an empty name, the error is reported when the
+// module is used to create a container.
+func (m *TypedModule) Build() Module {
+	return m.b.Build()
+}
@@ -0,0 +1,54 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+type typedStore struct {
+	addr string
+}
+
+type typedService struct {
+	store *typedStore
+	d1    D1
+}
+
+func newTypedStore() *typedStore {
+	return &typedStore{addr: "localhost"}
+}
+
+func newTypedService(store *typedStore, d1 D1) *typedService {
+	return &typedService{store: store, d1: d1}
+}
+
+func TestTypedModule(t *testing.T) {
+	storage := NewTypedModule("storage").
+		Provide(Singleton(newTypedStore)).
+		Build()
+	m := NewTypedModule("typed").
+		Provide(
+			Singleton2(newTypedService),
+			Singleton(func() D3 { return &D3Impl{} }).Named("MyD3"),
+		).
+		Build()
+	c := CreateContainer(&M1{}, storage, m)
+
+	store := c.InstanceByName("typedStore").(*typedStore)
+	service := c.Instance(reflect.TypeOf(&typedService{})).(*typedService)
+	if service.store != store || service.d1 == nil {
+		t.Errorf("bad instance after Instance(): got %+v", service)
+	}
+	if _, ok := c.InstanceByName("MyD3").(D3); !ok {
+		t.Error("bad named instance after InstanceByName(): expected D3")
+	}
+}
+
+func TestTypedModule_NoName(t *testing.T) {
+	m := NewTypedModule("typed").
+		Provide(Singleton(func() interface{} { return 1 })).
+		Build()
+	if err := Validate(m); err == nil {
+		t.Error("expected error for provider without instance name")
+	}
+}
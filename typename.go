@@ -0,0 +1,42 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// typeNameIndex maps the canonical names of the types known from the graph to the types, so instances could be
+// retrieved by tooling which only has string identifiers. The known types are the ones declared by instance methods,
+// and the interfaces depended on by type.
+type typeNameIndex map[string]reflect.Type
+
+// newTypeNameIndex creates an index of the types known from the modules and the interface index.
+func newTypeNameIndex(rms []*reflectedModule, interfaces interfaceIndex) typeNameIndex {
+	index := make(typeNameIndex)
+	for _, rm := range rms {
+		for _, instance := range rm.instances {
+			index[typeName(instance.tp)] = instance.tp
+		}
+	}
+	for t := range interfaces {
+		index[typeName(t)] = t
+	}
+	return index
+}
+
+func (c *container) InstanceByTypeName(name string) (interface{}, error) {
+	t, ok := c.typeNames[name]
+	if !ok {
+		return nil, fmt.Errorf("instance type %s %w", name, ErrNotFound)
+	}
+	return c.Resolve(t)
+}
+
+func (f *fork) InstanceByTypeName(name string) (interface{}, error) {
+	for t, instance := range f.byType {
+		if typeName(t) == name {
+			return instance, nil
+		}
+	}
+	return f.extendedContainer.InstanceByTypeName(name)
+}
@@ -0,0 +1,37 @@
+package alice
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInstanceByTypeName(t *testing.T) {
+	c := CreateContainer(&M1{}, &M4{}).(extendedContainer)
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	name := d1Type.PkgPath() + ".D1"
+
+	instance, err := c.InstanceByTypeName(name)
+	if err != nil {
+		t.Fatalf("bad error after InstanceByTypeName(): got %v, expected nil", err)
+	}
+	if expected := c.Instance(d1Type); instance != expected {
+		t.Errorf("bad instance after InstanceByTypeName(): got %v, expected %v", instance, expected)
+	}
+
+	if _, err := c.InstanceByTypeName("D1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("bad error after InstanceByTypeName() with short name: got %v, expected %v", err, ErrNotFound)
+	}
+}
+
+func TestInstanceByTypeName_Fork(t *testing.T) {
+	c := CreateContainer(&M1{}, &M4{})
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	override := &D1Impl{}
+	f := c.(Scoper).Fork(OverrideType(d1Type, override)).(extendedContainer)
+
+	instance, err := f.InstanceByTypeName(d1Type.PkgPath() + ".D1")
+	if err != nil || instance != override {
+		t.Errorf("bad instance after InstanceByTypeName(): got %v, %v, expected %v", instance, err, override)
+	}
+}
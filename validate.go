@@ -0,0 +1,76 @@
+package alice
+
+import (
+	"context"
+	"fmt"
+)
+
+// Validate checks if the modules could be used to create a container, without constructing any instance. It reports
+// all problems found, such as invalid modules, missing or duplicated providers and cyclic dependencies, as Errors if
+// there is more than one. If the graph is valid, the modules implementing VerifiedModule are verified, and their
+// failures are reported as well. Only the instances retrieved by their Verify methods are constructed.
+func Validate(modules ...Module) error {
+	return ValidateWithOptions(modules)
+}
+
+// ValidateWithOptions checks the modules like Validate, with the options the container would be created with.
+func ValidateWithOptions(modules []Module, opts ...Option) error {
+	c := &container{
+		modules: modules,
+		options: newOptions(opts...),
+	}
+	rms, err := c.plan()
+	if err != nil {
+		return c.options.formatError(err)
+	}
+	return c.options.formatError(verifyModules(rms, modules, opts))
+}
+
+// verifyModules verifies the modules implementing VerifiedModule in instantiation order, with a lazy container of
+// copies of all modules, and returns all failures. The container is stopped afterwards.
+func verifyModules(rms []*reflectedModule, modules []Module, opts []Option) error {
+	var verified []*reflectedModule
+	for _, rm := range rms {
+		if _, ok := rm.m.(VerifiedModule); ok {
+			verified = append(verified, rm)
+		}
+	}
+	if len(verified) == 0 {
+		return nil
+	}
+
+	copies := make([]Module, len(modules))
+	for i, m := range modules {
+		copied, err := copyModule(m)
+		if err != nil {
+			// the targets of the built module are set, as it couldn't be copied
+			copied = m
+		}
+		copies[i] = copied
+	}
+	c, err := createContainerSafely(copies, append(append([]Option{}, opts...), WithLazy(), withForeground()))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, rm := range verified {
+		if err := verifyModule(rm.m.(VerifiedModule), c); err != nil {
+			errs = append(errs, fmt.Errorf("failed to verify module %s: %w", rm.name, err))
+		}
+	}
+	if err := c.Stop(context.Background()); err != nil {
+		errs = append(errs, err)
+	}
+	return joinErrors(errs)
+}
+
+// verifyModule calls Verify of the module, converting the panic to an error, e.g. if an instance retrieved from the
+// container fails to be constructed.
+func verifyModule(m VerifiedModule, c Container) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(r)
+		}
+	}()
+	return m.Verify(c)
+}
@@ -0,0 +1,175 @@
+package alice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	if err := Validate(&M1{}, &M2{}, &M3{}, &M4{}, &M5{}); err != nil {
+		t.Errorf("unexpected error after Validate(): %s", err.Error())
+	}
+}
+
+func TestValidate_MultipleErrors(t *testing.T) {
+	// D1 is duplicated, D3 and D4 of M2 are not found
+	err := Validate(&M1{}, &M1Duplicated{}, &M2{}, &M3{})
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("bad error after Validate(): got %v, expected Errors", err)
+	}
+	if len(errs) != 3 {
+		t.Errorf("bad number of errors after Validate(): got %d, expected %d", len(errs), 3)
+	}
+	t.Log(err.Error())
+}
+
+func TestValidate_InvalidModules(t *testing.T) {
+	nonStructModule := nonStructModule("module")
+	err := Validate(&nonStructModule, &invalidMethodModule2{})
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("bad error after Validate(): got %v, expected 2 errors", err)
+	}
+}
+
+func TestValidate_Cycle(t *testing.T) {
+	if err := Validate(&SelfDependModule{}); err == nil {
+		t.Error("expect error after Validate() with cycle")
+	}
+}
+
+func TestValidate_CycleWithOtherErrors(t *testing.T) {
+	// the cycle is reported with the reflection error and the missing dependency
+	nonStructModule := nonStructModule("module")
+	err := Validate(&SelfDependModule{}, &nonStructModule, &M3{})
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 3 || !strings.Contains(err.Error(), "cyclic dependencies") {
+		t.Errorf("bad error after Validate(): got %v, expected 3 errors including the cycle", err)
+	}
+}
+
+func TestValidateWithOptions(t *testing.T) {
+	if err := ValidateWithOptions([]Module{&M3{}, &ModuleWithD5Impl1{}}, WithStrict()); err == nil {
+		t.Error("expect error after ValidateWithOptions() in strict mode")
+	}
+}
+
+type verifiedModule struct {
+	BaseModule
+	required []string
+	heavy    int
+}
+
+func (m *verifiedModule) Settings() map[string]string {
+	return map[string]string{"url": "localhost"}
+}
+
+func (m *verifiedModule) Heavy() D5 {
+	m.heavy++
+	return &D5Impl{}
+}
+
+func (m *verifiedModule) Verify(c Container) error {
+	settings := c.InstanceByName("Settings").(map[string]string)
+	var errs []error
+	for _, key := range m.required {
+		if _, ok := settings[key]; !ok {
+			errs = append(errs, fmt.Errorf("setting %s is missing", key))
+		}
+	}
+	return joinErrors(errs)
+}
+
+func TestValidate_Verify(t *testing.T) {
+	m := &verifiedModule{required: []string{"url"}}
+	if err := Validate(m); err != nil {
+		t.Errorf("unexpected error after Validate(): %s", err.Error())
+	}
+	if m.heavy != 0 {
+		t.Errorf("bad constructions after Validate(): got %d, expected %d", m.heavy, 0)
+	}
+
+	m = &verifiedModule{required: []string{"url", "user", "password"}}
+	err := Validate(m)
+	if err == nil || !strings.Contains(err.Error(), "failed to verify module verifiedModule") {
+		t.Fatalf("bad error after Validate(): got %v, expected the failures of verifiedModule", err)
+	}
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Errorf("bad number of failures after Validate(): got %v, expected %d", errs, 2)
+	}
+	t.Log(err.Error())
+}
+
+type verifiedSummary struct {
+	stopped bool
+}
+
+func (s *verifiedSummary) Stop(ctx context.Context) error {
+	s.stopped = true
+	return nil
+}
+
+// verifiedState is shared by the copies of verifiedConsumerModule.
+type verifiedState struct {
+	summary    *verifiedSummary
+	background int
+}
+
+type verifiedConsumerModule struct {
+	BaseModule
+	Settings map[string]string `alice:"Settings"`
+	state    *verifiedState
+}
+
+func (m *verifiedConsumerModule) Summary() *verifiedSummary {
+	m.state.summary = &verifiedSummary{}
+	return m.state.summary
+}
+
+func (m *verifiedConsumerModule) Report() string {
+	m.state.background++
+	return "report"
+}
+
+func (m *verifiedConsumerModule) BackgroundInstances() []string {
+	return []string{"Report"}
+}
+
+func (m *verifiedConsumerModule) Verify(c Container) error {
+	c.InstanceByName("Summary")
+	return nil
+}
+
+func TestValidate_VerifyIsolated(t *testing.T) {
+	m := &verifiedConsumerModule{state: &verifiedState{}}
+	if err := Validate(&verifiedModule{}, m); err != nil {
+		t.Fatalf("unexpected error after Validate(): %s", err.Error())
+	}
+	if m.Settings != nil {
+		t.Errorf("bad dependency of the validated module after Validate(): got %v, expected nil", m.Settings)
+	}
+	if m.state.summary == nil || !m.state.summary.stopped {
+		t.Errorf("bad instance retrieved by Verify() after Validate(): got %+v, expected it stopped", m.state.summary)
+	}
+	// the background instance would be constructed by now if it was started
+	time.Sleep(10 * time.Millisecond)
+	if m.state.background != 0 {
+		t.Errorf("bad constructions of background instance after Validate(): got %d, expected %d",
+			m.state.background, 0)
+	}
+}
+
+func TestValidate_VerifySkippedForInvalidGraph(t *testing.T) {
+	m := &verifiedModule{required: []string{"user"}}
+	// D1 is missing, so the module is not verified
+	err := Validate(m, &M4{})
+	if err == nil || strings.Contains(err.Error(), "failed to verify") {
+		t.Errorf("bad error after Validate(): got %v, expected the graph error only", err)
+	}
+}
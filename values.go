@@ -0,0 +1,47 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Values creates a module providing plain values, such as configurations, as named instances. Modules could depend
+// on them by name like any other instance, and missing values are reported during graph construction.
+//
+//	c := alice.CreateContainer(
+//		alice.Values("config", map[string]interface{}{
+//			"Retries": 3,
+//			"Table":   "example_table",
+//		}),
+//		&ClientModule{}, // Retries int `alice:"Retries"`
+//	)
+func Values(name string, values map[string]interface{}) Module {
+	b := NewModule(name)
+
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values[key]
+		if value == nil {
+			b.setError(fmt.Errorf("value %s.%s is nil", name, key))
+			continue
+		}
+		v := reflect.ValueOf(value)
+		constructor := reflect.MakeFunc(
+			reflect.FuncOf(nil, []reflect.Type{v.Type()}, false),
+			func([]reflect.Value) []reflect.Value {
+				return []reflect.Value{v}
+			})
+		b.m.providers = append(b.m.providers, &builtProvider{
+			name:        key,
+			constructor: constructor,
+		})
+	}
+
+	return b.Build()
+}
@@ -0,0 +1,47 @@
+package alice
+
+import (
+	"testing"
+)
+
+type ValueConsumerModule struct {
+	BaseModule
+	Retries int    `alice:"Retries"`
+	Table   string `alice:"Table"`
+}
+
+func TestValues(t *testing.T) {
+	m := &ValueConsumerModule{}
+	c := CreateContainer(Values("config", map[string]interface{}{
+		"Retries": 3,
+		"Table":   "example_table",
+	}), m)
+
+	if m.Retries != 3 {
+		t.Errorf("bad Retries after CreateContainer(): got %d, expected %d", m.Retries, 3)
+	}
+	if m.Table != "example_table" {
+		t.Errorf("bad Table after CreateContainer(): got %s, expected %s", m.Table, "example_table")
+	}
+	if table := c.InstanceByName("Table"); table != "example_table" {
+		t.Errorf("bad instance from InstanceByName(): got %v, expected %s", table, "example_table")
+	}
+}
+
+func TestValues_MissingValue(t *testing.T) {
+	rm1, _ := reflectModule(Values("config", map[string]interface{}{"Retries": 3}))
+	rm2, _ := reflectModule(&ValueConsumerModule{})
+	_, err := createGraph(rm1, rm2)
+	if err == nil {
+		t.Error("expect error after createGraph() of missing value")
+	}
+	t.Log(err.Error())
+}
+
+func TestValues_NilValue(t *testing.T) {
+	_, err := reflectModule(Values("config", map[string]interface{}{"Retries": nil}))
+	if err == nil {
+		t.Error("expect error after reflectModule() of nil value")
+	}
+	t.Log(err.Error())
+}
@@ -0,0 +1,69 @@
+package alice
+
+import (
+	"reflect"
+	"testing"
+)
+
+type d1Internal struct{}
+
+func (d *d1Internal) D1() {}
+
+type ViewedModule1 struct {
+	BaseModule
+}
+
+func (m *ViewedModule1) D1() *d1Internal {
+	return &d1Internal{}
+}
+
+func (m *ViewedModule1) Views() map[string]reflect.Type {
+	return map[string]reflect.Type{
+		"D1": reflect.TypeOf((*D1)(nil)).Elem(),
+	}
+}
+
+type invalidViewedModule struct {
+	BaseModule
+}
+
+func (m *invalidViewedModule) D1() *d1Internal {
+	return &d1Internal{}
+}
+
+func (m *invalidViewedModule) Views() map[string]reflect.Type {
+	return map[string]reflect.Type{
+		"D1": reflect.TypeOf((*D2)(nil)).Elem(),
+	}
+}
+
+func TestViews(t *testing.T) {
+	d1Type := reflect.TypeOf((*D1)(nil)).Elem()
+	c := CreateContainerWithOptions([]Module{&ViewedModule1{}}, WithStrict())
+	if _, ok := c.Instance(d1Type).(*d1Internal); !ok {
+		t.Errorf("bad instance of view in strict mode: got %v, expected *d1Internal", c.Instance(d1Type))
+	}
+	if tp := c.(Introspector).Instances()[0].Type; tp != d1Type {
+		t.Errorf("bad type of viewed instance: got %v, expected %v", tp, d1Type)
+	}
+
+	built := NewModule("built").
+		Provide("D1", func() *d1Internal { return &d1Internal{} }).
+		As("D1", d1Type).
+		Build()
+	c = CreateContainerWithOptions([]Module{built}, WithStrict())
+	if _, ok := c.Instance(d1Type).(*d1Internal); !ok {
+		t.Errorf("bad instance of view of built module: got %v, expected *d1Internal", c.Instance(d1Type))
+	}
+
+	if err := Validate(&invalidViewedModule{}); err == nil {
+		t.Error("expected error for unassignable view")
+	}
+	invalid := NewModule("invalid").
+		Provide("D1", func() *d1Internal { return &d1Internal{} }).
+		As("D1", reflect.TypeOf((*D2)(nil)).Elem()).
+		Build()
+	if err := Validate(invalid); err == nil {
+		t.Error("expected error for unassignable view of built module")
+	}
+}
@@ -0,0 +1,100 @@
+package alice
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+func (c *container) Without(moduleTypes ...reflect.Type) (Container, error) {
+	excluded := make(map[reflect.Type]bool)
+	for _, t := range moduleTypes {
+		if t.Kind() != reflect.Ptr {
+			t = reflect.PtrTo(t)
+		}
+		excluded[t] = true
+	}
+
+	var modules []Module
+	found := make(map[reflect.Type]bool)
+	for _, m := range c.modules {
+		t := modulePtrType(m)
+		if excluded[t] {
+			found[t] = true
+			continue
+		}
+		copied, err := copyModule(m)
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, copied)
+	}
+	for t := range excluded {
+		if !found[t] {
+			return nil, fmt.Errorf("module type %s is not in the container", t.Elem().Name())
+		}
+	}
+
+	nc := &container{
+		modules: modules,
+		options: c.options,
+	}
+	// the plan of the trimmed container differs from the pinned one by design, and is not printed
+	nc.options.pinnedPlan = nil
+	nc.options.planOutput = nil
+	start := time.Now()
+	orderedRms, err := nc.plan()
+	if err != nil {
+		return nil, err
+	}
+	if err := nc.populateSafely(start, orderedRms); err != nil {
+		return nil, err
+	}
+	return nc, nil
+}
+
+// populateSafely instantiates the planned modules, converting the panic to an error.
+func (c *container) populateSafely(start time.Time, orderedRms []*reflectedModule) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(r)
+		}
+	}()
+	c.populatePlanned(start, orderedRms, nil)
+	return nil
+}
+
+// copyModule returns a shallow copy of a module struct, so dependencies injected into the copy don't overwrite the
+// fields of the original module. Built modules are copied with their providers. It returns error for a built module
+// declaring dependency targets, as its constructors read the targets of this container, which can't be copied.
+func copyModule(m Module) (Module, error) {
+	if bm, ok := m.(*builtModule); ok {
+		return bm.copy()
+	}
+	if _, ok := m.(*funcModule); ok {
+		// the built module has no dependency targets
+		return m, nil
+	}
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return m, nil
+	}
+	copied := reflect.New(v.Elem().Type())
+	copied.Elem().Set(v.Elem())
+	return copied.Interface().(Module), nil
+}
+
+// copy returns a copy of the built module with copies of its providers.
+func (m *builtModule) copy() (*builtModule, error) {
+	if len(m.namedDepends) > 0 || len(m.typedDepends) > 0 || len(m.listDepends) > 0 {
+		return nil, fmt.Errorf("built module %s declares dependency targets shared with the container, "+
+			"declare the dependencies as constructor parameters instead", m.name)
+	}
+	copied := *m
+	copied.providers = make([]*builtProvider, len(m.providers))
+	for i, p := range m.providers {
+		provider := *p
+		copied.providers[i] = &provider
+	}
+	return &copied, nil
+}
@@ -0,0 +1,99 @@
+package alice
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithout(t *testing.T) {
+	m1 := &M1{}
+	m4 := &M4{}
+	c := CreateContainer(m1, m4, &M5{})
+	d1 := m4.D1
+
+	trimmed, err := c.(Rebuilder).Without(reflect.TypeOf(M5{}))
+	if err != nil {
+		t.Fatalf("bad error after Without(): got %v, expected nil", err)
+	}
+	if len(trimmed.(Introspector).Instances()) != 4 {
+		t.Errorf("bad instances after Without(): got %v, expected 4 instances", trimmed.(Introspector).Instances())
+	}
+	if trimmed.Instance(reflect.TypeOf((*D1)(nil)).Elem()) == nil {
+		t.Error("bad instance after Without(): got nil, expected D1")
+	}
+	if m4.D1 != d1 {
+		t.Errorf("bad field of the original module after Without(): got %v, expected %v", m4.D1, d1)
+	}
+
+	if _, err := c.(Rebuilder).Without(reflect.TypeOf(&M1{})); err == nil {
+		t.Error("expected error for removing a module depended on")
+	}
+	if _, err := c.(Rebuilder).Without(reflect.TypeOf(&M2{})); err == nil {
+		t.Error("expected error for removing a module not in the container")
+	}
+}
+
+func TestWithout_BuiltModules(t *testing.T) {
+	calls := 0
+	funcModule := ModuleFunc(func() map[string]interface{} {
+		calls++
+		return map[string]interface{}{"Answer": 42}
+	})
+	values := Values("values", map[string]interface{}{"Name": "alice"})
+	c := CreateContainer(funcModule, values, &M5{})
+	calls = 0
+
+	trimmed, err := c.(Rebuilder).Without(reflect.TypeOf(M5{}))
+	if err != nil {
+		t.Fatalf("bad error after Without(): got %v, expected nil", err)
+	}
+	if calls != 1 {
+		t.Errorf("bad calls of the module function after Without(): got %d, expected 1", calls)
+	}
+	if trimmed.InstanceByName("Name") != "alice" {
+		t.Errorf("bad instance after Without(): got %v, expected alice", trimmed.InstanceByName("Name"))
+	}
+
+	var d1 D1
+	required := NewModule("required").Require(&d1).Provide("D1User", func() string { return "user" }).Build()
+	c = CreateContainer(&M1{}, required, &M5{})
+	if _, err := c.(Rebuilder).Without(reflect.TypeOf(M5{})); err == nil {
+		t.Error("expected error for copying a built module with dependency targets")
+	}
+}
+
+type switchableModule struct {
+	BaseModule
+	fail bool
+}
+
+func (m *switchableModule) Switchable() string {
+	if m.fail {
+		panic("switchable failed")
+	}
+	return "switchable"
+}
+
+func TestWithout_PinnedPlanAndFailure(t *testing.T) {
+	modules := []Module{&M1{}, &M4{}, &M5{}}
+	planned, err := Plan(modules)
+	if err != nil {
+		t.Fatalf("bad error after Plan(): got %v, expected nil", err)
+	}
+	var pinned bytes.Buffer
+	WritePlan(&pinned, planned)
+	c := CreateContainerWithOptions(modules, WithPinnedPlan(pinned.Bytes()))
+	if _, err := c.(Rebuilder).Without(reflect.TypeOf(M5{})); err != nil {
+		t.Errorf("bad error after Without() with pinned plan: got %v, expected nil", err)
+	}
+
+	m := &switchableModule{}
+	c = CreateContainer(m, &M5{})
+	m.fail = true
+	if _, err := c.(Rebuilder).Without(reflect.TypeOf(M5{})); err == nil ||
+		!strings.Contains(err.Error(), "switchable failed") {
+		t.Errorf("bad error after Without() with failing instance: got %v, expected switchable failed", err)
+	}
+}